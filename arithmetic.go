@@ -2,7 +2,6 @@ package float16
 
 import (
 	"fmt"
-	"math"
 )
 
 // Global arithmetic settings
@@ -31,6 +30,14 @@ func Add(a, b Float16) Float16 {
 
 // AddWithMode performs addition with specified arithmetic and rounding modes
 func AddWithMode(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Float16, error) {
+	// Handle NaN cases: the first NaN operand propagates, quieted
+	if a.IsNaN() || b.IsNaN() {
+		if mode == ModeExactArithmetic {
+			return propagateNaN(a, b), nanOperandError("add", a, b)
+		}
+		return propagateNaN(a, b), nil
+	}
+
 	// Handle special cases first for performance
 	if a.IsZero() {
 		return b, nil
@@ -39,19 +46,6 @@ func AddWithMode(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Floa
 		return a, nil
 	}
 
-	// Handle NaN cases
-	if a.IsNaN() || b.IsNaN() {
-		if mode == ModeExactArithmetic {
-			return 0, &Float16Error{
-				Op:   "add",
-				Msg:  "NaN operand in exact mode",
-				Code: ErrNaN,
-			}
-		}
-		// Return a quiet NaN
-		return QuietNaN, nil
-	}
-
 	// Handle infinity cases
 	if a.IsInf(0) || b.IsInf(0) {
 		if a.IsInf(1) && b.IsInf(-1) {
@@ -104,8 +98,15 @@ func Sub(a, b Float16) Float16 {
 
 // SubWithMode performs subtraction with specified arithmetic and rounding modes
 func SubWithMode(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Float16, error) {
-	// Subtraction is addition with negated second operand
-	return AddWithMode(a, b.Neg(), mode, rounding)
+	// Subtraction is addition with negated second operand; a NaN keeps its sign
+	if !b.IsNaN() {
+		b = b.Neg()
+	}
+	result, err := AddWithMode(a, b, mode, rounding)
+	if e, ok := err.(*Float16Error); ok {
+		e.Op = "sub"
+	}
+	return result, err
 }
 
 // Mul performs multiplication of two Float16 values
@@ -116,6 +117,14 @@ func Mul(a, b Float16) Float16 {
 
 // MulWithMode performs multiplication with specified arithmetic and rounding modes
 func MulWithMode(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Float16, error) {
+	// Handle NaN cases before zeros, so that 0 × NaN is NaN
+	if a.IsNaN() || b.IsNaN() {
+		if mode == ModeExactArithmetic {
+			return propagateNaN(a, b), nanOperandError("mul", a, b)
+		}
+		return propagateNaN(a, b), nil
+	}
+
 	// Handle special cases
 	// Check for zero times infinity cases first
 	aIsZero := a.IsZero()
@@ -143,18 +152,6 @@ func MulWithMode(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Floa
 		return PositiveZero, nil
 	}
 
-	// Handle NaN cases
-	if a.IsNaN() || b.IsNaN() {
-		if mode == ModeExactArithmetic {
-			return 0, &Float16Error{
-				Op:   "mul",
-				Msg:  "NaN operand in exact mode",
-				Code: ErrNaN,
-			}
-		}
-		return QuietNaN, nil
-	}
-
 	// Handle infinity cases
 	if a.IsInf(0) || b.IsInf(0) {
 		// Check for 0 * ∞ which is NaN
@@ -187,7 +184,7 @@ func MulWithMode(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Floa
 	}
 
 	// Full IEEE 754 implementation
-	return addIEEE754(a, b, rounding)
+	return mulIEEE754(a, b, rounding)
 }
 
 // Div performs division of two Float16 values
@@ -198,6 +195,14 @@ func Div(a, b Float16) Float16 {
 
 // DivWithMode performs division with specified arithmetic and rounding modes
 func DivWithMode(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Float16, error) {
+	// Handle NaN cases before zeros, so that NaN / 0 is NaN
+	if a.IsNaN() || b.IsNaN() {
+		if mode == ModeExactArithmetic {
+			return propagateNaN(a, b), nanOperandError("div", a, b)
+		}
+		return propagateNaN(a, b), nil
+	}
+
 	// Handle division by zero
 	if b.IsZero() {
 		if a.IsZero() {
@@ -271,18 +276,6 @@ func DivWithMode(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Floa
 		return PositiveZero, nil
 	}
 
-	// Handle NaN cases
-	if a.IsNaN() || b.IsNaN() {
-		if mode == ModeExactArithmetic {
-			return 0, &Float16Error{
-				Op:   "div",
-				Msg:  "NaN operand in exact mode",
-				Code: ErrNaN,
-			}
-		}
-		return QuietNaN, nil
-	}
-
 	// Handle infinity cases
 	if a.IsInf(0) && b.IsInf(0) {
 		// ∞/∞ = NaN
@@ -325,7 +318,7 @@ func DivWithMode(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Floa
 	}
 
 	// Full IEEE 754 implementation
-	return addIEEE754(a, b, rounding)
+	return divIEEE754(a, b, rounding)
 }
 
 // IEEE 754 compliant arithmetic implementations
@@ -425,7 +418,8 @@ func GreaterEqual(a, b Float16) bool {
 	return Greater(a, b) || Equal(a, b)
 }
 
-// Min returns the smaller of two Float16 values
+// Min returns the smaller of two Float16 values. It matches MinimumNumber
+// except that two NaNs give b unchanged.
 func Min(a, b Float16) Float16 {
 	// Handle NaN: return the non-NaN value, or NaN if both are NaN
 	if a.IsNaN() {
@@ -447,7 +441,8 @@ func Min(a, b Float16) Float16 {
 	return b
 }
 
-// Max returns the larger of two Float16 values
+// Max returns the larger of two Float16 values. It matches MaximumNumber
+// except that two NaNs give b unchanged.
 func Max(a, b Float16) Float16 {
 	// Handle NaN: return the non-NaN value, or NaN if both are NaN
 	if a.IsNaN() {
@@ -456,7 +451,13 @@ func Max(a, b Float16) Float16 {
 	if b.IsNaN() {
 		return a
 	}
-
+	// Handle -0 and +0
+	if a.IsZero() && b.IsZero() {
+		if a.Signbit() {
+			return b // b is +0, or both are -0
+		}
+		return a // a is +0
+	}
 	if Greater(a, b) {
 		return a
 	}
@@ -530,35 +531,20 @@ func ScaleSlice(s []Float16, scalar Float16) []Float16 {
 	return result
 }
 
-// SumSlice returns the sum of all elements in the slice
+// SumSlice returns the sum of all elements in the slice, accumulated in
+// float64 and rounded once; see SumSliceWith for other strategies
 func SumSlice(s []Float16) Float16 {
-	var sum Float16 = PositiveZero
-	for _, v := range s {
-		sum = Add(sum, v)
-	}
-	return sum
+	return SumSliceWith(s, SumFloat64Acc)
 }
 
-// DotProduct computes the dot product of two Float16 slices
+// DotProduct computes the dot product of two Float16 slices, accumulated in
+// float64 and rounded once; see DotProductWith for other strategies
 func DotProduct(a, b []Float16) Float16 {
-	if len(a) != len(b) {
-		panic("float16: slice length mismatch")
-	}
-
-	var sum Float16 = PositiveZero
-	for i := range a {
-		product := Mul(a[i], b[i])
-		sum = Add(sum, product)
-	}
-	return sum
+	return DotProductWith(a, b, SumFloat64Acc)
 }
 
-// Norm2 computes the L2 norm (Euclidean norm) of a Float16 slice
+// Norm2 computes the L2 norm (Euclidean norm) of a Float16 slice without
+// intermediate overflow; see Norm2With for other strategies
 func Norm2(s []Float16) Float16 {
-	var sumSquares Float16 = PositiveZero
-	for _, v := range s {
-		square := Mul(v, v)
-		sumSquares = Add(sumSquares, square)
-	}
-	return FromFloat64(math.Sqrt(sumSquares.ToFloat64()))
+	return Norm2With(s, SumFloat64Acc)
 }
@@ -84,7 +84,7 @@ func TestAddWithMode_ErrorCases(t *testing.T) {
 		expect  Float16
 		errCode ErrorCode
 	}{
-		{"NaN in exact mode", 0x7E00, 0x3C00, ModeExactArithmetic, 0, ErrNaN},
+		{"NaN in exact mode", 0x7E00, 0x3C00, ModeExactArithmetic, 0x7E00, ErrNaN},
 		{"Inf-Inf in exact mode", 0x7C00, 0xFC00, ModeExactArithmetic, 0, ErrInvalidOperation},
 	}
 
@@ -711,6 +711,39 @@ func TestAddIEEE754(t *testing.T) {
 	}
 }
 
+// TestMulDivIEEE checks that the IEEE mode multiplies and divides, with
+// operands whose sum differs from both results
+func TestMulDivIEEE(t *testing.T) {
+	tests := []struct {
+		a, b, product, quotient float64
+	}{
+		{3, 4, 12, 0.75},
+		{-1.5, 2, -3, -0.75},
+		{0.25, 8, 2, 1.0 / 32},
+		{100, -0.5, -50, -200},
+		{6, 3, 18, 2},
+	}
+
+	modes := []RoundingMode{RoundNearestEven, RoundNearestAway, RoundTowardZero, RoundTowardPositive, RoundTowardNegative}
+	for _, tt := range tests {
+		a, b := FromFloat64(tt.a), FromFloat64(tt.b)
+		for _, r := range modes {
+			if got, err := MulWithMode(a, b, ModeIEEEArithmetic, r); err != nil || got.ToFloat64() != tt.product {
+				t.Errorf("MulWithMode(%v, %v, %v) = %v, %v, want %v", tt.a, tt.b, r, got, err, tt.product)
+			}
+			if got, err := DivWithMode(a, b, ModeIEEEArithmetic, r); err != nil || got.ToFloat64() != tt.quotient {
+				t.Errorf("DivWithMode(%v, %v, %v) = %v, %v, want %v", tt.a, tt.b, r, got, err, tt.quotient)
+			}
+		}
+		if got := Mul(a, b); got.ToFloat64() != tt.product {
+			t.Errorf("Mul(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.product)
+		}
+		if got := Div(a, b); got.ToFloat64() != tt.quotient {
+			t.Errorf("Div(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.quotient)
+		}
+	}
+}
+
 func TestLess_Extra(t *testing.T) {
 	tests := []struct {
 		name   string
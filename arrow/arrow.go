@@ -0,0 +1,152 @@
+// Package arrow encodes and decodes Apache Arrow IPC streams and files
+// carrying half-precision columns.
+//
+// It implements just enough of the Arrow columnar format to exchange record
+// batches of HalfFloat, Float32 and fixed-width integer columns, with
+// validity bitmaps, without depending on the Arrow Go module. HalfFloat
+// columns map to []float16.Float16 plus a null mask.
+//
+// Dictionary batches, compression, nested types and big-endian data are not
+// supported.
+//
+// See: https://arrow.apache.org/docs/format/Columnar.html
+package arrow
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zerfoo/float16"
+)
+
+// Errors returned when decoding Arrow data
+var (
+	ErrFormat      = errors.New("arrow: malformed IPC data")
+	ErrUnsupported = errors.New("arrow: unsupported feature")
+	ErrSchema      = errors.New("arrow: record batch does not match schema")
+)
+
+// DataType is the type of a column
+type DataType int
+
+// Supported column types
+const (
+	Float16 DataType = iota
+	Float32
+	Int8
+	Int16
+	Int32
+	Int64
+	Uint8
+	Uint16
+	Uint32
+	Uint64
+)
+
+func (t DataType) String() string {
+	names := [...]string{"float16", "float32", "int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64"}
+	if t >= 0 && int(t) < len(names) {
+		return names[t]
+	}
+	return fmt.Sprintf("DataType(%d)", int(t))
+}
+
+// width returns the size in bytes of one value
+func (t DataType) width() int {
+	switch t {
+	case Int8, Uint8:
+		return 1
+	case Float16, Int16, Uint16:
+		return 2
+	case Float32, Int32, Uint32:
+		return 4
+	case Int64, Uint64:
+		return 8
+	}
+	return 0
+}
+
+func (t DataType) signed() bool {
+	return t >= Int8 && t <= Int64
+}
+
+// Field describes one column of a schema
+type Field struct {
+	Name     string
+	Type     DataType
+	Nullable bool
+}
+
+// Schema is an ordered list of fields
+type Schema struct {
+	Fields []Field
+}
+
+// Column holds the values of one column. Exactly one value slice is used,
+// chosen by the field type: Float16 for float16, Float32 for float32, Int
+// for signed integers and Uint for unsigned integers.
+//
+// Nulls is the null mask: when non-nil, Nulls[i] reports that row i is null,
+// and the corresponding value is meaningless. A nil mask means no nulls.
+type Column struct {
+	Float16 []float16.Float16
+	Float32 []float32
+	Int     []int64
+	Uint    []uint64
+	Nulls   []bool
+}
+
+// Len returns the number of rows held by the column's value slice
+func (c *Column) Len(t DataType) int {
+	switch {
+	case t == Float16:
+		return len(c.Float16)
+	case t == Float32:
+		return len(c.Float32)
+	case t.signed():
+		return len(c.Int)
+	default:
+		return len(c.Uint)
+	}
+}
+
+// NullCount returns the number of null rows
+func (c *Column) NullCount() int {
+	n := 0
+	for _, null := range c.Nulls {
+		if null {
+			n++
+		}
+	}
+	return n
+}
+
+// RecordBatch is a set of equal-length columns described by a schema
+type RecordBatch struct {
+	Schema  *Schema
+	Length  int
+	Columns []Column
+}
+
+// Validate checks that the batch matches its schema
+func (rb *RecordBatch) Validate() error {
+	if rb.Schema == nil || len(rb.Columns) != len(rb.Schema.Fields) {
+		return fmt.Errorf("%w: column count", ErrSchema)
+	}
+	for i, f := range rb.Schema.Fields {
+		c := &rb.Columns[i]
+		if f.Type.width() == 0 {
+			return fmt.Errorf("%w: field %q has unknown type %v", ErrSchema, f.Name, f.Type)
+		}
+		if n := c.Len(f.Type); n != rb.Length {
+			return fmt.Errorf("%w: column %q has %d %s values, want %d", ErrSchema, f.Name, n, f.Type, rb.Length)
+		}
+		if c.Nulls != nil && len(c.Nulls) != rb.Length {
+			return fmt.Errorf("%w: column %q null mask has %d entries, want %d", ErrSchema, f.Name, len(c.Nulls), rb.Length)
+		}
+		if !f.Nullable && c.NullCount() > 0 {
+			return fmt.Errorf("%w: non-nullable column %q has nulls", ErrSchema, f.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,387 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+func testSchema() *Schema {
+	return &Schema{Fields: []Field{
+		{Name: "activations", Type: Float16, Nullable: true},
+		{Name: "scale", Type: Float32},
+		{Name: "token", Type: Int32},
+		{Name: "delta", Type: Int8, Nullable: true},
+		{Name: "id", Type: Uint64},
+		{Name: "flags", Type: Uint16},
+	}}
+}
+
+func testBatch(schema *Schema, n int) *RecordBatch {
+	rb := &RecordBatch{Schema: schema, Length: n, Columns: make([]Column, len(schema.Fields))}
+	for i := 0; i < n; i++ {
+		rb.Columns[0].Float16 = append(rb.Columns[0].Float16, float16.FromFloat32(float32(i)*0.5-1))
+		rb.Columns[1].Float32 = append(rb.Columns[1].Float32, float32(i)/3)
+		rb.Columns[2].Int = append(rb.Columns[2].Int, int64(i*1000-5000))
+		rb.Columns[3].Int = append(rb.Columns[3].Int, int64(i%256-128))
+		rb.Columns[4].Uint = append(rb.Columns[4].Uint, uint64(i)<<40)
+		rb.Columns[5].Uint = append(rb.Columns[5].Uint, uint64(65535-i))
+	}
+	rb.Columns[0].Nulls = make([]bool, n)
+	for i := 0; i < n; i += 3 {
+		rb.Columns[0].Nulls[i] = true
+		rb.Columns[0].Float16[i] = 0
+	}
+	if n > 1 {
+		rb.Columns[0].Float16[n-1] = float16.QuietNaN
+	}
+	return rb
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	schema := testSchema()
+	batches := []*RecordBatch{testBatch(schema, 11), testBatch(schema, 1), testBatch(schema, 0)}
+	batches[2].Columns[0].Nulls = nil
+	batches[2].Columns[0].Float16 = []float16.Float16{}
+
+	var buf bytes.Buffer
+	w, err := NewStreamWriter(&buf, schema)
+	if err != nil {
+		t.Fatalf("NewStreamWriter() unexpected error: %v", err)
+	}
+	for _, rb := range batches {
+		if err := w.Write(rb); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+	if buf.Len()%8 != 0 {
+		t.Errorf("stream length %d is not a multiple of 8", buf.Len())
+	}
+
+	r, err := NewStreamReader(&buf)
+	if err != nil {
+		t.Fatalf("NewStreamReader() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(r.Schema(), schema) {
+		t.Errorf("Schema() = %+v, want %+v", r.Schema(), schema)
+	}
+	for i, want := range batches {
+		got, err := r.Next()
+		if err != nil {
+			t.Fatalf("Next() batch %d unexpected error: %v", i, err)
+		}
+		checkBatchEqual(t, got, want)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next() after last batch error = %v, want io.EOF", err)
+	}
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	schema := testSchema()
+	batches := []*RecordBatch{testBatch(schema, 5), testBatch(schema, 17)}
+
+	var buf bytes.Buffer
+	w, err := NewFileWriter(&buf, schema)
+	if err != nil {
+		t.Fatalf("NewFileWriter() unexpected error: %v", err)
+	}
+	for _, rb := range batches {
+		if err := w.Write(rb); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	data := buf.Bytes()
+	if string(data[:8]) != "ARROW1\x00\x00" || string(data[len(data)-6:]) != "ARROW1" {
+		t.Fatalf("file magic missing: %q ... %q", data[:8], data[len(data)-6:])
+	}
+
+	r, err := NewFileReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewFileReader() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(r.Schema(), schema) {
+		t.Errorf("Schema() = %+v, want %+v", r.Schema(), schema)
+	}
+	if r.NumRecords() != len(batches) {
+		t.Fatalf("NumRecords() = %d, want %d", r.NumRecords(), len(batches))
+	}
+	// Random access, last batch first
+	for i := len(batches) - 1; i >= 0; i-- {
+		got, err := r.Record(i)
+		if err != nil {
+			t.Fatalf("Record(%d) unexpected error: %v", i, err)
+		}
+		checkBatchEqual(t, got, batches[i])
+	}
+	if _, err := r.Record(len(batches)); err == nil {
+		t.Error("Record() out of range expected error, got nil")
+	}
+
+	// The embedded stream is readable on its own
+	sr, err := NewStreamReader(bytes.NewReader(data[8:]))
+	if err != nil {
+		t.Fatalf("NewStreamReader() on file body unexpected error: %v", err)
+	}
+	for range batches {
+		if _, err := sr.Next(); err != nil {
+			t.Fatalf("Next() on file body unexpected error: %v", err)
+		}
+	}
+	if _, err := sr.Next(); err != io.EOF {
+		t.Errorf("Next() after last batch error = %v, want io.EOF", err)
+	}
+}
+
+func checkBatchEqual(t *testing.T, got, want *RecordBatch) {
+	t.Helper()
+	if got.Length != want.Length {
+		t.Fatalf("Length = %d, want %d", got.Length, want.Length)
+	}
+	for i, f := range want.Schema.Fields {
+		g, w := got.Columns[i], want.Columns[i]
+		for j := 0; j < want.Length; j++ {
+			gotNull := g.Nulls != nil && g.Nulls[j]
+			wantNull := w.Nulls != nil && w.Nulls[j]
+			if gotNull != wantNull {
+				t.Errorf("%s[%d] null = %v, want %v", f.Name, j, gotNull, wantNull)
+			}
+			if wantNull {
+				continue
+			}
+			switch {
+			case f.Type == Float16:
+				if g.Float16[j].Bits() != w.Float16[j].Bits() {
+					t.Errorf("%s[%d] = %#04x, want %#04x", f.Name, j, g.Float16[j].Bits(), w.Float16[j].Bits())
+				}
+			case f.Type == Float32:
+				if g.Float32[j] != w.Float32[j] {
+					t.Errorf("%s[%d] = %v, want %v", f.Name, j, g.Float32[j], w.Float32[j])
+				}
+			case f.Type.signed():
+				if g.Int[j] != w.Int[j] {
+					t.Errorf("%s[%d] = %d, want %d", f.Name, j, g.Int[j], w.Int[j])
+				}
+			default:
+				if g.Uint[j] != w.Uint[j] {
+					t.Errorf("%s[%d] = %d, want %d", f.Name, j, g.Uint[j], w.Uint[j])
+				}
+			}
+		}
+	}
+}
+
+func TestValidityBitmapLayout(t *testing.T) {
+	schema := &Schema{Fields: []Field{{Name: "x", Type: Float16, Nullable: true}}}
+	rb := &RecordBatch{Schema: schema, Length: 10, Columns: []Column{{
+		Float16: make([]float16.Float16, 10),
+		Nulls:   []bool{false, true, false, false, false, false, false, false, true, false},
+	}}}
+	_, body := encodeRecordBatch(rb)
+
+	// LSB-first bitmap with 1 meaning valid, padded to 8 bytes
+	if body[0] != 0xFD || body[1] != 0x02 {
+		t.Errorf("bitmap = %#02x %#02x, want 0xfd 0x02", body[0], body[1])
+	}
+	if len(body) != 8+24 {
+		t.Errorf("body length = %d, want 32", len(body))
+	}
+}
+
+func TestSchemaTypes(t *testing.T) {
+	var fields []Field
+	for dt := Float16; dt <= Uint64; dt++ {
+		fields = append(fields, Field{Name: dt.String(), Type: dt, Nullable: dt%2 == 0})
+	}
+	schema := &Schema{Fields: fields}
+
+	got, err := readSchema(bytes.NewReader(frame(schemaMessage(schema))))
+	if err != nil {
+		t.Fatalf("readSchema() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, schema) {
+		t.Errorf("readSchema() = %+v, want %+v", got, schema)
+	}
+}
+
+// frame wraps metadata in the encapsulated message format
+func frame(meta []byte) []byte {
+	var buf bytes.Buffer
+	if _, _, err := writeMessage(&buf, meta, nil); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+func TestLegacyFraming(t *testing.T) {
+	schema := testSchema()
+	meta := schemaMessage(schema)
+
+	// Pre-0.15 streams omit the continuation marker
+	legacy := binary.LittleEndian.AppendUint32(nil, uint32(align8(len(meta))))
+	legacy = append(legacy, meta...)
+	legacy = append(legacy, make([]byte, align8(len(meta))-len(meta))...)
+	legacy = append(legacy, 0, 0, 0, 0)
+
+	r, err := NewStreamReader(bytes.NewReader(legacy))
+	if err != nil {
+		t.Fatalf("NewStreamReader() unexpected error: %v", err)
+	}
+	if len(r.Schema().Fields) != len(schema.Fields) {
+		t.Errorf("Schema() has %d fields, want %d", len(r.Schema().Fields), len(schema.Fields))
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Next() error = %v, want io.EOF", err)
+	}
+}
+
+func TestWriteValidation(t *testing.T) {
+	schema := testSchema()
+	w, err := NewStreamWriter(io.Discard, schema)
+	if err != nil {
+		t.Fatalf("NewStreamWriter() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*RecordBatch)
+	}{
+		{"short column", func(rb *RecordBatch) { rb.Columns[1].Float32 = rb.Columns[1].Float32[:2] }},
+		{"wrong slice", func(rb *RecordBatch) { rb.Columns[2].Int, rb.Columns[2].Uint = nil, make([]uint64, rb.Length) }},
+		{"nulls in non-nullable", func(rb *RecordBatch) { rb.Columns[1].Nulls = make([]bool, rb.Length); rb.Columns[1].Nulls[0] = true }},
+		{"null mask length", func(rb *RecordBatch) { rb.Columns[0].Nulls = rb.Columns[0].Nulls[:1] }},
+		{"missing column", func(rb *RecordBatch) { rb.Columns = rb.Columns[:2] }},
+		{"other schema", func(rb *RecordBatch) {
+			s := testSchema()
+			s.Fields[0].Name = "renamed"
+			rb.Schema = s
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rb := testBatch(testSchema(), 4)
+			tt.mutate(rb)
+			if err := w.Write(rb); !errors.Is(err, ErrSchema) {
+				t.Errorf("Write() error = %v, want ErrSchema", err)
+			}
+		})
+	}
+
+	if _, err := NewStreamWriter(io.Discard, &Schema{Fields: []Field{{Name: "x", Type: DataType(99)}}}); !errors.Is(err, ErrSchema) {
+		t.Errorf("NewStreamWriter() unknown type error = %v, want ErrSchema", err)
+	}
+
+	w.Close()
+	if err := w.Write(testBatch(schema, 1)); err == nil {
+		t.Error("Write() after Close expected error, got nil")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	schema := testSchema()
+	var buf bytes.Buffer
+	w, _ := NewStreamWriter(&buf, schema)
+	w.Write(testBatch(schema, 8))
+	w.Close()
+	good := buf.Bytes()
+
+	// Double precision is not supported
+	b := newBuilder()
+	b.startTable(1)
+	b.addI16(0, 2)
+	fp := b.endTable()
+	name := b.createString("d")
+	b.startTable(7)
+	b.addOffset(0, name)
+	b.addU8(2, typeFloatingPoint)
+	b.addOffset(3, fp)
+	field := b.endTable()
+	fields := b.createOffsetVector([]int{field})
+	b.startTable(4)
+	b.addOffset(1, fields)
+	st := b.endTable()
+	b.startTable(5)
+	b.addI16(0, metadataV5)
+	b.addU8(1, headerSchema)
+	b.addOffset(2, st)
+	double := frame(b.finish(b.endTable()))
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, ErrFormat},
+		{"eos only", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0}, ErrFormat},
+		{"truncated metadata", good[:20], ErrFormat},
+		{"garbage metadata", append([]byte{0xFF, 0xFF, 0xFF, 0xFF, 8, 0, 0, 0}, 0xF0, 0xFF, 0xFF, 0x7F, 1, 2, 3, 4), ErrFormat},
+		{"double", double, ErrUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStreamReader(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("NewStreamReader() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Truncated body of the record batch
+	r, err := NewStreamReader(bytes.NewReader(good[:len(good)-20]))
+	if err != nil {
+		t.Fatalf("NewStreamReader() unexpected error: %v", err)
+	}
+	if _, err := r.Next(); !errors.Is(err, ErrFormat) {
+		t.Errorf("Next() truncated body error = %v, want ErrFormat", err)
+	}
+
+	// Corrupting any byte must never panic
+	for i := range good {
+		data := append([]byte(nil), good...)
+		data[i] ^= 0xA5
+		r, err := NewStreamReader(bytes.NewReader(data))
+		if err != nil {
+			continue
+		}
+		for {
+			if _, err := r.Next(); err != nil {
+				break
+			}
+		}
+	}
+}
+
+func TestFileReaderErrors(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewFileWriter(&buf, testSchema())
+	w.Write(testBatch(testSchema(), 3))
+	w.Close()
+	good := buf.Bytes()
+
+	badMagic := append([]byte(nil), good...)
+	badMagic[len(badMagic)-1] = 'X'
+	badLen := append([]byte(nil), good...)
+	binary.LittleEndian.PutUint32(badLen[len(badLen)-10:], 1<<30)
+
+	for name, data := range map[string][]byte{
+		"short":      good[:10],
+		"bad magic":  badMagic,
+		"bad footer": badLen,
+	} {
+		if _, err := NewFileReader(bytes.NewReader(data), int64(len(data))); !errors.Is(err, ErrFormat) {
+			t.Errorf("NewFileReader(%s) error = %v, want ErrFormat", name, err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// fileMagic opens and closes an Arrow IPC file
+const fileMagic = "ARROW1"
+
+// block locates one record batch message within a file
+type block struct {
+	offset  int64
+	metaLen int32
+	bodyLen int64
+}
+
+// countingWriter tracks the number of bytes written
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// FileWriter writes record batches in the Arrow IPC file format, which
+// wraps a stream with magic bytes and a footer indexing the batches for
+// random access
+type FileWriter struct {
+	w      *countingWriter
+	schema *Schema
+	blocks []block
+	closed bool
+}
+
+// NewFileWriter writes the file header and schema to w
+func NewFileWriter(w io.Writer, schema *Schema) (*FileWriter, error) {
+	if err := checkSchema(schema); err != nil {
+		return nil, err
+	}
+	cw := &countingWriter{w: w}
+	if _, err := cw.Write([]byte(fileMagic + "\x00\x00")); err != nil {
+		return nil, err
+	}
+	if _, _, err := writeMessage(cw, schemaMessage(schema), nil); err != nil {
+		return nil, err
+	}
+	return &FileWriter{w: cw, schema: schema}, nil
+}
+
+// Write appends a record batch to the file
+func (fw *FileWriter) Write(rb *RecordBatch) error {
+	if fw.closed {
+		return errors.New("arrow: write to closed file")
+	}
+	if err := checkBatch(fw.schema, rb); err != nil {
+		return err
+	}
+	meta, body := encodeRecordBatch(rb)
+	offset := fw.w.n
+	metaLen, _, err := writeMessage(fw.w, meta, body)
+	if err != nil {
+		return err
+	}
+	fw.blocks = append(fw.blocks, block{offset: offset, metaLen: int32(metaLen), bodyLen: int64(len(body))})
+	return nil
+}
+
+// Close writes the end-of-stream marker and the footer. It does not close
+// the underlying writer.
+func (fw *FileWriter) Close() error {
+	if fw.closed {
+		return nil
+	}
+	fw.closed = true
+	if err := writeEOS(fw.w); err != nil {
+		return err
+	}
+
+	b := newBuilder()
+	schema := encodeSchemaTable(b, fw.schema)
+	dicts := b.createStructVector(nil)
+	blocks := make([][]int64, len(fw.blocks))
+	for i, blk := range fw.blocks {
+		blocks[i] = []int64{blk.offset, int64(blk.metaLen), blk.bodyLen}
+	}
+	batches := b.createStructVector(blocks)
+	b.startTable(5)
+	b.addI16(0, metadataV5)
+	b.addOffset(1, schema)
+	b.addOffset(2, dicts)
+	b.addOffset(3, batches)
+	footer := b.finish(b.endTable())
+
+	tail := make([]byte, 0, len(footer)+4+len(fileMagic))
+	tail = append(tail, footer...)
+	tail = binary.LittleEndian.AppendUint32(tail, uint32(len(footer)))
+	tail = append(tail, fileMagic...)
+	_, err := fw.w.Write(tail)
+	return err
+}
+
+// FileReader provides random access to the record batches of an Arrow IPC
+// file
+type FileReader struct {
+	r      io.ReaderAt
+	size   int64
+	schema *Schema
+	blocks []block
+}
+
+// NewFileReader reads the footer of the size-byte file in r
+func NewFileReader(r io.ReaderAt, size int64) (*FileReader, error) {
+	const trailer = 4 + len(fileMagic)
+	if size < int64(8+trailer) {
+		return nil, fmt.Errorf("%w: file too small", ErrFormat)
+	}
+	head := make([]byte, len(fileMagic))
+	if _, err := r.ReadAt(head, 0); err != nil {
+		return nil, err
+	}
+	tail := make([]byte, trailer)
+	if _, err := r.ReadAt(tail, size-int64(trailer)); err != nil {
+		return nil, err
+	}
+	if string(head) != fileMagic || string(tail[4:]) != fileMagic {
+		return nil, fmt.Errorf("%w: missing ARROW1 magic", ErrFormat)
+	}
+
+	footerLen := int64(int32(binary.LittleEndian.Uint32(tail)))
+	if footerLen <= 0 || footerLen > size-int64(8+trailer) {
+		return nil, fmt.Errorf("%w: footer length %d", ErrFormat, footerLen)
+	}
+	footer := make([]byte, footerLen)
+	if _, err := r.ReadAt(footer, size-int64(trailer)-footerLen); err != nil {
+		return nil, err
+	}
+
+	fr := &FileReader{r: r, size: size}
+	err := decode(func() error {
+		root := rootTable(footer)
+		st, ok := root.table(1)
+		if !ok {
+			return fmt.Errorf("%w: footer has no schema", ErrFormat)
+		}
+		schema, err := decodeSchemaTable(st)
+		if err != nil {
+			return err
+		}
+		fr.schema = schema
+		if _, n := root.vector(2); n != 0 {
+			return fmt.Errorf("%w: dictionary batches", ErrUnsupported)
+		}
+		start, n := root.vector(3)
+		for i := 0; i < n; i++ {
+			p := start + 24*i
+			fr.blocks = append(fr.blocks, block{
+				offset:  int64(readU64(footer, p)),
+				metaLen: int32(readU32(footer, p+8)),
+				bodyLen: int64(readU64(footer, p+16)),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fr, nil
+}
+
+// Schema returns the schema of the file
+func (fr *FileReader) Schema() *Schema {
+	return fr.schema
+}
+
+// NumRecords returns the number of record batches in the file
+func (fr *FileReader) NumRecords() int {
+	return len(fr.blocks)
+}
+
+// Record reads record batch i
+func (fr *FileReader) Record(i int) (*RecordBatch, error) {
+	if i < 0 || i >= len(fr.blocks) {
+		return nil, fmt.Errorf("arrow: record %d out of range [0, %d)", i, len(fr.blocks))
+	}
+	blk := fr.blocks[i]
+	if blk.offset < 0 || blk.metaLen < 8 || blk.bodyLen < 0 ||
+		blk.offset > fr.size || int64(blk.metaLen) > fr.size-blk.offset ||
+		blk.bodyLen > fr.size-blk.offset-int64(blk.metaLen) {
+		return nil, fmt.Errorf("%w: record %d block out of range", ErrFormat, i)
+	}
+	buf := make([]byte, int64(blk.metaLen)+blk.bodyLen)
+	if _, err := fr.r.ReadAt(buf, blk.offset); err != nil {
+		return nil, err
+	}
+	rb, err := readRecordBatch(bytes.NewReader(buf), fr.schema)
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: record %d is an end-of-stream marker", ErrFormat, i)
+	}
+	return rb, err
+}
@@ -0,0 +1,340 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// This file holds a minimal FlatBuffers builder and reader, covering only
+// what the Arrow IPC metadata needs: tables with scalar, table, string and
+// vector fields, unions, and vectors of structs. The layout follows the
+// FlatBuffers binary format, building buffers back to front.
+//
+// See: https://flatbuffers.dev/internals/
+
+// errBounds is raised by the reader when an offset points outside the buffer
+var errBounds = errors.New("flatbuffer offset out of range")
+
+// builder constructs a FlatBuffer back to front; data occupies buf[head:]
+type builder struct {
+	buf       []byte
+	head      int
+	minalign  int
+	vtable    []int
+	objectEnd int
+}
+
+func newBuilder() *builder {
+	b := &builder{buf: make([]byte, 256), minalign: 1}
+	b.head = len(b.buf)
+	return b
+}
+
+// offset returns the current size of the built data; objects are referred
+// to by this offset from the end of the buffer
+func (b *builder) offset() int {
+	return len(b.buf) - b.head
+}
+
+// grow ensures there is room to prepend n bytes
+func (b *builder) grow(n int) {
+	for b.head < n {
+		nb := make([]byte, 2*len(b.buf))
+		copy(nb[len(nb)-len(b.buf):], b.buf)
+		b.head += len(nb) - len(b.buf)
+		b.buf = nb
+	}
+}
+
+// pad prepends n zero bytes
+func (b *builder) pad(n int) {
+	b.grow(n)
+	for i := 0; i < n; i++ {
+		b.head--
+		b.buf[b.head] = 0
+	}
+}
+
+// prep aligns the buffer so that after prepending additional bytes, the
+// next value of the given size is aligned to that size
+func (b *builder) prep(size, additional int) {
+	if size > b.minalign {
+		b.minalign = size
+	}
+	b.pad(-(b.offset() + additional) & (size - 1))
+}
+
+func (b *builder) placeU8(v uint8) {
+	b.grow(1)
+	b.head--
+	b.buf[b.head] = v
+}
+
+func (b *builder) placeU16(v uint16) {
+	b.grow(2)
+	b.head -= 2
+	binary.LittleEndian.PutUint16(b.buf[b.head:], v)
+}
+
+func (b *builder) placeU32(v uint32) {
+	b.grow(4)
+	b.head -= 4
+	binary.LittleEndian.PutUint32(b.buf[b.head:], v)
+}
+
+func (b *builder) placeU64(v uint64) {
+	b.grow(8)
+	b.head -= 8
+	binary.LittleEndian.PutUint64(b.buf[b.head:], v)
+}
+
+// prependUOffset writes a forward reference to the object at off
+func (b *builder) prependUOffset(off int) {
+	b.prep(4, 0)
+	b.placeU32(uint32(b.offset() - off + 4))
+}
+
+func (b *builder) createString(s string) int {
+	b.prep(4, len(s)+1)
+	b.placeU8(0)
+	b.grow(len(s))
+	b.head -= len(s)
+	copy(b.buf[b.head:], s)
+	b.placeU32(uint32(len(s)))
+	return b.offset()
+}
+
+// startVector prepares for n elements of elemSize bytes, prepended in reverse
+func (b *builder) startVector(elemSize, n, alignment int) {
+	b.prep(4, elemSize*n)
+	b.prep(alignment, elemSize*n)
+}
+
+func (b *builder) endVector(n int) int {
+	b.placeU32(uint32(n))
+	return b.offset()
+}
+
+// createOffsetVector builds a vector of references to the given objects
+func (b *builder) createOffsetVector(offs []int) int {
+	b.startVector(4, len(offs), 4)
+	for i := len(offs) - 1; i >= 0; i-- {
+		b.prependUOffset(offs[i])
+	}
+	return b.endVector(len(offs))
+}
+
+// createStructVector builds a vector of structs made of int64 fields
+func (b *builder) createStructVector(structs [][]int64) int {
+	size := 0
+	if len(structs) > 0 {
+		size = 8 * len(structs[0])
+	}
+	b.startVector(size, len(structs), 8)
+	for i := len(structs) - 1; i >= 0; i-- {
+		for j := len(structs[i]) - 1; j >= 0; j-- {
+			b.placeU64(uint64(structs[i][j]))
+		}
+	}
+	return b.endVector(len(structs))
+}
+
+func (b *builder) startTable(numFields int) {
+	b.vtable = make([]int, numFields)
+	b.objectEnd = b.offset()
+}
+
+func (b *builder) slot(i int) {
+	b.vtable[i] = b.offset()
+}
+
+func (b *builder) addU8(i int, v uint8) {
+	b.prep(1, 0)
+	b.placeU8(v)
+	b.slot(i)
+}
+
+func (b *builder) addBool(i int, v bool) {
+	if v {
+		b.addU8(i, 1)
+	} else {
+		b.addU8(i, 0)
+	}
+}
+
+func (b *builder) addI16(i int, v int16) {
+	b.prep(2, 0)
+	b.placeU16(uint16(v))
+	b.slot(i)
+}
+
+func (b *builder) addI32(i int, v int32) {
+	b.prep(4, 0)
+	b.placeU32(uint32(v))
+	b.slot(i)
+}
+
+func (b *builder) addI64(i int, v int64) {
+	b.prep(8, 0)
+	b.placeU64(uint64(v))
+	b.slot(i)
+}
+
+func (b *builder) addOffset(i int, off int) {
+	b.prependUOffset(off)
+	b.slot(i)
+}
+
+// endTable writes the table's vtable and returns the table offset
+func (b *builder) endTable() int {
+	b.prep(4, 0)
+	b.placeU32(0) // soffset to the vtable, patched below
+	obj := b.offset()
+
+	n := len(b.vtable)
+	for n > 0 && b.vtable[n-1] == 0 {
+		n--
+	}
+	for i := n - 1; i >= 0; i-- {
+		var off uint16
+		if b.vtable[i] != 0 {
+			off = uint16(obj - b.vtable[i])
+		}
+		b.placeU16(off)
+	}
+	b.placeU16(uint16(obj - b.objectEnd))
+	b.placeU16(uint16(4 + 2*n))
+	vt := b.offset()
+
+	binary.LittleEndian.PutUint32(b.buf[len(b.buf)-obj:], uint32(int32(vt-obj)))
+	b.vtable = nil
+	return obj
+}
+
+// finish writes the root table reference and returns the finished buffer
+func (b *builder) finish(root int) []byte {
+	b.prep(b.minalign, 4)
+	b.prependUOffset(root)
+	return b.buf[b.head:]
+}
+
+// table is a FlatBuffer table being read. Accessors panic with errBounds
+// on malformed input; decoders recover it into an error.
+type table struct {
+	buf []byte
+	pos int
+}
+
+func rootTable(buf []byte) table {
+	return table{buf: buf, pos: int(readU32(buf, 0))}
+}
+
+func readU16(buf []byte, p int) uint16 {
+	if p < 0 || p+2 > len(buf) {
+		panic(errBounds)
+	}
+	return binary.LittleEndian.Uint16(buf[p:])
+}
+
+func readU32(buf []byte, p int) uint32 {
+	if p < 0 || p+4 > len(buf) {
+		panic(errBounds)
+	}
+	return binary.LittleEndian.Uint32(buf[p:])
+}
+
+func readU64(buf []byte, p int) uint64 {
+	if p < 0 || p+8 > len(buf) {
+		panic(errBounds)
+	}
+	return binary.LittleEndian.Uint64(buf[p:])
+}
+
+// field returns the position of field i, or 0 if it is absent
+func (t table) field(i int) int {
+	vt := t.pos - int(int32(readU32(t.buf, t.pos)))
+	vsize := int(readU16(t.buf, vt))
+	o := 4 + 2*i
+	if o+2 > vsize {
+		return 0
+	}
+	off := int(readU16(t.buf, vt+o))
+	if off == 0 {
+		return 0
+	}
+	return t.pos + off
+}
+
+func (t table) u8(i int, def uint8) uint8 {
+	p := t.field(i)
+	if p == 0 {
+		return def
+	}
+	if p >= len(t.buf) {
+		panic(errBounds)
+	}
+	return t.buf[p]
+}
+
+func (t table) boolean(i int) bool {
+	return t.u8(i, 0) != 0
+}
+
+func (t table) i16(i int, def int16) int16 {
+	if p := t.field(i); p != 0 {
+		return int16(readU16(t.buf, p))
+	}
+	return def
+}
+
+func (t table) i32(i int, def int32) int32 {
+	if p := t.field(i); p != 0 {
+		return int32(readU32(t.buf, p))
+	}
+	return def
+}
+
+func (t table) i64(i int, def int64) int64 {
+	if p := t.field(i); p != 0 {
+		return int64(readU64(t.buf, p))
+	}
+	return def
+}
+
+// deref follows the uoffset stored at p
+func (t table) deref(p int) int {
+	return p + int(readU32(t.buf, p))
+}
+
+func (t table) table(i int) (table, bool) {
+	p := t.field(i)
+	if p == 0 {
+		return table{}, false
+	}
+	return table{buf: t.buf, pos: t.deref(p)}, true
+}
+
+// vector returns the position of the first element and the element count
+func (t table) vector(i int) (start, n int) {
+	p := t.field(i)
+	if p == 0 {
+		return 0, 0
+	}
+	vp := t.deref(p)
+	n = int(readU32(t.buf, vp))
+	if n < 0 || vp+4+n > len(t.buf) {
+		panic(errBounds)
+	}
+	return vp + 4, n
+}
+
+// vectorTable returns element j of a vector of tables starting at start
+func (t table) vectorTable(start, j int) table {
+	p := start + 4*j
+	return table{buf: t.buf, pos: t.deref(p)}
+}
+
+func (t table) str(i int) string {
+	start, n := t.vector(i)
+	return string(t.buf[start : start+n])
+}
@@ -0,0 +1,483 @@
+package arrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/zerfoo/float16"
+)
+
+// Constants from the Arrow Schema.fbs and Message.fbs definitions
+const (
+	metadataV4 = 3
+	metadataV5 = 4
+
+	headerSchema          = 1
+	headerDictionaryBatch = 2
+	headerRecordBatch     = 3
+
+	typeInt           = 2
+	typeFloatingPoint = 3
+
+	precisionHalf   = 0
+	precisionSingle = 1
+
+	// continuation marks the start of an encapsulated message
+	continuation = 0xFFFFFFFF
+
+	// maxMetadataSize bounds the flatbuffer metadata of a single message
+	maxMetadataSize = 64 << 20
+)
+
+// align8 rounds n up to a multiple of 8, the alignment Arrow requires for
+// metadata and body buffers
+func align8(n int) int {
+	return (n + 7) &^ 7
+}
+
+// decode runs fn, converting flatbuffer bounds panics into ErrFormat
+func decode(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if r != errBounds {
+				panic(r)
+			}
+			err = fmt.Errorf("%w: %v", ErrFormat, errBounds)
+		}
+	}()
+	return fn()
+}
+
+// encodeSchemaTable adds a Schema table to b and returns its offset
+func encodeSchemaTable(b *builder, s *Schema) int {
+	fields := make([]int, len(s.Fields))
+	for i, f := range s.Fields {
+		var typeType uint8
+		var typeOff int
+		switch f.Type {
+		case Float16, Float32:
+			precision := int16(precisionHalf)
+			if f.Type == Float32 {
+				precision = precisionSingle
+			}
+			b.startTable(1)
+			b.addI16(0, precision)
+			typeOff, typeType = b.endTable(), typeFloatingPoint
+		default:
+			b.startTable(2)
+			b.addI32(0, int32(8*f.Type.width()))
+			b.addBool(1, f.Type.signed())
+			typeOff, typeType = b.endTable(), typeInt
+		}
+		name := b.createString(f.Name)
+		children := b.createOffsetVector(nil)
+
+		b.startTable(7)
+		b.addOffset(0, name)
+		b.addBool(1, f.Nullable)
+		b.addU8(2, typeType)
+		b.addOffset(3, typeOff)
+		b.addOffset(5, children)
+		fields[i] = b.endTable()
+	}
+	vec := b.createOffsetVector(fields)
+
+	b.startTable(4)
+	b.addOffset(1, vec)
+	return b.endTable()
+}
+
+// decodeSchemaTable reads a Schema table
+func decodeSchemaTable(t table) (*Schema, error) {
+	if t.i16(0, 0) != 0 {
+		return nil, fmt.Errorf("%w: big-endian data", ErrUnsupported)
+	}
+	start, n := t.vector(1)
+	s := &Schema{Fields: make([]Field, n)}
+	for i := range s.Fields {
+		ft := t.vectorTable(start, i)
+		f := Field{Name: ft.str(0), Nullable: ft.boolean(1)}
+		if _, ok := ft.table(4); ok {
+			return nil, fmt.Errorf("%w: dictionary-encoded field %q", ErrUnsupported, f.Name)
+		}
+		if _, nc := ft.vector(5); nc != 0 {
+			return nil, fmt.Errorf("%w: nested field %q", ErrUnsupported, f.Name)
+		}
+		tt, ok := ft.table(3)
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q has no type", ErrFormat, f.Name)
+		}
+
+		switch ft.u8(2, 0) {
+		case typeFloatingPoint:
+			switch tt.i16(0, 0) {
+			case precisionHalf:
+				f.Type = Float16
+			case precisionSingle:
+				f.Type = Float32
+			default:
+				return nil, fmt.Errorf("%w: field %q has double precision", ErrUnsupported, f.Name)
+			}
+		case typeInt:
+			signed := tt.boolean(1)
+			switch tt.i32(0, 0) {
+			case 8:
+				f.Type = Uint8
+			case 16:
+				f.Type = Uint16
+			case 32:
+				f.Type = Uint32
+			case 64:
+				f.Type = Uint64
+			default:
+				return nil, fmt.Errorf("%w: field %q has bit width %d", ErrUnsupported, f.Name, tt.i32(0, 0))
+			}
+			if signed {
+				f.Type -= Uint8 - Int8
+			}
+		default:
+			return nil, fmt.Errorf("%w: field %q has type %d", ErrUnsupported, f.Name, ft.u8(2, 0))
+		}
+		s.Fields[i] = f
+	}
+	return s, nil
+}
+
+// encodeMessage builds a Message flatbuffer wrapping the given header
+func encodeMessage(headerType uint8, header func(*builder) int, bodyLength int) []byte {
+	b := newBuilder()
+	hdr := header(b)
+	b.startTable(5)
+	b.addI16(0, metadataV5)
+	b.addU8(1, headerType)
+	b.addOffset(2, hdr)
+	b.addI64(3, int64(bodyLength))
+	return b.finish(b.endTable())
+}
+
+// encodeRecordBatch builds the metadata and body of a RecordBatch message
+func encodeRecordBatch(rb *RecordBatch) (meta, body []byte) {
+	var nodes, buffers [][]int64
+	for i, f := range rb.Schema.Fields {
+		c := &rb.Columns[i]
+		nulls := c.NullCount()
+		nodes = append(nodes, []int64{int64(rb.Length), int64(nulls)})
+
+		// Validity bitmap, omitted when there are no nulls
+		start := len(body)
+		if nulls > 0 {
+			bitmap := make([]byte, (rb.Length+7)/8)
+			for j, null := range c.Nulls {
+				if !null {
+					bitmap[j/8] |= 1 << (j % 8)
+				}
+			}
+			body = append(body, bitmap...)
+		}
+		buffers = append(buffers, []int64{int64(start), int64(len(body) - start)})
+		body = append(body, make([]byte, align8(len(body))-len(body))...)
+
+		start = len(body)
+		body = appendValues(body, f.Type, c, rb.Length)
+		buffers = append(buffers, []int64{int64(start), int64(len(body) - start)})
+		body = append(body, make([]byte, align8(len(body))-len(body))...)
+	}
+
+	meta = encodeMessage(headerRecordBatch, func(b *builder) int {
+		nv := b.createStructVector(nodes)
+		bv := b.createStructVector(buffers)
+		b.startTable(5)
+		b.addI64(0, int64(rb.Length))
+		b.addOffset(1, nv)
+		b.addOffset(2, bv)
+		return b.endTable()
+	}, len(body))
+	return meta, body
+}
+
+// appendValues appends the little-endian values of column c
+func appendValues(body []byte, t DataType, c *Column, n int) []byte {
+	le := binary.LittleEndian
+	for j := 0; j < n; j++ {
+		switch t {
+		case Float16:
+			body = le.AppendUint16(body, c.Float16[j].Bits())
+		case Float32:
+			body = le.AppendUint32(body, math.Float32bits(c.Float32[j]))
+		case Int8:
+			body = append(body, byte(c.Int[j]))
+		case Int16:
+			body = le.AppendUint16(body, uint16(c.Int[j]))
+		case Int32:
+			body = le.AppendUint32(body, uint32(c.Int[j]))
+		case Int64:
+			body = le.AppendUint64(body, uint64(c.Int[j]))
+		case Uint8:
+			body = append(body, byte(c.Uint[j]))
+		case Uint16:
+			body = le.AppendUint16(body, uint16(c.Uint[j]))
+		case Uint32:
+			body = le.AppendUint32(body, uint32(c.Uint[j]))
+		case Uint64:
+			body = le.AppendUint64(body, c.Uint[j])
+		}
+	}
+	return body
+}
+
+// decodeRecordBatch reads a RecordBatch table and its body
+func decodeRecordBatch(t table, body []byte, schema *Schema) (*RecordBatch, error) {
+	if _, ok := t.table(3); ok {
+		return nil, fmt.Errorf("%w: compressed record batch", ErrUnsupported)
+	}
+	length := t.i64(0, 0)
+	if length < 0 || length > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: record batch length %d", ErrFormat, length)
+	}
+	rb := &RecordBatch{Schema: schema, Length: int(length), Columns: make([]Column, len(schema.Fields))}
+
+	nodeStart, nNodes := t.vector(1)
+	bufStart, nBufs := t.vector(2)
+	if nNodes != len(schema.Fields) || nBufs != 2*len(schema.Fields) {
+		return nil, fmt.Errorf("%w: %d nodes and %d buffers for %d fields", ErrFormat, nNodes, nBufs, len(schema.Fields))
+	}
+
+	// buffer returns the bytes of buffer i, checked against the body
+	buffer := func(i int) ([]byte, error) {
+		off := int64(readU64(t.buf, bufStart+16*i))
+		n := int64(readU64(t.buf, bufStart+16*i+8))
+		if off < 0 || n < 0 || off > int64(len(body)) || n > int64(len(body))-off {
+			return nil, fmt.Errorf("%w: buffer %d out of range", ErrFormat, i)
+		}
+		return body[off : off+n], nil
+	}
+
+	for i, f := range schema.Fields {
+		nodeLen := int64(readU64(t.buf, nodeStart+16*i))
+		nullCount := int64(readU64(t.buf, nodeStart+16*i+8))
+		if nodeLen != length || nullCount < 0 || nullCount > length {
+			return nil, fmt.Errorf("%w: column %q has length %d and %d nulls", ErrFormat, f.Name, nodeLen, nullCount)
+		}
+		n := int(length)
+
+		validity, err := buffer(2 * i)
+		if err != nil {
+			return nil, err
+		}
+		values, err := buffer(2*i + 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) < n*f.Type.width() {
+			return nil, fmt.Errorf("%w: column %q values buffer too short", ErrFormat, f.Name)
+		}
+
+		c := &rb.Columns[i]
+		if nullCount > 0 {
+			if len(validity) < (n+7)/8 {
+				return nil, fmt.Errorf("%w: column %q validity bitmap too short", ErrFormat, f.Name)
+			}
+			c.Nulls = make([]bool, n)
+			for j := range c.Nulls {
+				c.Nulls[j] = validity[j/8]&(1<<(j%8)) == 0
+			}
+		}
+		decodeValues(c, f.Type, values, n)
+	}
+	return rb, nil
+}
+
+// decodeValues fills the value slice of c from little-endian data
+func decodeValues(c *Column, t DataType, data []byte, n int) {
+	le := binary.LittleEndian
+	switch {
+	case t == Float16:
+		c.Float16 = make([]float16.Float16, n)
+		float16.DecodeFloat16s(c.Float16, data, le)
+	case t == Float32:
+		c.Float32 = make([]float32, n)
+		for j := range c.Float32 {
+			c.Float32[j] = math.Float32frombits(le.Uint32(data[4*j:]))
+		}
+	case t.signed():
+		c.Int = make([]int64, n)
+		for j := range c.Int {
+			switch t {
+			case Int8:
+				c.Int[j] = int64(int8(data[j]))
+			case Int16:
+				c.Int[j] = int64(int16(le.Uint16(data[2*j:])))
+			case Int32:
+				c.Int[j] = int64(int32(le.Uint32(data[4*j:])))
+			case Int64:
+				c.Int[j] = int64(le.Uint64(data[8*j:]))
+			}
+		}
+	default:
+		c.Uint = make([]uint64, n)
+		for j := range c.Uint {
+			switch t {
+			case Uint8:
+				c.Uint[j] = uint64(data[j])
+			case Uint16:
+				c.Uint[j] = uint64(le.Uint16(data[2*j:]))
+			case Uint32:
+				c.Uint[j] = uint64(le.Uint32(data[4*j:]))
+			case Uint64:
+				c.Uint[j] = le.Uint64(data[8*j:])
+			}
+		}
+	}
+}
+
+// writeMessage writes an encapsulated message: continuation marker, padded
+// metadata length, metadata, padding and body. It returns the size of the
+// prefix and metadata, and the total bytes written.
+func writeMessage(w io.Writer, meta, body []byte) (metaLen, total int, err error) {
+	padded := align8(len(meta))
+	var prefix [8]byte
+	binary.LittleEndian.PutUint32(prefix[:], continuation)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(padded))
+
+	buf := make([]byte, 0, 8+padded)
+	buf = append(buf, prefix[:]...)
+	buf = append(buf, meta...)
+	buf = append(buf, make([]byte, padded-len(meta))...)
+	if _, err := w.Write(buf); err != nil {
+		return 0, 0, err
+	}
+	if _, err := w.Write(body); err != nil {
+		return 0, 0, err
+	}
+	return len(buf), len(buf) + len(body), nil
+}
+
+// writeEOS writes the end-of-stream marker
+func writeEOS(w io.Writer) error {
+	var eos [8]byte
+	binary.LittleEndian.PutUint32(eos[:], continuation)
+	_, err := w.Write(eos[:])
+	return err
+}
+
+// message is a decoded encapsulated message
+type message struct {
+	headerType uint8
+	header     table
+	body       []byte
+}
+
+// readMessage reads the next encapsulated message, returning io.EOF at the
+// end-of-stream marker or a clean end of input
+func readMessage(r io.Reader) (*message, error) {
+	var word [4]byte
+	if _, err := io.ReadFull(r, word[:]); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: truncated message", ErrFormat)
+		}
+		return nil, err
+	}
+	size := binary.LittleEndian.Uint32(word[:])
+	if size == continuation {
+		if _, err := io.ReadFull(r, word[:]); err != nil {
+			return nil, fmt.Errorf("%w: truncated message", ErrFormat)
+		}
+		size = binary.LittleEndian.Uint32(word[:])
+	}
+	if size == 0 {
+		return nil, io.EOF
+	}
+	if size > maxMetadataSize {
+		return nil, fmt.Errorf("%w: metadata length %d", ErrFormat, size)
+	}
+
+	meta := make([]byte, size)
+	if _, err := io.ReadFull(r, meta); err != nil {
+		return nil, fmt.Errorf("%w: truncated metadata", ErrFormat)
+	}
+
+	m := &message{}
+	var bodyLen int64
+	err := decode(func() error {
+		root := rootTable(meta)
+		if v := root.i16(0, 0); v != metadataV4 && v != metadataV5 {
+			return fmt.Errorf("%w: metadata version %d", ErrUnsupported, v)
+		}
+		m.headerType = root.u8(1, 0)
+		hdr, ok := root.table(2)
+		if !ok {
+			return fmt.Errorf("%w: message has no header", ErrFormat)
+		}
+		m.header = hdr
+		bodyLen = root.i64(3, 0)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bodyLen < 0 {
+		return nil, fmt.Errorf("%w: body length %d", ErrFormat, bodyLen)
+	}
+
+	// Grow the body as data arrives so a corrupt length cannot force a huge allocation
+	var body bytes.Buffer
+	if _, err := io.CopyN(&body, r, bodyLen); err != nil {
+		return nil, fmt.Errorf("%w: truncated body", ErrFormat)
+	}
+	m.body = body.Bytes()
+	return m, nil
+}
+
+// schemaMessage encodes s as a Schema message
+func schemaMessage(s *Schema) []byte {
+	return encodeMessage(headerSchema, func(b *builder) int {
+		return encodeSchemaTable(b, s)
+	}, 0)
+}
+
+// readSchema reads a Schema message
+func readSchema(r io.Reader) (*Schema, error) {
+	m, err := readMessage(r)
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: missing schema", ErrFormat)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.headerType != headerSchema {
+		return nil, fmt.Errorf("%w: expected schema message, got type %d", ErrFormat, m.headerType)
+	}
+	var s *Schema
+	err = decode(func() error {
+		var err error
+		s, err = decodeSchemaTable(m.header)
+		return err
+	})
+	return s, err
+}
+
+// readRecordBatch reads the next RecordBatch message for schema
+func readRecordBatch(r io.Reader, schema *Schema) (*RecordBatch, error) {
+	m, err := readMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	switch m.headerType {
+	case headerRecordBatch:
+	case headerDictionaryBatch:
+		return nil, fmt.Errorf("%w: dictionary batch", ErrUnsupported)
+	default:
+		return nil, fmt.Errorf("%w: unexpected message type %d", ErrFormat, m.headerType)
+	}
+	var rb *RecordBatch
+	err = decode(func() error {
+		var err error
+		rb, err = decodeRecordBatch(m.header, m.body, schema)
+		return err
+	})
+	return rb, err
+}
@@ -0,0 +1,103 @@
+package arrow
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// StreamWriter writes record batches in the Arrow IPC streaming format
+type StreamWriter struct {
+	w      io.Writer
+	schema *Schema
+	closed bool
+}
+
+// NewStreamWriter writes the schema message to w and returns a writer for
+// record batches of that schema
+func NewStreamWriter(w io.Writer, schema *Schema) (*StreamWriter, error) {
+	if err := checkSchema(schema); err != nil {
+		return nil, err
+	}
+	if _, _, err := writeMessage(w, schemaMessage(schema), nil); err != nil {
+		return nil, err
+	}
+	return &StreamWriter{w: w, schema: schema}, nil
+}
+
+// Write appends a record batch to the stream
+func (sw *StreamWriter) Write(rb *RecordBatch) error {
+	if sw.closed {
+		return errors.New("arrow: write to closed stream")
+	}
+	if err := checkBatch(sw.schema, rb); err != nil {
+		return err
+	}
+	meta, body := encodeRecordBatch(rb)
+	_, _, err := writeMessage(sw.w, meta, body)
+	return err
+}
+
+// Close writes the end-of-stream marker. It does not close the underlying
+// writer.
+func (sw *StreamWriter) Close() error {
+	if sw.closed {
+		return nil
+	}
+	sw.closed = true
+	return writeEOS(sw.w)
+}
+
+// StreamReader reads record batches from an Arrow IPC stream
+type StreamReader struct {
+	r      io.Reader
+	schema *Schema
+}
+
+// NewStreamReader reads the schema message from r
+func NewStreamReader(r io.Reader) (*StreamReader, error) {
+	schema, err := readSchema(r)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamReader{r: r, schema: schema}, nil
+}
+
+// Schema returns the schema of the stream
+func (sr *StreamReader) Schema() *Schema {
+	return sr.schema
+}
+
+// Next returns the next record batch, or io.EOF at the end of the stream
+func (sr *StreamReader) Next() (*RecordBatch, error) {
+	return readRecordBatch(sr.r, sr.schema)
+}
+
+// checkSchema rejects schemas with unknown field types
+func checkSchema(schema *Schema) error {
+	if schema == nil {
+		return fmt.Errorf("%w: nil schema", ErrSchema)
+	}
+	for _, f := range schema.Fields {
+		if f.Type.width() == 0 {
+			return fmt.Errorf("%w: field %q has unknown type %v", ErrSchema, f.Name, f.Type)
+		}
+	}
+	return nil
+}
+
+// checkBatch validates rb and checks that it uses the writer's schema
+func checkBatch(schema *Schema, rb *RecordBatch) error {
+	if err := rb.Validate(); err != nil {
+		return err
+	}
+	if len(rb.Schema.Fields) != len(schema.Fields) {
+		return fmt.Errorf("%w: column count", ErrSchema)
+	}
+	for i, f := range rb.Schema.Fields {
+		if f != schema.Fields[i] {
+			return fmt.Errorf("%w: field %d is %q %v, want %q %v", ErrSchema, i, f.Name, f.Type, schema.Fields[i].Name, schema.Fields[i].Type)
+		}
+	}
+	return nil
+}
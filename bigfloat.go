@@ -0,0 +1,106 @@
+package float16
+
+import (
+	"math"
+	"math/big"
+)
+
+// Conversions to and from math/big. Every finite Float16 is a dyadic
+// rational m × 2^k with |m| < 2^11 and k ≥ -24, so it is exact as a
+// big.Float or big.Rat and has a terminating decimal expansion of at most
+// 24 fractional digits.
+
+// BigFloat returns f as a *big.Float with precision 53, like big.NewFloat;
+// the value is exact, infinities included. BigFloat panics with a
+// big.ErrNaN if f is NaN, which big.Float cannot represent.
+func (f Float16) BigFloat() *big.Float {
+	if f.IsNaN() {
+		panic(big.ErrNaN{})
+	}
+	return new(big.Float).SetFloat64(f.ToFloat64())
+}
+
+// Rat returns the exact value of f as a *big.Rat, or nil if f is not
+// finite, like big.Rat.SetFloat64. Both zeros give 0.
+func (f Float16) Rat() *big.Rat {
+	if !f.IsFinite() {
+		return nil
+	}
+	return new(big.Rat).SetFloat64(f.ToFloat64())
+}
+
+// ExactDecimal returns the exact decimal expansion of f, such as
+// "0.000000059604644775390625" for SmallestSubnormal, without an exponent
+// or trailing zeros. Zeros keep their sign; infinities and NaN are
+// formatted as by String.
+func (f Float16) ExactDecimal() string {
+	switch {
+	case !f.IsFinite():
+		return f.String()
+	case f == NegativeZero:
+		return "-0"
+	}
+	r := f.Rat()
+	// A denominator of 2^k needs exactly k fractional digits
+	return r.FloatString(r.Denom().BitLen() - 1)
+}
+
+// FromBigFloat rounds x to Float16 in the given rounding mode, producing
+// subnormals, and ±Inf or ±MaxValue on overflow, as IEEE 754 requires. The
+// accuracy reports whether the result is below, equal to or above x. Only
+// one rounding takes place, whatever the precision of x.
+func FromBigFloat(x *big.Float, rounding RoundingMode) (Float16, big.Accuracy) {
+	var sign Float16
+	if x.Signbit() {
+		sign = SignMask
+	}
+	switch {
+	case x.IsInf():
+		return sign | PositiveInfinity, big.Exact
+	case x.Sign() == 0:
+		return sign, big.Exact
+	}
+
+	// For |x| in [2^(e-1), 2^e) the Float16 quantum is 2^(e-11), and never
+	// below 2^-24. Measured in quanta, |x| = n is below 2^11.
+	e := x.MantExp(nil)
+	q := max(e-11, -24)
+	n := new(big.Float).SetMantExp(x, -q)
+	n.Abs(n)
+	i, _ := n.Int64() // truncated
+	frac := new(big.Float).Sub(n, new(big.Float).SetInt64(i))
+
+	if frac.Sign() != 0 {
+		half := frac.Cmp(big.NewFloat(0.5))
+		var up bool
+		switch rounding {
+		case RoundNearestEven:
+			up = half > 0 || (half == 0 && i&1 == 1)
+		case RoundNearestAway:
+			up = half >= 0
+		case RoundTowardPositive:
+			up = sign == 0
+		case RoundTowardNegative:
+			up = sign != 0
+		}
+		if up {
+			i++
+		}
+	}
+
+	var result Float16
+	// i × 2^q is exact in float64 unless it overflows Float16 as well
+	if v := math.Ldexp(float64(i), q); v > MaxValue.ToFloat64() {
+		result = overflowResult(uint16(sign), rounding)
+	} else {
+		result = sign | FromFloat64(v)
+	}
+
+	switch result.BigFloat().Cmp(x) {
+	case -1:
+		return result, big.Below
+	case 1:
+		return result, big.Above
+	}
+	return result, big.Exact
+}
@@ -0,0 +1,135 @@
+package float16
+
+import (
+	"math/big"
+	"testing"
+)
+
+var allRoundingModes = []RoundingMode{RoundNearestEven, RoundNearestAway, RoundTowardZero, RoundTowardPositive, RoundTowardNegative}
+
+func TestBigFloatExact(t *testing.T) {
+	for b := 0; b < 1<<16; b++ {
+		f := FromBits(uint16(b))
+		if f.IsNaN() {
+			continue
+		}
+		x := f.BigFloat()
+		if v, acc := x.Float64(); v != f.ToFloat64() || acc != big.Exact || x.Signbit() != f.Signbit() {
+			t.Fatalf("BigFloat(%#04x) = %v, want %v", b, x, f.ToFloat64())
+		}
+		for _, rounding := range allRoundingModes {
+			if got, acc := FromBigFloat(x, rounding); got != f || acc != big.Exact {
+				t.Fatalf("FromBigFloat(BigFloat(%#04x), %v) = %#04x, %v", b, rounding, uint16(got), acc)
+			}
+		}
+
+		if !f.IsFinite() {
+			if f.Rat() != nil {
+				t.Fatalf("Rat(%v) = %v, want nil", f, f.Rat())
+			}
+			continue
+		}
+		if r := f.Rat(); new(big.Float).SetRat(r).Cmp(x) != 0 {
+			t.Fatalf("Rat(%#04x) = %v, want %v", b, r, x)
+		}
+		// The decimal expansion is exact
+		r, ok := new(big.Rat).SetString(f.ExactDecimal())
+		if !ok || r.Cmp(f.Rat()) != 0 {
+			t.Fatalf("ExactDecimal(%#04x) = %q", b, f.ExactDecimal())
+		}
+	}
+}
+
+func TestBigFloatSpecialValues(t *testing.T) {
+	tests := []struct {
+		f    Float16
+		want string
+	}{
+		{SmallestSubnormal, "0.000000059604644775390625"},
+		{SmallestSubnormal | SignMask, "-0.000000059604644775390625"},
+		{MaxValue, "65504"},
+		{FromFloat32(0.1), "0.0999755859375"},
+		{FromFloat32(1.5), "1.5"},
+		{PositiveZero, "0"},
+		{NegativeZero, "-0"},
+		{NegativeInfinity, "-Inf"},
+		{QuietNaN, "NaN"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.ExactDecimal(); got != tt.want {
+			t.Errorf("ExactDecimal(%#04x) = %q, want %q", uint16(tt.f), got, tt.want)
+		}
+	}
+
+	defer func() {
+		if _, ok := recover().(big.ErrNaN); !ok {
+			t.Error("BigFloat(NaN) did not panic with big.ErrNaN")
+		}
+	}()
+	QuietNaN.BigFloat()
+}
+
+func TestFromBigFloatRounding(t *testing.T) {
+	// Points between each pair of neighbours, including ties, are exact in
+	// float64, where FromFloat64WithMode is the reference
+	for b := 0; b < 0x7C00; b++ {
+		lo, hi := FromBits(uint16(b)), FromBits(uint16(b+1))
+		for _, frac := range []float64{0.25, 0.5, 0.75} {
+			for _, sign := range []float64{1, -1} {
+				v := sign * (lo.ToFloat64() + frac*(hi.ToFloat64()-lo.ToFloat64()))
+				x := big.NewFloat(v)
+				for _, rounding := range allRoundingModes {
+					want, _ := FromFloat64WithMode(v, ModeIEEE, rounding)
+					got, acc := FromBigFloat(x, rounding)
+					if got != want {
+						t.Fatalf("FromBigFloat(%v, %v) = %v, want %v", v, rounding, got, want)
+					}
+					if wantAcc := big.Accuracy(want.BigFloat().Cmp(x)); acc != wantAcc {
+						t.Fatalf("FromBigFloat(%v, %v) accuracy = %v, want %v", v, rounding, acc, wantAcc)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFromBigFloatBeyondFloat64(t *testing.T) {
+	// nudge returns v + d × 2^-150, which float64 cannot hold
+	nudge := func(v, d float64) *big.Float {
+		x := new(big.Float).SetPrec(200).SetFloat64(v)
+		return x.Add(x, new(big.Float).SetMantExp(big.NewFloat(d), -150))
+	}
+	pow2 := func(sign float64, exp int) *big.Float {
+		return new(big.Float).SetMantExp(big.NewFloat(sign), exp)
+	}
+	one := FromFloat32(1)
+	tests := []struct {
+		name     string
+		x        *big.Float
+		rounding RoundingMode
+		want     Float16
+		acc      big.Accuracy
+	}{
+		{"1 + half ulp + tiny", nudge(1+1.0/2048, 1), RoundNearestEven, NextAfter(one, PositiveInfinity), big.Above},
+		{"1 + half ulp - tiny", nudge(1+1.0/2048, -1), RoundNearestAway, one, big.Below},
+		{"1 + tiny, up", nudge(1, 1), RoundTowardPositive, NextAfter(one, PositiveInfinity), big.Above},
+		{"1 + tiny, nearest", nudge(1, 1), RoundNearestEven, one, big.Below},
+		{"2^-1000", pow2(1, -1000), RoundNearestEven, PositiveZero, big.Below},
+		{"2^-1000, up", pow2(1, -1000), RoundTowardPositive, SmallestSubnormal, big.Above},
+		{"-2^-1000, down", pow2(-1, -1000), RoundTowardNegative, SmallestSubnormal | SignMask, big.Below},
+		{"-2^-1000, nearest", pow2(-1, -1000), RoundNearestEven, NegativeZero, big.Above},
+		{"65520 - tiny", nudge(65520, -1), RoundNearestEven, MaxValue, big.Below},
+		{"65520", big.NewFloat(65520), RoundNearestEven, PositiveInfinity, big.Above},
+		{"2^100000", pow2(1, 100000), RoundNearestEven, PositiveInfinity, big.Above},
+		{"-2^100000, toward zero", pow2(-1, 100000), RoundTowardZero, MinValue, big.Above},
+		{"-Inf", new(big.Float).SetInf(true), RoundTowardZero, NegativeInfinity, big.Exact},
+		{"-0", new(big.Float).Neg(new(big.Float)), RoundNearestEven, NegativeZero, big.Exact},
+	}
+
+	for _, tt := range tests {
+		got, acc := FromBigFloat(tt.x, tt.rounding)
+		if got != tt.want || acc != tt.acc {
+			t.Errorf("%s: FromBigFloat() = %v (%#04x), %v, want %v, %v", tt.name, got, uint16(got), acc, tt.want, tt.acc)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package float16
+
+import (
+	"encoding/binary"
+	"io"
+	"unsafe"
+)
+
+// Byte-level views and endian-aware encoding of Float16 slices
+
+// BytesAsFloat16s reinterprets b as a slice of Float16 values without copying.
+// The bytes are interpreted in host byte order, so the view is only portable
+// when the data was produced on a machine with the same endianness.
+// It returns an error if len(b) is odd or b is not 2-byte aligned.
+// The returned slice aliases b; writes to one are visible through the other.
+func BytesAsFloat16s(b []byte) ([]Float16, error) {
+	if len(b) == 0 {
+		return []Float16{}, nil
+	}
+	if len(b)%2 != 0 {
+		return nil, &Float16Error{
+			Op:    "bytes_as_float16s",
+			Value: len(b),
+			Msg:   "byte length is not a multiple of 2",
+			Code:  ErrInvalidOperation,
+		}
+	}
+	if uintptr(unsafe.Pointer(&b[0]))%unsafe.Alignof(Float16(0)) != 0 {
+		return nil, &Float16Error{
+			Op:   "bytes_as_float16s",
+			Msg:  "byte slice is not 2-byte aligned",
+			Code: ErrInvalidOperation,
+		}
+	}
+	return unsafe.Slice((*Float16)(unsafe.Pointer(&b[0])), len(b)/2), nil
+}
+
+// Float16sAsBytes reinterprets s as a slice of bytes in host byte order without copying.
+// The returned slice aliases s; writes to one are visible through the other.
+func Float16sAsBytes(s []Float16) []byte {
+	if len(s) == 0 {
+		return []byte{}
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&s[0])), len(s)*2)
+}
+
+// PutFloat16s encodes src into dst using the given byte order.
+// It panics if dst is shorter than 2*len(src), like binary.ByteOrder.PutUint16.
+func PutFloat16s(dst []byte, src []Float16, order binary.ByteOrder) {
+	_ = dst[:2*len(src)] // early bounds check
+	for i, v := range src {
+		order.PutUint16(dst[2*i:], uint16(v))
+	}
+}
+
+// AppendFloat16s appends the encoding of src to dst using the given byte order
+// and returns the extended buffer, in the style of binary.AppendByteOrder.
+func AppendFloat16s(dst []byte, order binary.AppendByteOrder, src ...Float16) []byte {
+	for _, v := range src {
+		dst = order.AppendUint16(dst, uint16(v))
+	}
+	return dst
+}
+
+// DecodeFloat16s decodes len(dst) values from src using the given byte order.
+// It panics if src is shorter than 2*len(dst).
+func DecodeFloat16s(dst []Float16, src []byte, order binary.ByteOrder) {
+	_ = src[:2*len(dst)] // early bounds check
+	for i := range dst {
+		dst[i] = Float16(order.Uint16(src[2*i:]))
+	}
+}
+
+// ReadFloat16s reads exactly len(dst) values from r using the given byte order.
+// The data is read directly into the memory backing dst, so no intermediate
+// buffer is allocated. Errors follow io.ReadFull: io.EOF if no bytes were read,
+// io.ErrUnexpectedEOF if only part of dst could be filled.
+func ReadFloat16s(r io.Reader, order binary.ByteOrder, dst []Float16) error {
+	buf := Float16sAsBytes(dst)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+	if order == binary.NativeEndian {
+		return nil
+	}
+	// Decode in place: each value only reads the two bytes it overwrites.
+	for i := range dst {
+		dst[i] = Float16(order.Uint16(buf[2*i:]))
+	}
+	return nil
+}
+
+// WriteFloat16s writes src to w using the given byte order.
+func WriteFloat16s(w io.Writer, order binary.ByteOrder, src []Float16) error {
+	if order == binary.NativeEndian {
+		_, err := w.Write(Float16sAsBytes(src))
+		return err
+	}
+	buf := make([]byte, 2*len(src))
+	PutFloat16s(buf, src, order)
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,137 @@
+package float16
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestBytesAsFloat16s(t *testing.T) {
+	src := []Float16{0x3C00, 0x4000, 0xBC00, PositiveInfinity}
+	raw := make([]byte, 2*len(src))
+	PutFloat16s(raw, src, binary.NativeEndian)
+
+	got, err := BytesAsFloat16s(raw)
+	if err != nil {
+		t.Fatalf("BytesAsFloat16s() unexpected error: %v", err)
+	}
+	if len(got) != len(src) {
+		t.Fatalf("BytesAsFloat16s() length = %d, want %d", len(got), len(src))
+	}
+	for i := range src {
+		if got[i] != src[i] {
+			t.Errorf("BytesAsFloat16s()[%d] = 0x%04X, want 0x%04X", i, got[i], src[i])
+		}
+	}
+
+	// The view aliases the original buffer
+	got[0] = 0x4200
+	if binary.NativeEndian.Uint16(raw) != 0x4200 {
+		t.Error("BytesAsFloat16s() result does not alias the input")
+	}
+}
+
+func TestBytesAsFloat16sErrors(t *testing.T) {
+	if _, err := BytesAsFloat16s(make([]byte, 3)); err == nil {
+		t.Error("BytesAsFloat16s() with odd length should fail")
+	}
+
+	buf := make([]Float16, 4)
+	misaligned := Float16sAsBytes(buf)[1:5]
+	_, err := BytesAsFloat16s(misaligned)
+	var ferr *Float16Error
+	if !errors.As(err, &ferr) || ferr.Code != ErrInvalidOperation {
+		t.Errorf("BytesAsFloat16s() misaligned error = %v, want ErrInvalidOperation", err)
+	}
+
+	empty, err := BytesAsFloat16s(nil)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("BytesAsFloat16s(nil) = %v, %v; want empty, nil", empty, err)
+	}
+}
+
+func TestFloat16sAsBytes(t *testing.T) {
+	s := []Float16{0x1234, 0xABCD}
+	b := Float16sAsBytes(s)
+	if len(b) != 4 {
+		t.Fatalf("Float16sAsBytes() length = %d, want 4", len(b))
+	}
+	if binary.NativeEndian.Uint16(b[2:]) != 0xABCD {
+		t.Errorf("Float16sAsBytes() second value = 0x%04X, want 0xABCD", binary.NativeEndian.Uint16(b[2:]))
+	}
+	if len(Float16sAsBytes(nil)) != 0 {
+		t.Error("Float16sAsBytes(nil) should be empty")
+	}
+}
+
+func TestPutAppendDecodeFloat16s(t *testing.T) {
+	src := []Float16{0x3C00, 0x7BFF, 0x8001}
+	orders := []struct {
+		name  string
+		order binary.ByteOrder
+		want  []byte
+	}{
+		{"little", binary.LittleEndian, []byte{0x00, 0x3C, 0xFF, 0x7B, 0x01, 0x80}},
+		{"big", binary.BigEndian, []byte{0x3C, 0x00, 0x7B, 0xFF, 0x80, 0x01}},
+	}
+
+	for _, tt := range orders {
+		t.Run(tt.name, func(t *testing.T) {
+			put := make([]byte, len(tt.want))
+			PutFloat16s(put, src, tt.order)
+			if !bytes.Equal(put, tt.want) {
+				t.Errorf("PutFloat16s() = % X, want % X", put, tt.want)
+			}
+
+			app := AppendFloat16s([]byte{0xEE}, tt.order.(binary.AppendByteOrder), src...)
+			if !bytes.Equal(app[1:], tt.want) || app[0] != 0xEE {
+				t.Errorf("AppendFloat16s() = % X, want EE % X", app, tt.want)
+			}
+
+			dec := make([]Float16, len(src))
+			DecodeFloat16s(dec, tt.want, tt.order)
+			for i := range src {
+				if dec[i] != src[i] {
+					t.Errorf("DecodeFloat16s()[%d] = 0x%04X, want 0x%04X", i, dec[i], src[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadWriteFloat16s(t *testing.T) {
+	src := []Float16{0x0000, 0x3C00, 0xC000, QuietNaN, NegativeInfinity}
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian, binary.NativeEndian} {
+		var buf bytes.Buffer
+		if err := WriteFloat16s(&buf, order, src); err != nil {
+			t.Fatalf("WriteFloat16s(%v) unexpected error: %v", order, err)
+		}
+		if buf.Len() != 2*len(src) {
+			t.Fatalf("WriteFloat16s(%v) wrote %d bytes, want %d", order, buf.Len(), 2*len(src))
+		}
+
+		dst := make([]Float16, len(src))
+		if err := ReadFloat16s(&buf, order, dst); err != nil {
+			t.Fatalf("ReadFloat16s(%v) unexpected error: %v", order, err)
+		}
+		for i := range src {
+			if dst[i] != src[i] {
+				t.Errorf("ReadFloat16s(%v)[%d] = 0x%04X, want 0x%04X", order, i, dst[i], src[i])
+			}
+		}
+	}
+}
+
+func TestReadFloat16sShort(t *testing.T) {
+	dst := make([]Float16, 2)
+	err := ReadFloat16s(bytes.NewReader([]byte{0x00, 0x3C, 0x00}), binary.LittleEndian, dst)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadFloat16s() short read error = %v, want io.ErrUnexpectedEOF", err)
+	}
+	err = ReadFloat16s(bytes.NewReader(nil), binary.LittleEndian, dst)
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("ReadFloat16s() empty read error = %v, want io.EOF", err)
+	}
+}
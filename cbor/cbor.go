@@ -0,0 +1,165 @@
+// Package cbor encodes and decodes CBOR (RFC 8949) data items, with
+// floating-point values carried as IEEE 754 half precision whenever that is
+// lossless.
+//
+// Floats are written in preferred serialisation: the shortest of half,
+// single or double precision that represents the value exactly, using
+// float16.FromFloat64WithMode in ModeExact as the exactness check.
+// Infinities always fit in half precision; NaNs keep their payload and use
+// the shortest width that does not drop payload bits. The deterministic
+// variant (RFC 8949 section 4.2.2) writes every NaN as the half quiet NaN
+// 0xf97e00. Half-precision values are decoded through Float16.ToFloat64.
+//
+// Besides the float codec the package offers Append functions for the other
+// item types, Marshal for common Go values, and a Decoder that turns items
+// into generic Go values.
+//
+// See: https://www.rfc-editor.org/rfc/rfc8949.html
+package cbor
+
+import (
+	"errors"
+	"math"
+)
+
+// Major types
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// Additional information values with special meaning
+const (
+	aiOneByte    = 24
+	aiTwoBytes   = 25
+	aiFourBytes  = 26
+	aiEightBytes = 27
+	aiIndefinite = 31
+)
+
+// Simple values of major type 7
+const (
+	simpleFalse     = 20
+	simpleTrue      = 21
+	simpleNull      = 22
+	simpleUndefined = 23
+)
+
+// Initial bytes of the three float encodings
+const (
+	headHalf   = majorSimple<<5 | aiTwoBytes
+	headSingle = majorSimple<<5 | aiFourBytes
+	headDouble = majorSimple<<5 | aiEightBytes
+	headBreak  = majorSimple<<5 | aiIndefinite
+)
+
+// Errors returned by the encoder and decoder
+var (
+	ErrSyntax      = errors.New("cbor: malformed data item")
+	ErrUnsupported = errors.New("cbor: unsupported value")
+)
+
+// Tag is a tagged data item (major type 6)
+type Tag struct {
+	Number  uint64
+	Content interface{}
+}
+
+// Simple is a simple value (major type 7) other than false, true, null,
+// undefined and floats
+type Simple uint8
+
+// Undefined is the CBOR undefined value
+type Undefined struct{}
+
+// appendHead appends the initial byte and argument of a data item, using the
+// shortest argument encoding
+func appendHead(dst []byte, major byte, n uint64) []byte {
+	m := major << 5
+	switch {
+	case n < aiOneByte:
+		return append(dst, m|byte(n))
+	case n <= math.MaxUint8:
+		return append(dst, m|aiOneByte, byte(n))
+	case n <= math.MaxUint16:
+		return append(dst, m|aiTwoBytes, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		return append(dst, m|aiFourBytes, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(dst, m|aiEightBytes, byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32),
+		byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+// AppendUint appends an unsigned integer
+func AppendUint(dst []byte, v uint64) []byte {
+	return appendHead(dst, majorUint, v)
+}
+
+// AppendInt appends a signed integer, using major type 1 for negative values
+func AppendInt(dst []byte, v int64) []byte {
+	if v < 0 {
+		return appendHead(dst, majorNegInt, uint64(-1-v))
+	}
+	return appendHead(dst, majorUint, uint64(v))
+}
+
+// AppendBytes appends a byte string
+func AppendBytes(dst, b []byte) []byte {
+	return append(appendHead(dst, majorBytes, uint64(len(b))), b...)
+}
+
+// AppendString appends a text string. s should be valid UTF-8.
+func AppendString(dst []byte, s string) []byte {
+	return append(appendHead(dst, majorText, uint64(len(s))), s...)
+}
+
+// AppendArrayHeader appends the header of an array of n items; the items
+// must follow
+func AppendArrayHeader(dst []byte, n int) []byte {
+	return appendHead(dst, majorArray, uint64(n))
+}
+
+// AppendMapHeader appends the header of a map of n pairs; the keys and
+// values must follow, alternating
+func AppendMapHeader(dst []byte, n int) []byte {
+	return appendHead(dst, majorMap, uint64(n))
+}
+
+// AppendTag appends a tag number; the tagged item must follow
+func AppendTag(dst []byte, number uint64) []byte {
+	return appendHead(dst, majorTag, number)
+}
+
+// AppendBool appends true or false
+func AppendBool(dst []byte, v bool) []byte {
+	if v {
+		return append(dst, majorSimple<<5|simpleTrue)
+	}
+	return append(dst, majorSimple<<5|simpleFalse)
+}
+
+// AppendNull appends null
+func AppendNull(dst []byte) []byte {
+	return append(dst, majorSimple<<5|simpleNull)
+}
+
+// AppendUndefined appends undefined
+func AppendUndefined(dst []byte) []byte {
+	return append(dst, majorSimple<<5|simpleUndefined)
+}
+
+// AppendSimple appends a simple value. Values 24 to 31 are reserved and
+// values 20 to 23 should be written with AppendBool, AppendNull or
+// AppendUndefined.
+func AppendSimple(dst []byte, v Simple) []byte {
+	if v < aiOneByte {
+		return append(dst, majorSimple<<5|byte(v))
+	}
+	return append(dst, majorSimple<<5|aiOneByte, byte(v))
+}
@@ -0,0 +1,340 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Floating-point examples from RFC 8949 Appendix A
+var rfcFloats = []struct {
+	f   float64
+	hex string
+}{
+	{0.0, "f90000"},
+	{math.Copysign(0, -1), "f98000"},
+	{1.0, "f93c00"},
+	{1.1, "fb3ff199999999999a"},
+	{1.5, "f93e00"},
+	{65504.0, "f97bff"},
+	{100000.0, "fa47c35000"},
+	{3.4028234663852886e+38, "fa7f7fffff"},
+	{1.0e+300, "fb7e37e43c8800759c"},
+	{5.960464477539063e-8, "f90001"},
+	{0.00006103515625, "f90400"},
+	{-4.0, "f9c400"},
+	{-4.1, "fbc010666666666666"},
+	{math.Inf(1), "f97c00"},
+	{math.Float64frombits(0x7FF8000000000000), "f97e00"},
+	{math.Inf(-1), "f9fc00"},
+}
+
+func TestAppendFloat(t *testing.T) {
+	for _, tt := range rfcFloats {
+		got := hex.EncodeToString(AppendFloat(nil, tt.f))
+		if got != tt.hex {
+			t.Errorf("AppendFloat(%g) = %s, want %s", tt.f, got, tt.hex)
+		}
+	}
+}
+
+func TestDecodeFloat(t *testing.T) {
+	cases := append([]struct {
+		f   float64
+		hex string
+	}{
+		// Non-preferred encodings from Appendix A decode to the same values
+		{math.Inf(1), "fa7f800000"},
+		{math.NaN(), "fa7fc00000"},
+		{math.Inf(-1), "faff800000"},
+		{math.Inf(1), "fb7ff0000000000000"},
+		{math.NaN(), "fb7ff8000000000000"},
+		{float16.FromBits(0x03FF).ToFloat64(), "f903ff"},
+	}, rfcFloats...)
+
+	for _, tt := range cases {
+		b := mustHex(tt.hex)
+		got, n, err := DecodeFloat(append(b, 0xAA))
+		if err != nil {
+			t.Errorf("DecodeFloat(%s) unexpected error: %v", tt.hex, err)
+			continue
+		}
+		if n != len(b) {
+			t.Errorf("DecodeFloat(%s) size = %d, want %d", tt.hex, n, len(b))
+		}
+		if math.IsNaN(tt.f) {
+			if !math.IsNaN(got) {
+				t.Errorf("DecodeFloat(%s) = %g, want NaN", tt.hex, got)
+			}
+		} else if got != tt.f || math.Signbit(got) != math.Signbit(tt.f) {
+			t.Errorf("DecodeFloat(%s) = %g, want %g", tt.hex, got, tt.f)
+		}
+	}
+
+	for _, bad := range []string{"", "f9", "fa7f80", "01", "f7"} {
+		if _, _, err := DecodeFloat(mustHex(bad)); !errors.Is(err, ErrSyntax) {
+			t.Errorf("DecodeFloat(%q) error = %v, want ErrSyntax", bad, err)
+		}
+	}
+}
+
+func TestFloatRoundTripAllHalves(t *testing.T) {
+	for i := 0; i <= math.MaxUint16; i++ {
+		h := float16.FromBits(uint16(i))
+		if h.IsNaN() {
+			continue
+		}
+		enc := AppendFloat(nil, h.ToFloat64())
+		if len(enc) != 3 || !bytes.Equal(enc, AppendFloat16(nil, h)) {
+			t.Fatalf("AppendFloat(%v) = %x, want half precision f9%04x", h, enc, i)
+		}
+		got, _, err := DecodeFloat(enc)
+		if err != nil || got != h.ToFloat64() || math.Signbit(got) != h.Signbit() {
+			t.Fatalf("DecodeFloat(%x) = %g, %v, want %g", enc, got, err, h.ToFloat64())
+		}
+	}
+}
+
+func TestFloatWidths(t *testing.T) {
+	tests := []struct {
+		name string
+		f    float64
+		size int
+	}{
+		{"half subnormal", math.Ldexp(3, -24), 3},
+		{"below half subnormal", math.Ldexp(1, -25), 5},
+		{"half plus one ulp of single", 1 + math.Ldexp(1, -23), 5},
+		{"above half range", 65536, 5},
+		{"single subnormal", math.Ldexp(1, -149), 5},
+		{"double only", math.Ldexp(1, -150), 9},
+		{"max float64", math.MaxFloat64, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := AppendFloat(nil, tt.f)
+			if len(enc) != tt.size {
+				t.Errorf("AppendFloat(%g) = %x, want %d bytes", tt.f, enc, tt.size)
+			}
+			if got, _, _ := DecodeFloat(enc); got != tt.f {
+				t.Errorf("round trip of %g = %g", tt.f, got)
+			}
+		})
+	}
+}
+
+func TestNaNPayloads(t *testing.T) {
+	tests := []struct {
+		bits uint64
+		hex  string
+	}{
+		{0x7FF8000000000000, "f97e00"},
+		{0xFFF8000000000000, "f9fe00"},
+		{0x7FF0040000000000, "f97c01"},             // signalling NaN, payload fits in half
+		{0x7FF8000020000000, "fa7fc00001"},         // needs single precision
+		{0x7FF8000000000001, "fb7ff8000000000001"}, // needs double precision
+		{0xFFF4000000000000, "f9fd00"},             // negative signalling NaN
+		{0x7FFFFFFFE0000000, "fa7fffffff"},         // all single mantissa bits set
+		{0x7FF0000000000001, "fb7ff0000000000001"}, // smallest payload
+	}
+	for _, tt := range tests {
+		f := math.Float64frombits(tt.bits)
+		if got := hex.EncodeToString(AppendFloat(nil, f)); got != tt.hex {
+			t.Errorf("AppendFloat(NaN %#016x) = %s, want %s", tt.bits, got, tt.hex)
+		}
+		if got := hex.EncodeToString(AppendFloatDeterministic(nil, f)); got != "f97e00" {
+			t.Errorf("AppendFloatDeterministic(NaN %#016x) = %s, want f97e00", tt.bits, got)
+		}
+	}
+	if got := hex.EncodeToString(AppendFloatDeterministic(nil, 1.1)); got != "fb3ff199999999999a" {
+		t.Errorf("AppendFloatDeterministic(1.1) = %s", got)
+	}
+}
+
+// Other examples from RFC 8949 Appendix A, in diagnostic form as Go values
+func TestUnmarshalRFCExamples(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want interface{}
+	}{
+		{"00", uint64(0)},
+		{"17", uint64(23)},
+		{"1818", uint64(24)},
+		{"1903e8", uint64(1000)},
+		{"1b000000e8d4a51000", uint64(1000000000000)},
+		{"1bffffffffffffffff", uint64(math.MaxUint64)},
+		{"20", int64(-1)},
+		{"3903e7", int64(-1000)},
+		{"f4", false},
+		{"f5", true},
+		{"f6", nil},
+		{"f7", Undefined{}},
+		{"f0", Simple(16)},
+		{"f8ff", Simple(255)},
+		{"c074323031332d30332d32315432303a30343a30305a", Tag{0, "2013-03-21T20:04:00Z"}},
+		{"d82076687474703a2f2f7777772e6578616d706c652e636f6d", Tag{32, "http://www.example.com"}},
+		{"40", []byte{}},
+		{"4401020304", []byte{1, 2, 3, 4}},
+		{"60", ""},
+		{"6449455446", "IETF"},
+		{"62c3bc", "ü"},
+		{"80", []interface{}{}},
+		{"83010203", []interface{}{uint64(1), uint64(2), uint64(3)}},
+		{"8301820203820405", []interface{}{uint64(1), []interface{}{uint64(2), uint64(3)}, []interface{}{uint64(4), uint64(5)}}},
+		{"a201020304", map[interface{}]interface{}{uint64(1): uint64(2), uint64(3): uint64(4)}},
+		{"a26161016162820203", map[interface{}]interface{}{"a": uint64(1), "b": []interface{}{uint64(2), uint64(3)}}},
+		{"5f42010243030405ff", []byte{1, 2, 3, 4, 5}},
+		{"7f657374726561646d696e67ff", "streaming"},
+		{"9fff", []interface{}{}},
+		{"9f018202039f0405ffff", []interface{}{uint64(1), []interface{}{uint64(2), uint64(3)}, []interface{}{uint64(4), uint64(5)}}},
+		{"bf61610161629f0203ffff", map[interface{}]interface{}{"a": uint64(1), "b": []interface{}{uint64(2), uint64(3)}}},
+		{"83f93c00fa47c35000fb3ff199999999999a", []interface{}{1.0, 100000.0, 1.1}},
+	}
+	for _, tt := range tests {
+		got, err := Unmarshal(mustHex(tt.hex))
+		if err != nil {
+			t.Errorf("Unmarshal(%s) unexpected error: %v", tt.hex, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Unmarshal(%s) = %#v, want %#v", tt.hex, got, tt.want)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		v   interface{}
+		hex string
+	}{
+		{uint64(1000000), "1a000f4240"},
+		{-1000, "3903e7"},
+		{int64(math.MinInt64), "3b7fffffffffffffff"},
+		{float32(1.5), "f93e00"},
+		{float32(100000), "fa47c35000"},
+		{float16.FromBits(0x7BFF), "f97bff"},
+		{[]float16.Float16{0x3C00, 0xC000}, "82f93c00f9c000"},
+		{[]float64{0.5, 1.1}, "82f93800fb3ff199999999999a"},
+		{[]interface{}{"a", map[string]interface{}{"b": "c"}}, "826161a161626163"},
+		{map[string]interface{}{"b": uint8(2), "a": int8(1), "aa": nil}, "a3616101616202626161f6"},
+		{map[interface{}]interface{}{10: true, -1: false, "z": 0.0}, "a30af520f4617af90000"},
+		{Tag{1, 1363896240.5}, "c1fb41d452d9ec200000"},
+		{[]byte("\x01\x02"), "420102"},
+		{Undefined{}, "f7"},
+		{Simple(255), "f8ff"},
+	}
+	for _, tt := range tests {
+		b, err := Marshal(tt.v)
+		if err != nil {
+			t.Errorf("Marshal(%#v) unexpected error: %v", tt.v, err)
+			continue
+		}
+		if got := hex.EncodeToString(b); got != tt.hex {
+			t.Errorf("Marshal(%#v) = %s, want %s", tt.v, got, tt.hex)
+		}
+	}
+
+	if _, err := Marshal(struct{}{}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Marshal(struct{}) error = %v, want ErrUnsupported", err)
+	}
+	if _, err := Marshal([]interface{}{complex(1, 2)}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Marshal([]interface{}{complex}) error = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	v := map[interface{}]interface{}{
+		"temp":    []interface{}{21.5, float16.FromFloat32(0.1).ToFloat64(), math.Copysign(0, -1)},
+		"id":      uint64(42),
+		"offset":  int64(-7),
+		"payload": []byte{0xDE, 0xAD},
+		"ok":      true,
+		uint64(7): Tag{Number: 1, Content: 1.0e300},
+	}
+	b, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+	got, err := Unmarshal(b)
+	if err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("round trip = %#v, want %#v", got, v)
+	}
+}
+
+func TestDecoderStream(t *testing.T) {
+	var stream []byte
+	for _, f := range []float64{0.25, 3.14159, math.Inf(-1)} {
+		stream = AppendFloat(stream, f)
+	}
+	stream = AppendString(stream, "end")
+
+	// iotest-style reader without ReadByte exercises the buffered path
+	d := NewDecoder(struct{ io.Reader }{bytes.NewReader(stream)})
+	var got []interface{}
+	for {
+		v, err := d.Decode()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Decode() unexpected error: %v", err)
+		}
+		got = append(got, v)
+	}
+	want := []interface{}{0.25, 3.14159, math.Inf(-1), "end"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() sequence = %v, want %v", got, want)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		want error
+	}{
+		{"empty", "", ErrSyntax},
+		{"truncated argument", "19e8", ErrSyntax},
+		{"truncated half", "f93c", ErrSyntax},
+		{"truncated string", "644945", ErrSyntax},
+		{"truncated array", "8301", ErrSyntax},
+		{"reserved ai", "1c", ErrSyntax},
+		{"lone break", "ff", ErrSyntax},
+		{"indefinite uint", "1f", ErrSyntax},
+		{"bad chunk", "5f6161ff", ErrSyntax},
+		{"nested indefinite chunk", "5f5fffff", ErrSyntax},
+		{"invalid utf8", "62c328", ErrSyntax},
+		{"two-byte simple", "f813", ErrSyntax},
+		{"trailing data", "0000", ErrSyntax},
+		{"duplicate key", "a201020103", ErrSyntax},
+		{"negative overflow", "3bffffffffffffffff", ErrUnsupported},
+		{"array key", "a1800102", ErrUnsupported},
+		{"tagged array key", "a1c18001", ErrUnsupported},
+		{"too deep", strings.Repeat("81", maxDepth+1) + "00", ErrSyntax},
+		{"huge string length", "5bffffffffffffff00", ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Unmarshal(mustHex(tt.hex)); !errors.Is(err, tt.want) {
+				t.Errorf("Unmarshal(%s) error = %v, want %v", tt.hex, err, tt.want)
+			}
+		})
+	}
+}
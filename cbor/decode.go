@@ -0,0 +1,370 @@
+package cbor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"unicode/utf8"
+)
+
+// maxDepth bounds the nesting of arrays, maps and tags
+const maxDepth = 512
+
+// Decoder reads a sequence of CBOR data items from an input stream.
+//
+// Items are decoded into generic Go values:
+//
+//	unsigned integer  uint64
+//	negative integer  int64
+//	byte string       []byte
+//	text string       string
+//	array             []interface{}
+//	map               map[interface{}]interface{}
+//	tag               Tag
+//	false, true       bool
+//	null              nil
+//	undefined         Undefined
+//	simple value      Simple
+//	float             float64
+//
+// Negative integers below math.MinInt64 and map keys that are not
+// comparable (arrays, maps, byte strings) are reported as ErrUnsupported.
+type Decoder struct {
+	r     byteReader
+	depth int
+}
+
+// byteReader is a reader that can also be read a byte at a time
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// NewDecoder returns a decoder reading from r. If r does not implement
+// io.ByteReader it is buffered, and the decoder may read past the last item
+// it returns.
+func NewDecoder(r io.Reader) *Decoder {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Decoder{r: br}
+}
+
+// Decode reads the next data item. It returns io.EOF when the input ends
+// cleanly between items.
+func (d *Decoder) Decode() (interface{}, error) {
+	ib, err := d.r.ReadByte()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	d.depth = 0
+	v, err := d.item(ib)
+	if err == io.EOF {
+		err = fmt.Errorf("%w: unexpected end of input", ErrSyntax)
+	}
+	return v, err
+}
+
+// Unmarshal decodes the single data item in data
+func Unmarshal(data []byte) (interface{}, error) {
+	r := bytes.NewReader(data)
+	v, err := NewDecoder(r).Decode()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: empty input", ErrSyntax)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%w: %d bytes of trailing data", ErrSyntax, r.Len())
+	}
+	return v, nil
+}
+
+// readFull reads len(p) bytes, reporting a short read as io.EOF
+func (d *Decoder) readFull(p []byte) error {
+	if _, err := io.ReadFull(d.r, p); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return io.EOF
+		}
+		return err
+	}
+	return nil
+}
+
+// argument reads the argument selected by the additional information ai
+func (d *Decoder) argument(ai byte) (uint64, error) {
+	var buf [8]byte
+	switch {
+	case ai < aiOneByte:
+		return uint64(ai), nil
+	case ai == aiOneByte:
+		b, err := d.r.ReadByte()
+		return uint64(b), err
+	case ai == aiTwoBytes:
+		err := d.readFull(buf[:2])
+		return uint64(binary.BigEndian.Uint16(buf[:])), err
+	case ai == aiFourBytes:
+		err := d.readFull(buf[:4])
+		return uint64(binary.BigEndian.Uint32(buf[:])), err
+	case ai == aiEightBytes:
+		err := d.readFull(buf[:8])
+		return binary.BigEndian.Uint64(buf[:]), err
+	}
+	return 0, fmt.Errorf("%w: reserved additional information %d", ErrSyntax, ai)
+}
+
+// item decodes the data item starting with initial byte ib
+func (d *Decoder) item(ib byte) (interface{}, error) {
+	major, ai := ib>>5, ib&0x1F
+
+	if major == majorSimple {
+		return d.simple(ai)
+	}
+	if ai == aiIndefinite {
+		return d.indefinite(major)
+	}
+	n, err := d.argument(ai)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorUint:
+		return n, nil
+	case majorNegInt:
+		if n > math.MaxInt64 {
+			return nil, fmt.Errorf("%w: negative integer -1-%d out of int64 range", ErrUnsupported, n)
+		}
+		return -1 - int64(n), nil
+	case majorBytes:
+		return d.bytes(n)
+	case majorText:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(b) {
+			return nil, fmt.Errorf("%w: invalid UTF-8 in text string", ErrSyntax)
+		}
+		return string(b), nil
+	case majorArray:
+		return d.array(n, false)
+	case majorMap:
+		return d.mapItem(n, false)
+	}
+
+	// majorTag
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+	ib, err = d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	content, err := d.item(ib)
+	if err != nil {
+		return nil, err
+	}
+	return Tag{Number: n, Content: content}, nil
+}
+
+// simple decodes a major type 7 item
+func (d *Decoder) simple(ai byte) (interface{}, error) {
+	var buf [8]byte
+	switch {
+	case ai < simpleFalse:
+		return Simple(ai), nil
+	case ai == simpleFalse:
+		return false, nil
+	case ai == simpleTrue:
+		return true, nil
+	case ai == simpleNull:
+		return nil, nil
+	case ai == simpleUndefined:
+		return Undefined{}, nil
+	case ai == aiOneByte:
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b < 32 {
+			return nil, fmt.Errorf("%w: simple value %d in two-byte form", ErrSyntax, b)
+		}
+		return Simple(b), nil
+	case ai >= aiTwoBytes && ai <= aiEightBytes:
+		size := 2 << (ai - aiTwoBytes)
+		if err := d.readFull(buf[:size]); err != nil {
+			return nil, err
+		}
+		return floatValue(ai, buf[:size]), nil
+	case ai == aiIndefinite:
+		return nil, fmt.Errorf("%w: unexpected break", ErrSyntax)
+	}
+	return nil, fmt.Errorf("%w: reserved additional information %d", ErrSyntax, ai)
+}
+
+// bytes reads an n-byte string body, growing the buffer as data arrives so
+// that a corrupt length cannot force a huge allocation
+func (d *Decoder) bytes(n uint64) ([]byte, error) {
+	if n > math.MaxInt64 {
+		return nil, fmt.Errorf("%w: string length %d", ErrSyntax, n)
+	}
+	if n == 0 {
+		return []byte{}, nil
+	}
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, d.r, int64(n)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// indefinite decodes an indefinite-length string, array or map
+func (d *Decoder) indefinite(major byte) (interface{}, error) {
+	switch major {
+	case majorArray:
+		return d.array(0, true)
+	case majorMap:
+		return d.mapItem(0, true)
+	case majorBytes, majorText:
+		// Concatenate definite-length chunks of the same major type
+		var buf []byte
+		for {
+			ib, err := d.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if ib == headBreak {
+				break
+			}
+			if ib>>5 != major || ib&0x1F == aiIndefinite {
+				return nil, fmt.Errorf("%w: invalid chunk in indefinite-length string", ErrSyntax)
+			}
+			n, err := d.argument(ib & 0x1F)
+			if err != nil {
+				return nil, err
+			}
+			chunk, err := d.bytes(n)
+			if err != nil {
+				return nil, err
+			}
+			if major == majorText && !utf8.Valid(chunk) {
+				return nil, fmt.Errorf("%w: invalid UTF-8 in text string", ErrSyntax)
+			}
+			buf = append(buf, chunk...)
+		}
+		if major == majorText {
+			return string(buf), nil
+		}
+		if buf == nil {
+			buf = []byte{}
+		}
+		return buf, nil
+	}
+	return nil, fmt.Errorf("%w: indefinite length for major type %d", ErrSyntax, major)
+}
+
+// next reads the initial byte of the next nested item, reporting whether it
+// is a break that ends an indefinite-length container
+func (d *Decoder) next(indefinite bool) (ib byte, brk bool, err error) {
+	ib, err = d.r.ReadByte()
+	if err != nil {
+		return 0, false, err
+	}
+	return ib, indefinite && ib == headBreak, nil
+}
+
+func (d *Decoder) array(n uint64, indefinite bool) (interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+
+	// Each item takes at least one byte, so cap the preallocation
+	a := make([]interface{}, 0, min(n, 1024))
+	for i := uint64(0); indefinite || i < n; i++ {
+		ib, brk, err := d.next(indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if brk {
+			break
+		}
+		v, err := d.item(ib)
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+	return a, nil
+}
+
+func (d *Decoder) mapItem(n uint64, indefinite bool) (interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer d.leave()
+
+	m := make(map[interface{}]interface{}, min(n, 1024))
+	for i := uint64(0); indefinite || i < n; i++ {
+		ib, brk, err := d.next(indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if brk {
+			break
+		}
+		k, err := d.item(ib)
+		if err != nil {
+			return nil, err
+		}
+		if !hashable(k) {
+			return nil, fmt.Errorf("%w: map key of type %T", ErrUnsupported, k)
+		}
+		if ib, err = d.r.ReadByte(); err != nil {
+			return nil, err
+		}
+		v, err := d.item(ib)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := m[k]; dup {
+			return nil, fmt.Errorf("%w: duplicate map key %v", ErrSyntax, k)
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+func (d *Decoder) enter() error {
+	d.depth++
+	if d.depth > maxDepth {
+		return fmt.Errorf("%w: nesting deeper than %d", ErrSyntax, maxDepth)
+	}
+	return nil
+}
+
+func (d *Decoder) leave() {
+	d.depth--
+}
+
+// hashable reports whether v can be used as a Go map key
+func hashable(v interface{}) bool {
+	switch v := v.(type) {
+	case []byte, []interface{}, map[interface{}]interface{}:
+		return false
+	case Tag:
+		return hashable(v.Content)
+	}
+	return true
+}
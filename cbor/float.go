@@ -0,0 +1,106 @@
+package cbor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/zerfoo/float16"
+)
+
+// AppendFloat appends f in preferred serialisation: half precision when f
+// is exactly representable as a Float16, otherwise single precision when
+// exact, otherwise double precision. Infinities are written as half
+// precision, and a NaN uses the shortest width that keeps its sign and
+// payload. Note that math.NaN() has the lowest payload bit set and so needs
+// double precision; use AppendFloatDeterministic to write all NaNs in half
+// precision.
+func AppendFloat(dst []byte, f float64) []byte {
+	if math.IsNaN(f) {
+		return appendNaN(dst, math.Float64bits(f))
+	}
+	if math.IsInf(f, 0) {
+		if f > 0 {
+			return AppendFloat16(dst, float16.PositiveInfinity)
+		}
+		return AppendFloat16(dst, float16.NegativeInfinity)
+	}
+	if h, err := float16.FromFloat64WithMode(f, float16.ModeExact, float16.RoundNearestEven); err == nil {
+		return AppendFloat16(dst, h)
+	}
+	if f32 := float32(f); float64(f32) == f {
+		return binary.BigEndian.AppendUint32(append(dst, headSingle), math.Float32bits(f32))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, headDouble), math.Float64bits(f))
+}
+
+// AppendFloatDeterministic appends f like AppendFloat, except that every
+// NaN is written as the half-precision quiet NaN 0xf97e00, as required by
+// the core deterministic encoding of RFC 8949 section 4.2.2.
+func AppendFloatDeterministic(dst []byte, f float64) []byte {
+	if math.IsNaN(f) {
+		return AppendFloat16(dst, float16.QuietNaN)
+	}
+	return AppendFloat(dst, f)
+}
+
+// AppendFloat16 appends a half-precision float. Every Float16, NaNs
+// included, is already in preferred serialisation.
+func AppendFloat16(dst []byte, f float16.Float16) []byte {
+	return binary.BigEndian.AppendUint16(append(dst, headHalf), f.Bits())
+}
+
+// appendNaN writes a NaN in the shortest width whose mantissa holds the
+// whole payload, so that widening it back restores the original bits
+func appendNaN(dst []byte, bits uint64) []byte {
+	// Mantissa bits dropped when narrowing to half and single precision
+	const (
+		halfShift   = float16.Float64MantissaLen - float16.MantissaLen
+		singleShift = float16.Float64MantissaLen - float16.Float32MantissaLen
+	)
+	sign := bits >> 63
+	payload := bits & (1<<float16.Float64MantissaLen - 1)
+	switch {
+	case payload&(1<<halfShift-1) == 0:
+		h := uint16(sign<<15) | float16.ExponentMask | uint16(payload>>halfShift)
+		return binary.BigEndian.AppendUint16(append(dst, headHalf), h)
+	case payload&(1<<singleShift-1) == 0:
+		s := uint32(sign<<31) | 0x7F800000 | uint32(payload>>singleShift)
+		return binary.BigEndian.AppendUint32(append(dst, headSingle), s)
+	}
+	return binary.BigEndian.AppendUint64(append(dst, headDouble), bits)
+}
+
+// DecodeFloat decodes the float item at the start of b, returning its value
+// and encoded size. Half-precision values are converted with
+// Float16.ToFloat64, single and double precision exactly.
+func DecodeFloat(b []byte) (f float64, n int, err error) {
+	if len(b) == 0 {
+		return 0, 0, fmt.Errorf("%w: empty input", ErrSyntax)
+	}
+	switch b[0] {
+	case headHalf:
+		n = 3
+	case headSingle:
+		n = 5
+	case headDouble:
+		n = 9
+	default:
+		return 0, 0, fmt.Errorf("%w: initial byte %#02x is not a float", ErrSyntax, b[0])
+	}
+	if len(b) < n {
+		return 0, 0, fmt.Errorf("%w: truncated float", ErrSyntax)
+	}
+	return floatValue(b[0]&0x1F, b[1:n]), n, nil
+}
+
+// floatValue converts the big-endian argument of a float item
+func floatValue(ai byte, arg []byte) float64 {
+	switch ai {
+	case aiTwoBytes:
+		return float16.FromBits(binary.BigEndian.Uint16(arg)).ToFloat64()
+	case aiFourBytes:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(arg)))
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(arg))
+}
@@ -0,0 +1,137 @@
+package cbor
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/zerfoo/float16"
+)
+
+// Marshal returns the CBOR encoding of v. See AppendValue for the supported
+// types.
+func Marshal(v interface{}) ([]byte, error) {
+	return AppendValue(nil, v)
+}
+
+// AppendValue appends the encoding of v, which may be nil, bool, any Go
+// integer type, float16.Float16, float32, float64, string, []byte,
+// []float16.Float16, []float32, []float64, []interface{},
+// map[string]interface{}, map[interface{}]interface{}, Tag, Simple or
+// Undefined. Floats use preferred serialisation (see AppendFloat), so a
+// float32 or float64 value that fits in half precision is written as one.
+// Map entries are sorted by the bytewise order of their encoded keys, so
+// the output does not depend on Go's map iteration order.
+func AppendValue(dst []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return AppendNull(dst), nil
+	case bool:
+		return AppendBool(dst, v), nil
+	case int:
+		return AppendInt(dst, int64(v)), nil
+	case int8:
+		return AppendInt(dst, int64(v)), nil
+	case int16:
+		return AppendInt(dst, int64(v)), nil
+	case int32:
+		return AppendInt(dst, int64(v)), nil
+	case int64:
+		return AppendInt(dst, v), nil
+	case uint:
+		return AppendUint(dst, uint64(v)), nil
+	case uint8:
+		return AppendUint(dst, uint64(v)), nil
+	case uint16:
+		return AppendUint(dst, uint64(v)), nil
+	case uint32:
+		return AppendUint(dst, uint64(v)), nil
+	case uint64:
+		return AppendUint(dst, v), nil
+	case float16.Float16:
+		return AppendFloat16(dst, v), nil
+	case float32:
+		return AppendFloat(dst, float64(v)), nil
+	case float64:
+		return AppendFloat(dst, v), nil
+	case string:
+		return AppendString(dst, v), nil
+	case []byte:
+		return AppendBytes(dst, v), nil
+	case []float16.Float16:
+		dst = AppendArrayHeader(dst, len(v))
+		for _, f := range v {
+			dst = AppendFloat16(dst, f)
+		}
+		return dst, nil
+	case []float32:
+		dst = AppendArrayHeader(dst, len(v))
+		for _, f := range v {
+			dst = AppendFloat(dst, float64(f))
+		}
+		return dst, nil
+	case []float64:
+		dst = AppendArrayHeader(dst, len(v))
+		for _, f := range v {
+			dst = AppendFloat(dst, f)
+		}
+		return dst, nil
+	case []interface{}:
+		dst = AppendArrayHeader(dst, len(v))
+		for _, e := range v {
+			var err error
+			if dst, err = AppendValue(dst, e); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+	case map[string]interface{}:
+		pairs := make([][2]interface{}, 0, len(v))
+		for k, e := range v {
+			pairs = append(pairs, [2]interface{}{k, e})
+		}
+		return appendMap(dst, pairs)
+	case map[interface{}]interface{}:
+		pairs := make([][2]interface{}, 0, len(v))
+		for k, e := range v {
+			pairs = append(pairs, [2]interface{}{k, e})
+		}
+		return appendMap(dst, pairs)
+	case Tag:
+		return AppendValue(AppendTag(dst, v.Number), v.Content)
+	case Simple:
+		return AppendSimple(dst, v), nil
+	case Undefined:
+		return AppendUndefined(dst), nil
+	}
+	return nil, fmt.Errorf("%w: Go type %T", ErrUnsupported, v)
+}
+
+// appendMap writes key/value pairs sorted by their encoded keys
+func appendMap(dst []byte, pairs [][2]interface{}) ([]byte, error) {
+	type entry struct {
+		key []byte
+		val interface{}
+	}
+	entries := make([]entry, len(pairs))
+	for i, p := range pairs {
+		key, err := AppendValue(nil, p[0])
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry{key, p[1]}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	dst = AppendMapHeader(dst, len(entries))
+	for _, e := range entries {
+		var err error
+		dst = append(dst, e.key...)
+		if dst, err = AppendValue(dst, e.val); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
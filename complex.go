@@ -0,0 +1,242 @@
+package float16
+
+import (
+	"math"
+	"math/cmplx"
+	"strconv"
+)
+
+// Complex32 is a complex number with half-precision real and imaginary parts.
+//
+// Arithmetic is carried out in float64 and each part of the result is rounded
+// to Float16. Add, Sub and Mul are correctly rounded. Exp, Log, Sqrt, Pow, Abs
+// and Phase follow math/cmplx, including its handling of infinities and NaN.
+type Complex32 struct {
+	Re, Im Float16
+}
+
+// NewComplex32 returns the complex number re + im·i
+func NewComplex32(re, im Float16) Complex32 {
+	return Complex32{Re: re, Im: im}
+}
+
+// Complex32FromComplex64 converts c to Complex32, rounding each part to
+// nearest even
+func Complex32FromComplex64(c complex64) Complex32 {
+	return Complex32{FromFloat32(real(c)), FromFloat32(imag(c))}
+}
+
+// Complex32FromComplex128 converts c to Complex32, rounding each part to
+// nearest even
+func Complex32FromComplex128(c complex128) Complex32 {
+	return Complex32{FromFloat64(real(c)), FromFloat64(imag(c))}
+}
+
+// Complex64 converts c to complex64 exactly
+func (c Complex32) Complex64() complex64 {
+	return complex(c.Re.ToFloat32(), c.Im.ToFloat32())
+}
+
+// Complex128 converts c to complex128 exactly
+func (c Complex32) Complex128() complex128 {
+	return complex(c.Re.ToFloat64(), c.Im.ToFloat64())
+}
+
+// IsNaN reports whether either part of c is NaN and neither is infinite,
+// like cmplx.IsNaN
+func (c Complex32) IsNaN() bool {
+	return cmplx.IsNaN(c.Complex128())
+}
+
+// IsInf reports whether either part of c is infinite, like cmplx.IsInf
+func (c Complex32) IsInf() bool {
+	return c.Re.IsInf(0) || c.Im.IsInf(0)
+}
+
+// String formats c like a Go complex value, e.g. "(1+2i)"
+func (c Complex32) String() string {
+	return strconv.FormatComplex(c.Complex128(), 'g', -1, 64)
+}
+
+// Add returns c + d
+func (c Complex32) Add(d Complex32) Complex32 {
+	return Complex32FromComplex128(c.Complex128() + d.Complex128())
+}
+
+// Sub returns c - d
+func (c Complex32) Sub(d Complex32) Complex32 {
+	return Complex32FromComplex128(c.Complex128() - d.Complex128())
+}
+
+// Mul returns c × d, with each part correctly rounded
+func (c Complex32) Mul(d Complex32) Complex32 {
+	a, b := c.Re.ToFloat64(), c.Im.ToFloat64()
+	x, y := d.Re.ToFloat64(), d.Im.ToFloat64()
+	// The products are exact, but their sum may need more than 53 bits
+	return Complex32{FromFloat64(addToOdd(a*x, -b*y)), FromFloat64(addToOdd(a*y, b*x))}
+}
+
+// addToOdd returns p + q rounded to float64 with round-to-odd: an inexact
+// sum becomes whichever neighbour has an odd significand. Rounding that to
+// Float16, 42 bits shorter, gives the correctly rounded sum, where rounding
+// to nearest first could land on a Float16 tie and round twice.
+func addToOdd(p, q float64) float64 {
+	s := p + q
+	if !isFinite64(s) {
+		return s
+	}
+	// TwoSum: the rounding error of s, exactly
+	bv := s - p
+	err := (p - (s - bv)) + (q - bv)
+	if err != 0 && math.Float64bits(s)&1 == 0 {
+		s = math.Nextafter(s, math.Copysign(math.Inf(1), err))
+	}
+	return s
+}
+
+// mulComplex returns c × d in float64. The products of half-precision values
+// are exact in float64, so each part is rounded only by the final fused add.
+func mulComplex(c, d Complex32) complex128 {
+	a, b := c.Re.ToFloat64(), c.Im.ToFloat64()
+	x, y := d.Re.ToFloat64(), d.Im.ToFloat64()
+	return complex(math.FMA(a, x, -b*y), math.FMA(a, y, b*x))
+}
+
+// Div returns c / d.
+//
+// It uses Smith's algorithm, scaling by the ratio of the divisor's parts so
+// that no intermediate product overflows, and recovers infinite and zero
+// results as in C99 Annex G when the algorithm yields NaN. This matches the
+// division of Go's built-in complex types.
+func (c Complex32) Div(d Complex32) Complex32 {
+	a, b := c.Re.ToFloat64(), c.Im.ToFloat64()
+	x, y := d.Re.ToFloat64(), d.Im.ToFloat64()
+
+	var e, f float64
+	if math.Abs(x) >= math.Abs(y) {
+		ratio := y / x
+		denom := x + ratio*y
+		e = (a + b*ratio) / denom
+		f = (b - a*ratio) / denom
+	} else {
+		ratio := x / y
+		denom := y + ratio*x
+		e = (a*ratio + b) / denom
+		f = (b*ratio - a) / denom
+	}
+
+	if math.IsNaN(e) && math.IsNaN(f) {
+		inf := math.Inf(1)
+		switch {
+		case x == 0 && y == 0 && (!math.IsNaN(a) || !math.IsNaN(b)):
+			e = math.Copysign(inf, x) * a
+			f = math.Copysign(inf, x) * b
+		case (math.IsInf(a, 0) || math.IsInf(b, 0)) && isFinite64(x) && isFinite64(y):
+			a = math.Copysign(infOne(a), a)
+			b = math.Copysign(infOne(b), b)
+			e = inf * (a*x + b*y)
+			f = inf * (b*x - a*y)
+		case (math.IsInf(x, 0) || math.IsInf(y, 0)) && isFinite64(a) && isFinite64(b):
+			x = math.Copysign(infOne(x), x)
+			y = math.Copysign(infOne(y), y)
+			e = 0 * (a*x + b*y)
+			f = 0 * (b*x - a*y)
+		}
+	}
+	return Complex32{FromFloat64(e), FromFloat64(f)}
+}
+
+func isFinite64(v float64) bool {
+	return !math.IsNaN(v - v)
+}
+
+// infOne returns 1 for an infinite v and 0 otherwise
+func infOne(v float64) float64 {
+	if math.IsInf(v, 0) {
+		return 1
+	}
+	return 0
+}
+
+// Neg returns -c
+func (c Complex32) Neg() Complex32 {
+	return Complex32{c.Re.Neg(), c.Im.Neg()}
+}
+
+// Conj returns the complex conjugate of c
+func (c Complex32) Conj() Complex32 {
+	return Complex32{c.Re, c.Im.Neg()}
+}
+
+// Abs returns the absolute value (modulus) of c, like cmplx.Abs
+func (c Complex32) Abs() Float16 {
+	return FromFloat64(cmplx.Abs(c.Complex128()))
+}
+
+// Phase returns the phase (argument) of c in [-π, π], like cmplx.Phase
+func (c Complex32) Phase() Float16 {
+	return FromFloat64(cmplx.Phase(c.Complex128()))
+}
+
+// Exp returns e**c, like cmplx.Exp
+func (c Complex32) Exp() Complex32 {
+	return Complex32FromComplex128(cmplx.Exp(c.Complex128()))
+}
+
+// Log returns the natural logarithm of c, like cmplx.Log
+func (c Complex32) Log() Complex32 {
+	return Complex32FromComplex128(cmplx.Log(c.Complex128()))
+}
+
+// Sqrt returns the principal square root of c, like cmplx.Sqrt
+func (c Complex32) Sqrt() Complex32 {
+	return Complex32FromComplex128(cmplx.Sqrt(c.Complex128()))
+}
+
+// Pow returns c**d, like cmplx.Pow
+func (c Complex32) Pow(d Complex32) Complex32 {
+	return Complex32FromComplex128(cmplx.Pow(c.Complex128(), d.Complex128()))
+}
+
+// Complex slice kernels
+
+// ComplexMulSlice returns the element-wise product of two Complex32 slices
+func ComplexMulSlice(a, b []Complex32) []Complex32 {
+	if len(a) != len(b) {
+		panic("float16: slice length mismatch")
+	}
+
+	result := make([]Complex32, len(a))
+	for i := range a {
+		result[i] = a[i].Mul(b[i])
+	}
+	return result
+}
+
+// ComplexMulAddSlice performs the multiply-accumulate acc[i] += a[i] × b[i]
+// in place. Each element is computed in float64 and rounded to Float16 after
+// the addition.
+func ComplexMulAddSlice(acc, a, b []Complex32) {
+	if len(acc) != len(a) || len(a) != len(b) {
+		panic("float16: slice length mismatch")
+	}
+
+	for i := range acc {
+		acc[i] = Complex32FromComplex128(acc[i].Complex128() + mulComplex(a[i], b[i]))
+	}
+}
+
+// ComplexDotProduct returns the sum of a[i] × b[i]. The sum is accumulated
+// in float64 and rounded to Float16 once at the end; pass conjugated values
+// in a for the Hermitian inner product.
+func ComplexDotProduct(a, b []Complex32) Complex32 {
+	if len(a) != len(b) {
+		panic("float16: slice length mismatch")
+	}
+
+	var sum complex128
+	for i := range a {
+		sum += mulComplex(a[i], b[i])
+	}
+	return Complex32FromComplex128(sum)
+}
@@ -0,0 +1,224 @@
+package float16
+
+import (
+	"math"
+	"math/big"
+	"math/cmplx"
+	"testing"
+)
+
+func c32(re, im float64) Complex32 {
+	return Complex32{FromFloat64(re), FromFloat64(im)}
+}
+
+// sameComplex compares parts bitwise, treating any two NaNs as equal
+func sameComplex(a, b Complex32) bool {
+	same := func(x, y Float16) bool { return x == y || x.IsNaN() && y.IsNaN() }
+	return same(a.Re, b.Re) && same(a.Im, b.Im)
+}
+
+func TestComplex32Arithmetic(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name string
+		got  Complex32
+		want Complex32
+	}{
+		{"add", c32(1, 2).Add(c32(3, -4)), c32(4, -2)},
+		{"sub", c32(1, 2).Sub(c32(3, -4)), c32(-2, 6)},
+		{"mul", c32(1, 2).Mul(c32(3, 4)), c32(-5, 10)},
+		{"mul i", c32(0, 1).Mul(c32(0, 1)), c32(-1, 0)},
+		{"div", c32(-5, 10).Div(c32(3, 4)), c32(1, 2)},
+		{"div imag divisor", c32(1, 0).Div(c32(0, 2)), c32(0, -0.5)},
+		{"neg", c32(1, -2).Neg(), c32(-1, 2)},
+		{"conj", c32(1, 2).Conj(), c32(1, -2)},
+		// Rounded once from the exact result
+		{"mul rounding", c32(1+1.0/1024, 0).Mul(c32(1+1.0/1024, 0)), c32(1+2.0/1024, 0)},
+		// 683×3 + 2^-48 is just above the tie 2049, but rounds to it in
+		// float64
+		{"mul near tie", c32(683, -0x1p-24).Mul(c32(3, 0x1p-24)), c32(2050, 680*0x1p-24)},
+		// Intermediate products of Smith's algorithm stay in range
+		{"div large", c32(60000, 60000).Div(c32(60000, 60000)), c32(1, 0)},
+		{"div tiny", c32(1e-7, 1e-7).Div(c32(1e-7, 0)), c32(1, 1)},
+		// C99 Annex G recovery
+		{"div by zero", c32(1, 1).Div(c32(0, 0)), c32(inf, inf)},
+		{"div inf by finite", c32(inf, 0).Div(c32(1, 1)), c32(inf, -inf)},
+		{"div finite by inf", c32(1, 1).Div(c32(inf, 0)), c32(0, 0)},
+	}
+	for _, tt := range tests {
+		if !sameComplex(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+// TestComplex32DivMatchesBuiltin checks Div against Go's complex128 division,
+// which also uses Smith's algorithm
+func TestComplex32DivMatchesBuiltin(t *testing.T) {
+	vals := []float64{0, 1, -2.5, 0.1, 65504, 6e-8, math.Inf(1), math.Inf(-1), math.NaN()}
+	for _, a := range vals {
+		for _, b := range vals {
+			for _, x := range vals {
+				for _, y := range vals {
+					n, d := c32(a, b), c32(x, y)
+					want := Complex32FromComplex128(n.Complex128() / d.Complex128())
+					if got := n.Div(d); !sameComplex(got, want) {
+						t.Errorf("%v.Div(%v) = %v, want %v", n, d, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestComplex32Functions(t *testing.T) {
+	inf, nan := math.Inf(1), math.NaN()
+	tests := []struct {
+		name string
+		c    Complex32
+		f    func(Complex32) Complex32
+		ref  func(complex128) complex128
+	}{
+		{"exp", c32(1, 2), Complex32.Exp, cmplx.Exp},
+		{"exp inf", c32(inf, 0), Complex32.Exp, cmplx.Exp},
+		{"exp -inf", c32(-inf, 3), Complex32.Exp, cmplx.Exp},
+		{"exp nan", c32(nan, 1), Complex32.Exp, cmplx.Exp},
+		{"log", c32(-1, 0), Complex32.Log, cmplx.Log},
+		{"log zero", c32(0, 0), Complex32.Log, cmplx.Log},
+		{"log inf", c32(-inf, nan), Complex32.Log, cmplx.Log},
+		{"sqrt", c32(-4, 0), Complex32.Sqrt, cmplx.Sqrt},
+		{"sqrt negative zero imag", c32(-4, math.Copysign(0, -1)), Complex32.Sqrt, cmplx.Sqrt},
+		{"sqrt inf", c32(1, inf), Complex32.Sqrt, cmplx.Sqrt},
+		{"sqrt large", c32(65504, 65504), Complex32.Sqrt, cmplx.Sqrt},
+	}
+	for _, tt := range tests {
+		want := Complex32FromComplex128(tt.ref(tt.c.Complex128()))
+		if got := tt.f(tt.c); !sameComplex(got, want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, want)
+		}
+	}
+
+	if got := c32(-1, 0).Log(); got.Im != FromFloat64(math.Pi) {
+		t.Errorf("Log(-1) imaginary part = %v, want π", got.Im)
+	}
+	if got := c32(0, 0).Pow(c32(0, 0)); got != c32(1, 0) {
+		t.Errorf("Pow(0, 0) = %v, want 1", got)
+	}
+	if got := c32(0, 0).Pow(c32(-1, 0)); !got.IsInf() {
+		t.Errorf("Pow(0, -1) = %v, want infinity", got)
+	}
+	if got := c32(0, 1).Pow(c32(2, 0)); !sameComplex(got, Complex32FromComplex128(cmplx.Pow(1i, 2))) {
+		t.Errorf("Pow(i, 2) = %v", got)
+	}
+}
+
+func TestComplex32AbsPhase(t *testing.T) {
+	inf, nan := math.Inf(1), math.NaN()
+	tests := []struct {
+		c     Complex32
+		abs   float64
+		phase float64
+	}{
+		{c32(3, 4), 5, math.Atan2(4, 3)},
+		{c32(60000, 60000), inf, math.Pi / 4}, // modulus overflows Float16
+		{c32(-1, 0), 1, math.Pi},
+		{c32(-1, math.Copysign(0, -1)), 1, -math.Pi},
+		{c32(nan, inf), inf, math.NaN()},
+		{c32(0, 0), 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.c.Abs(); got != FromFloat64(tt.abs) {
+			t.Errorf("%v.Abs() = %v, want %v", tt.c, got, tt.abs)
+		}
+		if got, want := tt.c.Phase(), FromFloat64(tt.phase); got != want && !(got.IsNaN() && want.IsNaN()) {
+			t.Errorf("%v.Phase() = %v, want %v", tt.c, got, want)
+		}
+	}
+}
+
+func TestComplex32Conversions(t *testing.T) {
+	c := Complex32FromComplex64(complex(1.5, -0.25))
+	if c != c32(1.5, -0.25) {
+		t.Errorf("Complex32FromComplex64() = %v", c)
+	}
+	if c.Complex64() != complex(1.5, -0.25) || c.Complex128() != complex(1.5, -0.25) {
+		t.Errorf("round trip = %v, %v", c.Complex64(), c.Complex128())
+	}
+	if s := c.String(); s != "(1.5-0.25i)" {
+		t.Errorf("String() = %q", s)
+	}
+	if !c32(math.NaN(), 0).IsNaN() || c32(math.NaN(), math.Inf(1)).IsNaN() || !c32(math.NaN(), math.Inf(1)).IsInf() {
+		t.Error("IsNaN/IsInf do not follow math/cmplx")
+	}
+	if NewComplex32(One(), Zero()) != c32(1, 0) {
+		t.Error("NewComplex32(1, 0) != 1")
+	}
+}
+
+func TestComplexSliceKernels(t *testing.T) {
+	a := []Complex32{c32(1, 2), c32(0, 1), c32(-3, 0.5)}
+	b := []Complex32{c32(3, 4), c32(0, 1), c32(2, 0)}
+
+	prod := ComplexMulSlice(a, b)
+	for i := range a {
+		if prod[i] != a[i].Mul(b[i]) {
+			t.Errorf("ComplexMulSlice()[%d] = %v, want %v", i, prod[i], a[i].Mul(b[i]))
+		}
+	}
+
+	acc := []Complex32{c32(1, 1), c32(1, 1), c32(1, 1)}
+	ComplexMulAddSlice(acc, a, b)
+	want := []Complex32{c32(-4, 11), c32(0, 1), c32(-5, 2)}
+	for i := range acc {
+		if acc[i] != want[i] {
+			t.Errorf("ComplexMulAddSlice()[%d] = %v, want %v", i, acc[i], want[i])
+		}
+	}
+
+	// The product 1 + 2^-11 - 2^-21 rounds to 1 in half precision, and
+	// 2048 + 1 would be a tie rounding down; rounding once gives 2050
+	acc = []Complex32{c32(2048, 0)}
+	ComplexMulAddSlice(acc, []Complex32{c32(1+1.0/1024, 0)}, []Complex32{c32(1-1.0/2048, 0)})
+	if acc[0] != c32(2050, 0) {
+		t.Errorf("ComplexMulAddSlice() rounding = %v, want 2050", acc[0])
+	}
+
+	if got := ComplexDotProduct(a, b); got != c32(-12, 11) {
+		t.Errorf("ComplexDotProduct() = %v, want (-12+11i)", got)
+	}
+	if got := ComplexDotProduct(nil, nil); got != (Complex32{}) {
+		t.Errorf("ComplexDotProduct(nil, nil) = %v, want 0", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("ComplexMulAddSlice() with mismatched lengths did not panic")
+		}
+	}()
+	ComplexMulAddSlice(acc, a, b)
+}
+
+// TestComplex32MulCorrectlyRounded compares Mul with the exact product,
+// computed with math/big, over a sample of finite operands
+func TestComplex32MulCorrectlyRounded(t *testing.T) {
+	exact := func(p, q, r, s Float16) Float16 {
+		// p×q + r×s
+		x := new(big.Float).Mul(p.BigFloat(), q.BigFloat())
+		x.SetPrec(200).Add(x, new(big.Float).Mul(r.BigFloat(), s.BigFloat()))
+		got, _ := FromBigFloat(x, RoundNearestEven)
+		return got
+	}
+	for i := 0; i < 1<<16; i += 97 {
+		for j := 0; j < 1<<16; j += 1009 {
+			c := Complex32{FromBits(uint16(i)), FromBits(uint16(j * 7))}
+			d := Complex32{FromBits(uint16(j)), FromBits(uint16(i*13 + 5))}
+			if !c.Re.IsFinite() || !c.Im.IsFinite() || !d.Re.IsFinite() || !d.Im.IsFinite() {
+				continue
+			}
+			want := Complex32{exact(c.Re, d.Re, c.Im.Neg(), d.Im), exact(c.Re, d.Im, c.Im, d.Re)}
+			if got := c.Mul(d); got.Re.ToFloat64() != want.Re.ToFloat64() || got.Im.ToFloat64() != want.Im.ToFloat64() {
+				t.Fatalf("%v × %v = %v, want %v", c, d, got, want)
+			}
+		}
+	}
+}
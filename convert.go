@@ -7,75 +7,28 @@ import (
 
 // FromFloat32 converts a float32 value to a Float16 value.
 // It handles special cases like NaN, infinities, and zeros.
-// The conversion follows IEEE 754-2008 rules for half-precision.
+// The conversion follows IEEE 754-2008 rules for half-precision, rounding
+// to nearest even and producing subnormals and infinities as needed. A NaN
+// keeps its sign and the top bits of its payload and is quieted.
 func FromFloat32(f32 float32) Float16 {
 	f32Bits := math.Float32bits(f32)
-	sign := f32Bits & 0x80000000
-	exp := (f32Bits >> 23) & 0xFF
-	mant := f32Bits & 0x7FFFFF
-
-	var f16Bits uint16
-
-	if exp == 0xFF { // NaN or Infinity
-		if mant != 0 { // NaN
-			f16Bits = 0x7E00 // Quiet NaN
-		} else { // Infinity
-			if sign != 0 {
-				f16Bits = 0xFC00 // Negative Infinity
-			} else {
-				f16Bits = 0x7C00 // Positive Infinity
-			}
-		}
-	} else if exp == 0 { // Zero or Denormalized
-		if mant == 0 { // Zero
-			if sign != 0 {
-				f16Bits = 0x8000 // Negative Zero
-			} else {
-				f16Bits = 0x0000 // Positive Zero
-			}
-		} else { // Denormalized float32, convert to float16 denormalized or zero
-			// Shift mantissa right to align with float16 denormalized range
-			// This is a simplified approach and might not be perfectly accurate for all denormals
-			// A more robust implementation would involve proper rounding and handling of underflow
-			f16Bits = uint16(mant >> 13) // Shift 23 - 10 = 13 bits
-			if sign != 0 {
-				f16Bits |= 0x8000
-			}
-		}
-	} else { // Normalized float32
-		exp16 := int(exp) - 127 + 15 // Adjust bias
-		if exp16 >= 31 { // Overflow, convert to infinity
-			if sign != 0 {
-				f16Bits = 0xFC00 // Negative Infinity
-			} else {
-				f16Bits = 0x7C00 // Positive Infinity
-			}
-		} else if exp16 <= 0 { // Underflow, convert to denormalized or zero
-			// This is a simplified approach. Proper denormalization requires
-			// shifting the mantissa and potentially losing precision.
-			if exp16 == 0 { // Smallest normalized float32 maps to float16 denormal
-				f16Bits = uint16(mant >> 13) // Shift 23 - 10 = 13 bits
-			} else { // Smaller than smallest denormal, convert to zero
-				f16Bits = 0x0000
-			}
-			if sign != 0 {
-				f16Bits |= 0x8000
-			}
-		} else { // Normalized float16
-			f16Bits = uint16(exp16<<10) | uint16(mant>>13) // Shift 23 - 10 = 13 bits
-			if sign != 0 {
-				f16Bits |= 0x8000
-			}
+	if (f32Bits>>23)&0xFF == 0xFF { // NaN or Infinity
+		if f32Bits&0x7FFFFF != 0 {
+			return nanFromFloat32(f32Bits)
 		}
+		return Float16(uint16(f32Bits>>16)&SignMask) | PositiveInfinity
 	}
-	return Float16(f16Bits)
+	// float32 to float64 is exact, so this rounds only once
+	result, _, _ := roundFloat64(float64(f32), RoundNearestEven)
+	return result
 }
 
 // ToFloat32 converts a Float16 value to a float32 value.
-// It handles special cases like NaN, infinities, and zeros.
+// It handles special cases like NaN, infinities, and zeros. The conversion
+// is exact; a NaN keeps its sign and payload and is quieted.
 func (f Float16) ToFloat32() float32 {
 	f16Bits := uint16(f)
-	sign := uint32(f16Bits & 0x8000) << 16 // Shift to float32 sign position
+	sign := uint32(f16Bits&0x8000) << 16 // Shift to float32 sign position
 	exp := (f16Bits >> 10) & 0x1F
 	mant := f16Bits & 0x3FF
 
@@ -83,7 +36,7 @@ func (f Float16) ToFloat32() float32 {
 
 	if exp == 0x1F { // NaN or Infinity
 		if mant != 0 { // NaN
-			f32Bits = 0x7FC00000 // Quiet NaN
+			f32Bits = nanToFloat32(f)
 		} else { // Infinity
 			if sign != 0 {
 				f32Bits = 0xFF800000 // Negative Infinity
@@ -98,35 +51,200 @@ func (f Float16) ToFloat32() float32 {
 			} else {
 				f32Bits = 0x00000000 // Positive Zero
 			}
-		} else { // Denormalized float16, convert to float32 denormalized
-			// Shift mantissa left to align with float32 denormalized range
-			// This is a simplified approach and might not be perfectly accurate for all denormals
-			// A more robust implementation would involve proper scaling
-			f32Bits = uint32(mant) << 13 // Shift 10 + 13 = 23 bits
-			if sign != 0 {
-				f32Bits |= 0x80000000
+		} else { // Denormalized float16, always a normal float32
+			// Normalize the mantissa, adjusting the exponent to keep mant × 2^-24
+			exp32 := uint32(127 - 15 + 1)
+			for mant&0x400 == 0 {
+				mant <<= 1
+				exp32--
 			}
+			f32Bits = sign | (exp32 << 23) | (uint32(mant&0x3FF) << 13)
 		}
 	} else { // Normalized float16
-		exp32 := uint32(int(exp) - 15 + 127) // Adjust bias
+		exp32 := uint32(int(exp) - 15 + 127)                  // Adjust bias
 		f32Bits = sign | (exp32 << 23) | (uint32(mant) << 13) // Shift 10 + 13 = 23 bits
 	}
 	return math.Float32frombits(f32Bits)
 }
 
 // FromFloat64 converts a float64 value to a Float16 value.
-// It handles special cases like NaN, infinities, and zeros.
+// It handles special cases like NaN, infinities, and zeros. A NaN keeps its
+// sign and the top bits of its payload and is quieted.
 func FromFloat64(f64 float64) Float16 {
-	return FromFloat32(float32(f64)) // Simplified: convert via float32
+	if math.IsNaN(f64) {
+		return nanFromFloat64(math.Float64bits(f64))
+	}
+	if math.IsInf(f64, 0) {
+		if f64 < 0 {
+			return NegativeInfinity
+		}
+		return PositiveInfinity
+	}
+	// Round directly from float64; going through float32 would round twice
+	result, _, _ := roundFloat64(f64, RoundNearestEven)
+	return result
 }
 
 // ToFloat64 converts a Float16 value to a float64 value.
-// It handles special cases like NaN, infinities, and zeros.
+// It handles special cases like NaN, infinities, and zeros. The conversion
+// is exact; a NaN keeps its sign and payload and is quieted.
 func (f Float16) ToFloat64() float64 {
-	return float64(f.ToFloat32()) // Simplified: convert via float32
+	if f.IsNaN() {
+		// Go leaves the NaN bits of float32 to float64 conversion unspecified
+		return math.Float64frombits(nanToFloat64(f))
+	}
+	return float64(f.ToFloat32())
+}
+
+// FromFloat32WithMode converts a float32 value to a Float16 value using the
+// given conversion and rounding modes. See FromFloat64WithMode.
+func FromFloat32WithMode(f32 float32, convMode ConversionMode, rounding RoundingMode) (Float16, error) {
+	if f32 != f32 {
+		// float64(f32) need not keep the payload or the signaling bit
+		return convertNaN(FromFloat32(f32), math.Float32bits(f32)&(1<<22) == 0, f32, convMode)
+	}
+	return FromFloat64WithMode(float64(f32), convMode, rounding)
+}
+
+// FromFloat64WithMode converts a float64 value to a Float16 value, rounding
+// once according to rounding.
+//
+// In ModeIEEE and ModeFast the correctly rounded result is always returned.
+// ModeStrict reports NaN and infinite inputs, overflow, and underflow (a tiny,
+// inexact result) as a *Float16Error. ModeExact additionally reports any
+// result that is not exactly equal to the input. A NaN is always quieted; a
+// signaling one is reported as ErrInvalidOperation, a quiet one as ErrNaN.
+func FromFloat64WithMode(f64 float64, convMode ConversionMode, rounding RoundingMode) (Float16, error) {
+	strict := convMode == ModeStrict || convMode == ModeExact
+
+	if math.IsNaN(f64) {
+		return convertNaN(FromFloat64(f64), math.Float64bits(f64)&(1<<51) == 0, f64, convMode)
+	}
+	if math.IsInf(f64, 0) {
+		if strict {
+			return Inf(int(math.Copysign(1, f64))), &Float16Error{Op: "convert", Value: f64, Msg: "infinity in strict mode", Code: ErrInfinity}
+		}
+		return Inf(int(math.Copysign(1, f64))), nil
+	}
+
+	result, inexact, overflow := roundFloat64(f64, rounding)
+	if !strict {
+		return result, nil
+	}
+
+	if overflow {
+		return result, &Float16Error{Op: "convert", Value: f64, Msg: "value too large for float16", Code: ErrOverflow}
+	}
+	if inexact && math.Abs(f64) < SmallestNormal.ToFloat64() {
+		return result, &Float16Error{Op: "convert", Value: f64, Msg: "value too small for float16", Code: ErrUnderflow}
+	}
+	if inexact && convMode == ModeExact {
+		return result, &Float16Error{Op: "convert", Value: f64, Msg: "value not exactly representable", Code: ErrInexact}
+	}
+	return result, nil
 }
 
+// convertNaN returns the quiet NaN result of converting the NaN value,
+// reporting it in ModeStrict and ModeExact
+func convertNaN(result Float16, signaling bool, value interface{}, convMode ConversionMode) (Float16, error) {
+	switch {
+	case convMode != ModeStrict && convMode != ModeExact:
+		return result, nil
+	case signaling:
+		return result, &Float16Error{Op: "convert", Value: value, Msg: "signaling NaN", Code: ErrInvalidOperation}
+	}
+	return result, &Float16Error{Op: "convert", Value: value, Msg: "NaN in strict mode", Code: ErrNaN}
+}
 
+// roundFloat64 rounds a finite float64 to a Float16 in the direction given by
+// rounding, reporting whether the result differs from the input and whether
+// it overflowed. Overflow produces infinity or the largest finite value as
+// IEEE 754 requires for the rounding direction.
+func roundFloat64(f64 float64, rounding RoundingMode) (result Float16, inexact, overflow bool) {
+	bits := math.Float64bits(f64)
+	sign := uint16(bits>>48) & SignMask
+	exp := int((bits >> Float64MantissaLen) & 0x7FF)
+	mant := bits & (1<<Float64MantissaLen - 1)
+
+	if exp == 0 && mant == 0 {
+		return Float16(sign), false, false
+	}
+
+	// Value is mant × 2^(e-52), with the implicit bit made explicit for normals
+	e := exp - Float64ExponentBias
+	if exp == 0 {
+		e = 1 - Float64ExponentBias
+	} else {
+		mant |= 1 << Float64MantissaLen
+	}
+
+	// Number of low bits to discard so that one unit is the Float16 quantum
+	// at this magnitude: 2^(e-10) for normals, 2^-24 for subnormals
+	shift := Float64MantissaLen - MantissaLen
+	if e < 1-ExponentBias {
+		shift += 1 - ExponentBias - e
+	}
+
+	// A shift of 64 or more, far below the smallest subnormal, keeps nothing
+	kept := mant >> shift
+	inexact = mant&(1<<shift-1) != 0
+	if shouldRound(mant, shift, sign, rounding) {
+		kept++
+	}
+
+	if e < 1-ExponentBias {
+		// Subnormal range; a carry into bit 10 yields the smallest normal
+		return Float16(sign | uint16(kept)), inexact, false
+	}
+
+	if kept == 1<<(MantissaLen+1) {
+		kept >>= 1
+		e++
+	}
+	if e > ExponentBias {
+		return overflowResult(sign, rounding), true, true
+	}
+	return Float16(sign | uint16(e+ExponentBias)<<MantissaLen | uint16(kept)&MantissaMask), inexact, false
+}
+
+// shouldRound reports whether discarding the low shift bits of mant, the
+// magnitude of a value with the given sign bit, rounds the kept bits up
+func shouldRound(mant uint64, shift int, sign uint16, rounding RoundingMode) bool {
+	rem := mant & (1<<shift - 1)
+	if rem == 0 {
+		return false
+	}
+	// Once everything is discarded it is less than half a unit
+	half := uint64(math.MaxUint64)
+	if shift < 64 {
+		half = 1 << (shift - 1)
+	}
+
+	switch rounding {
+	case RoundNearestEven:
+		return rem > half || (rem == half && (mant>>shift)&1 == 1)
+	case RoundNearestAway:
+		return rem >= half
+	case RoundTowardPositive:
+		return sign == 0
+	case RoundTowardNegative:
+		return sign != 0
+	}
+	return false
+}
+
+// overflowResult returns the IEEE 754 result for an overflowing value with the
+// given sign: infinity, or the largest finite value when rounding toward zero
+// or away from the sign of the value
+func overflowResult(sign uint16, rounding RoundingMode) Float16 {
+	switch {
+	case rounding == RoundTowardZero,
+		rounding == RoundTowardPositive && sign != 0,
+		rounding == RoundTowardNegative && sign == 0:
+		return Float16(sign) | MaxValue
+	}
+	return Float16(sign) | PositiveInfinity
+}
 
 // ParseFloat converts a string to a Float16 value.
 // The precision parameter is ignored for Float16.
@@ -156,5 +274,3 @@ func ParseFloat(s string, precision int) (Float16, error) {
 	}
 	return FromFloat32(float32(f32)), nil
 }
-
-
@@ -99,6 +99,137 @@ func TestFromFloat64WithMode_Extra(t *testing.T) {
 	}
 }
 
+func TestFromFloat64WithModeRounding(t *testing.T) {
+	// 1 + 2^-11 lies exactly halfway between 1.0 and the next Float16 (0x3C01)
+	tie := 1 + math.Ldexp(1, -11)
+	above := 1 + math.Ldexp(3, -12)
+
+	tests := []struct {
+		name     string
+		input    float64
+		rounding RoundingMode
+		expected Float16
+	}{
+		{"tie nearest even", tie, RoundNearestEven, 0x3C00},
+		{"tie nearest away", tie, RoundNearestAway, 0x3C01},
+		{"tie toward zero", tie, RoundTowardZero, 0x3C00},
+		{"tie toward positive", tie, RoundTowardPositive, 0x3C01},
+		{"negative tie toward negative", -tie, RoundTowardNegative, 0xBC01},
+		{"negative tie toward positive", -tie, RoundTowardPositive, 0xBC00},
+		{"above tie nearest even", above, RoundNearestEven, 0x3C01},
+		{"odd tie nearest even", 1 + math.Ldexp(3, -11), RoundNearestEven, 0x3C02},
+		{"1.2 nearest", 1.2, RoundNearestEven, 0x3CCD},
+		{"1.2 toward zero", 1.2, RoundTowardZero, 0x3CCC},
+		{"smallest subnormal", math.Ldexp(1, -24), RoundNearestEven, 0x0001},
+		{"half smallest subnormal", math.Ldexp(1, -25), RoundNearestEven, 0x0000},
+		{"tiny toward positive", 1e-300, RoundTowardPositive, 0x0001},
+		{"tiny negative toward negative", -1e-300, RoundTowardNegative, 0x8001},
+		{"largest subnormal carries to normal", math.Ldexp(1023.75, -24), RoundNearestEven, 0x0400},
+		{"max value", 65504, RoundNearestEven, MaxValue},
+		{"below overflow threshold", 65519, RoundNearestEven, MaxValue},
+		{"overflow threshold", 65520, RoundNearestEven, PositiveInfinity},
+		{"overflow toward zero", 1e6, RoundTowardZero, MaxValue},
+		{"negative overflow toward positive", -1e6, RoundTowardPositive, MinValue},
+		{"negative overflow toward negative", -1e6, RoundTowardNegative, NegativeInfinity},
+		{"negative zero", math.Copysign(0, -1), RoundNearestEven, NegativeZero},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromFloat64WithMode(tt.input, ModeIEEE, tt.rounding)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("FromFloat64WithMode(%g) = 0x%04X, want 0x%04X", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFromFloat64WithModeExact(t *testing.T) {
+	if _, err := FromFloat64WithMode(1.5, ModeExact, RoundNearestEven); err != nil {
+		t.Errorf("FromFloat64WithMode(1.5, ModeExact) unexpected error: %v", err)
+	}
+	_, err := FromFloat64WithMode(1.1, ModeExact, RoundNearestEven)
+	if err16, ok := err.(*Float16Error); !ok || err16.Code != ErrInexact {
+		t.Errorf("FromFloat64WithMode(1.1, ModeExact) error = %v, want ErrInexact", err)
+	}
+	if _, err := FromFloat64WithMode(65510, ModeStrict, RoundNearestEven); err != nil {
+		t.Errorf("FromFloat64WithMode(65510, ModeStrict) unexpected error: %v", err)
+	}
+	if _, err := FromFloat64WithMode(math.Ldexp(1, -24), ModeStrict, RoundNearestEven); err != nil {
+		t.Errorf("FromFloat64WithMode(2^-24, ModeStrict) unexpected error: %v", err)
+	}
+}
+
+func TestToFloat32Subnormal(t *testing.T) {
+	tests := []struct {
+		f    Float16
+		want float32
+	}{
+		{0x0001, float32(math.Ldexp(1, -24))},
+		{0x8001, -float32(math.Ldexp(1, -24))},
+		{0x0200, float32(math.Ldexp(1, -15))},
+		{0x03FF, float32(math.Ldexp(1023, -24))},
+		{0x0400, float32(math.Ldexp(1, -14))},
+	}
+	for _, tt := range tests {
+		if got := tt.f.ToFloat32(); got != tt.want {
+			t.Errorf("Float16(%#04x).ToFloat32() = %g, want %g", uint16(tt.f), got, tt.want)
+		}
+		if got := tt.f.ToFloat64(); got != float64(tt.want) {
+			t.Errorf("Float16(%#04x).ToFloat64() = %g, want %g", uint16(tt.f), got, tt.want)
+		}
+	}
+}
+
+func TestFromFloat32Rounding(t *testing.T) {
+	tests := []struct {
+		f    float32
+		want Float16
+	}{
+		// Truncation would give 0x3C00, 0x3C01 and 0x3BFF
+		{float32(1 + math.Ldexp(1, -11) + math.Ldexp(1, -20)), 0x3C01},
+		{float32(1 + 3*math.Ldexp(1, -11)), 0x3C02},
+		{float32(1 - math.Ldexp(1, -24)), 0x3C00},
+		{float32(1 + math.Ldexp(1, -11)), 0x3C00}, // tie to even
+		{65519, MaxValue},
+		{65520, PositiveInfinity},
+		{-65520, NegativeInfinity},
+		{float32(math.Ldexp(1, -25)), PositiveZero},
+		{float32(math.Ldexp(3, -26)), 0x0001},
+		{float32(math.Ldexp(3, -25)), 0x0002},
+		{float32(math.Ldexp(1, -14) - math.Ldexp(1, -25)), 0x0400}, // rounds up into the normals
+		{1e-40, PositiveZero},                                      // a float32 subnormal
+		{float32(math.Copysign(0, -1)), NegativeZero},
+	}
+	for _, tt := range tests {
+		if got := FromFloat32(tt.f); got != tt.want {
+			t.Errorf("FromFloat32(%g) = %#04x, want %#04x", tt.f, uint16(got), uint16(tt.want))
+		}
+	}
+}
+
+func TestFromFloat64SingleRounding(t *testing.T) {
+	tests := []struct {
+		f    float64
+		want Float16
+	}{
+		// Rounding to float32 first would produce a tie and round down to 1
+		{1 + math.Ldexp(1, -11) + math.Ldexp(1, -30), 0x3C01},
+		{-(1 + math.Ldexp(1, -11) + math.Ldexp(1, -30)), 0xBC01},
+		{math.Ldexp(1, -25) + math.Ldexp(1, -60), 0x0001},
+		{math.Inf(-1), NegativeInfinity},
+		{1e300, PositiveInfinity},
+	}
+	for _, tt := range tests {
+		if got := FromFloat64(tt.f); got != tt.want {
+			t.Errorf("FromFloat64(%g) = %#04x, want %#04x", tt.f, uint16(got), uint16(tt.want))
+		}
+	}
+}
+
 func TestShouldRound(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -125,17 +256,38 @@ func TestShouldRound(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := shouldRound(tt.mantissa, tt.shift, tt.sign)
+			got := shouldRound(uint64(tt.mantissa), tt.shift, tt.sign, tt.mode)
 			if got != tt.shouldRound {
-				t.Errorf("shouldRound(%d, %d, %d) = %v, want %v", tt.mantissa, tt.shift, tt.sign, got, tt.shouldRound)
+				t.Errorf("shouldRound(%d, %d, %d, %v) = %v, want %v", tt.mantissa, tt.shift, tt.sign, tt.mode, got, tt.shouldRound)
 			}
 		})
 	}
 }
 
-func TestParse(t *testing.T) {
-	_, err := Parse("1.0")
-	if err == nil {
-		t.Error("Expected error, got nil")
+func TestParseFloat(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Float16
+	}{
+		{"1.0", 0x3C00},
+		{"-2.5", 0xC100},
+		{"-0", NegativeZero},
+		{"Inf", PositiveInfinity},
+		{"-Inf", NegativeInfinity},
+	}
+	for _, tt := range tests {
+		got, err := ParseFloat(tt.s, 16)
+		if err != nil {
+			t.Errorf("ParseFloat(%q) unexpected error: %v", tt.s, err)
+		} else if got != tt.want {
+			t.Errorf("ParseFloat(%q) = 0x%04X, want 0x%04X", tt.s, got, tt.want)
+		}
+	}
+
+	if got, err := ParseFloat("NaN", 16); err != nil || !got.IsNaN() {
+		t.Errorf("ParseFloat(\"NaN\") = %v, %v, want NaN", got, err)
+	}
+	if _, err := ParseFloat("1.0.0", 16); err == nil {
+		t.Error("ParseFloat(\"1.0.0\") expected error, got nil")
 	}
 }
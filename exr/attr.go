@@ -0,0 +1,177 @@
+package exr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"math"
+)
+
+// Attribute is a named, typed header attribute.
+//
+// Value holds a Go value matching Type:
+//
+//	string          string
+//	int             int32
+//	float           float32
+//	double          float64
+//	v2i             [2]int32
+//	v2f             [2]float32
+//	v3f             [3]float32
+//	box2i           image.Rectangle (exclusive maximum)
+//	box2f           [4]float32 (xMin, yMin, xMax, yMax)
+//	chromaticities  [8]float32
+//	m44f            [16]float32
+//
+// Attributes of any other type carry their raw little-endian value as
+// []byte and are preserved unchanged.
+type Attribute struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+// standardAttrs maps the attributes held in Header fields to their types
+var standardAttrs = map[string]string{
+	"channels":           "chlist",
+	"compression":        "compression",
+	"dataWindow":         "box2i",
+	"displayWindow":      "box2i",
+	"lineOrder":          "lineOrder",
+	"pixelAspectRatio":   "float",
+	"screenWindowCenter": "v2f",
+	"screenWindowWidth":  "float",
+}
+
+// appendFloats appends little-endian float32 values
+func appendFloats(dst []byte, fs ...float32) []byte {
+	for _, f := range fs {
+		dst = binary.LittleEndian.AppendUint32(dst, math.Float32bits(f))
+	}
+	return dst
+}
+
+// appendBox2i appends r as an inclusive box2i
+func appendBox2i(dst []byte, r image.Rectangle) []byte {
+	for _, v := range []int{r.Min.X, r.Min.Y, r.Max.X - 1, r.Max.Y - 1} {
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(int32(v)))
+	}
+	return dst
+}
+
+// encodeValue returns the serialized value of a
+func (a Attribute) encodeValue() ([]byte, error) {
+	var b []byte
+	ok := true
+	switch a.Type {
+	case "string":
+		var s string
+		s, ok = a.Value.(string)
+		b = []byte(s)
+	case "int":
+		var v int32
+		v, ok = a.Value.(int32)
+		b = binary.LittleEndian.AppendUint32(b, uint32(v))
+	case "float":
+		var v float32
+		v, ok = a.Value.(float32)
+		b = appendFloats(b, v)
+	case "double":
+		var v float64
+		v, ok = a.Value.(float64)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	case "v2i":
+		var v [2]int32
+		v, ok = a.Value.([2]int32)
+		b = binary.LittleEndian.AppendUint32(b, uint32(v[0]))
+		b = binary.LittleEndian.AppendUint32(b, uint32(v[1]))
+	case "v2f":
+		var v [2]float32
+		v, ok = a.Value.([2]float32)
+		b = appendFloats(b, v[:]...)
+	case "v3f":
+		var v [3]float32
+		v, ok = a.Value.([3]float32)
+		b = appendFloats(b, v[:]...)
+	case "box2i":
+		var v image.Rectangle
+		v, ok = a.Value.(image.Rectangle)
+		b = appendBox2i(b, v)
+	case "box2f":
+		var v [4]float32
+		v, ok = a.Value.([4]float32)
+		b = appendFloats(b, v[:]...)
+	case "chromaticities":
+		var v [8]float32
+		v, ok = a.Value.([8]float32)
+		b = appendFloats(b, v[:]...)
+	case "m44f":
+		var v [16]float32
+		v, ok = a.Value.([16]float32)
+		b = appendFloats(b, v[:]...)
+	default:
+		b, ok = a.Value.([]byte)
+	}
+	if !ok {
+		return nil, fmt.Errorf("exr: attribute %q of type %s has Go type %T", a.Name, a.Type, a.Value)
+	}
+	return b, nil
+}
+
+// floats decodes n little-endian float32 values
+func floats(b []byte, n int) []float32 {
+	fs := make([]float32, n)
+	for i := range fs {
+		fs[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return fs
+}
+
+// decodeBox2i decodes an inclusive box2i into a rectangle, which is empty
+// rather than canonicalized when the box is inverted
+func decodeBox2i(b []byte) image.Rectangle {
+	v := func(i int) int { return int(int32(binary.LittleEndian.Uint32(b[4*i:]))) }
+	return image.Rectangle{Min: image.Pt(v(0), v(1)), Max: image.Pt(v(2)+1, v(3)+1)}
+}
+
+// attrSizes gives the value size of fixed-size attribute types
+var attrSizes = map[string]int{
+	"int": 4, "float": 4, "double": 8, "v2i": 8, "v2f": 8, "v3f": 12,
+	"box2i": 16, "box2f": 16, "chromaticities": 32, "m44f": 64,
+	"compression": 1, "lineOrder": 1,
+}
+
+// decodeAttribute converts a raw attribute value to its Go form
+func decodeAttribute(name, typ string, b []byte) (Attribute, error) {
+	a := Attribute{Name: name, Type: typ}
+	if size, ok := attrSizes[typ]; ok && len(b) != size {
+		return a, fmt.Errorf("%w: attribute %q of type %s has %d bytes, want %d", ErrFormat, name, typ, len(b), size)
+	}
+	switch typ {
+	case "string":
+		a.Value = string(b)
+	case "int":
+		a.Value = int32(binary.LittleEndian.Uint32(b))
+	case "float":
+		a.Value = floats(b, 1)[0]
+	case "double":
+		a.Value = math.Float64frombits(binary.LittleEndian.Uint64(b))
+	case "v2i":
+		a.Value = [2]int32{int32(binary.LittleEndian.Uint32(b)), int32(binary.LittleEndian.Uint32(b[4:]))}
+	case "v2f":
+		a.Value = [2]float32(floats(b, 2))
+	case "v3f":
+		a.Value = [3]float32(floats(b, 3))
+	case "box2i":
+		a.Value = decodeBox2i(b)
+	case "box2f":
+		a.Value = [4]float32(floats(b, 4))
+	case "chromaticities":
+		a.Value = [8]float32(floats(b, 8))
+	case "m44f":
+		a.Value = [16]float32(floats(b, 16))
+	default:
+		a.Value = append([]byte(nil), b...)
+	}
+	return a, nil
+}
@@ -0,0 +1,183 @@
+package exr
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// The RLE and ZIP methods first split the little-endian samples into a
+// plane of low bytes followed by a plane of high bytes, then replace each
+// byte by its difference from the previous one. Both steps make runs and
+// repetitions more likely in smooth images.
+
+// interleave splits even and odd bytes of src into two halves of dst
+func interleave(dst, src []byte) {
+	half := (len(src) + 1) / 2
+	for i, b := range src {
+		if i%2 == 0 {
+			dst[i/2] = b
+		} else {
+			dst[half+i/2] = b
+		}
+	}
+}
+
+// deinterleave is the inverse of interleave
+func deinterleave(dst, src []byte) {
+	half := (len(src) + 1) / 2
+	for i := range dst {
+		if i%2 == 0 {
+			dst[i] = src[i/2]
+		} else {
+			dst[i] = src[half+i/2]
+		}
+	}
+}
+
+// predict replaces bytes with their offset differences, in place
+func predict(b []byte) {
+	for i := len(b) - 1; i > 0; i-- {
+		b[i] = b[i] - b[i-1] + 128
+	}
+}
+
+// unpredict is the inverse of predict
+func unpredict(b []byte) {
+	for i := 1; i < len(b); i++ {
+		b[i] = b[i-1] + b[i] - 128
+	}
+}
+
+// prepare interleaves and predicts raw chunk data
+func prepare(raw []byte) []byte {
+	t := make([]byte, len(raw))
+	interleave(t, raw)
+	predict(t)
+	return t
+}
+
+// restore undoes prepare into dst
+func restore(dst, t []byte) {
+	unpredict(t)
+	deinterleave(dst, t)
+}
+
+// Run lengths of the RLE method
+const (
+	minRunLength = 3
+	maxRunLength = 127
+)
+
+// rleCompress encodes runs of at least three equal bytes as a count minus
+// one followed by the byte, and other bytes as a negated count followed by
+// the literal bytes
+func rleCompress(in []byte) []byte {
+	var out []byte
+	for start := 0; start < len(in); {
+		end := start + 1
+		for end < len(in) && in[end] == in[start] && end-start < maxRunLength {
+			end++
+		}
+		if end-start >= minRunLength {
+			out = append(out, byte(end-start-1), in[start])
+			start = end
+			continue
+		}
+		// Extend the literal run until the next run of three equal bytes
+		for end < len(in) && end-start < maxRunLength &&
+			(end+2 >= len(in) || in[end] != in[end+1] || in[end+1] != in[end+2]) {
+			end++
+		}
+		out = append(out, byte(-int8(end-start)))
+		out = append(out, in[start:end]...)
+		start = end
+	}
+	return out
+}
+
+// rleUncompress decodes RLE data into dst, which must be filled exactly
+func rleUncompress(dst, in []byte) error {
+	n := 0
+	for len(in) > 0 {
+		count := int(int8(in[0]))
+		in = in[1:]
+		if count < 0 {
+			count = -count
+			if count > len(in) || n+count > len(dst) {
+				return fmt.Errorf("%w: RLE literal overruns block", ErrFormat)
+			}
+			n += copy(dst[n:], in[:count])
+			in = in[count:]
+			continue
+		}
+		count++
+		if len(in) == 0 || n+count > len(dst) {
+			return fmt.Errorf("%w: RLE run overruns block", ErrFormat)
+		}
+		for i := 0; i < count; i++ {
+			dst[n+i] = in[0]
+		}
+		n += count
+		in = in[1:]
+	}
+	if n != len(dst) {
+		return fmt.Errorf("%w: RLE block has %d bytes, want %d", ErrFormat, n, len(dst))
+	}
+	return nil
+}
+
+// compressChunk compresses raw chunk data. The caller stores raw instead
+// when the result is not smaller.
+func compressChunk(c Compression, raw []byte) ([]byte, error) {
+	switch c {
+	case NoCompression:
+		return raw, nil
+	case RLECompression:
+		return rleCompress(prepare(raw)), nil
+	case ZIPSCompression, ZIPCompression:
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(prepare(raw)); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("%w: %v", ErrUnsupported, c)
+}
+
+// decompressChunk decodes chunk data into dst. Data whose size equals the
+// uncompressed size is stored raw.
+func decompressChunk(c Compression, dst, data []byte) error {
+	if len(data) == len(dst) {
+		copy(dst, data)
+		return nil
+	}
+	t := make([]byte, len(dst))
+	switch c {
+	case RLECompression:
+		if err := rleUncompress(t, data); err != nil {
+			return err
+		}
+	case ZIPSCompression, ZIPCompression:
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrFormat, err)
+		}
+		if _, err := io.ReadFull(zr, t); err != nil {
+			return fmt.Errorf("%w: zlib block: %v", ErrFormat, err)
+		}
+		var extra [1]byte
+		if n, _ := zr.Read(extra[:]); n != 0 {
+			return fmt.Errorf("%w: zlib block longer than %d bytes", ErrFormat, len(dst))
+		}
+	default:
+		return fmt.Errorf("%w: chunk has %d bytes, want %d", ErrFormat, len(data), len(dst))
+	}
+	restore(dst, t)
+	return nil
+}
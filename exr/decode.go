@@ -0,0 +1,318 @@
+package exr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os"
+
+	"github.com/zerfoo/float16"
+)
+
+// maxPixels bounds the data window of a decoded image
+const maxPixels = 1 << 30
+
+// channelInfo is an entry of the chlist attribute
+type channelInfo struct {
+	name      string
+	pixelType int32
+	linear    bool
+}
+
+// reader consumes a byte slice, recording the first error
+type reader struct {
+	b   []byte
+	pos int
+	err error
+}
+
+func (r *reader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: "+format, append([]interface{}{ErrFormat}, args...)...)
+	}
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.b)-r.pos {
+		r.fail("unexpected end of data at offset %d", r.pos)
+		return nil
+	}
+	b := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *reader) u32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) u64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+// cstring reads a null-terminated string of at most max bytes
+func (r *reader) cstring(max int) string {
+	if r.err != nil {
+		return ""
+	}
+	i := bytes.IndexByte(r.b[r.pos:], 0)
+	if i < 0 || i > max {
+		r.fail("bad string at offset %d", r.pos)
+		return ""
+	}
+	s := string(r.b[r.pos : r.pos+i])
+	r.pos += i + 1
+	return s
+}
+
+// ReadFile reads an OpenEXR image from the named file
+func ReadFile(name string) (*Image, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Decode reads an OpenEXR image from r
+func Decode(r io.Reader) (*Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes an OpenEXR image held in memory
+func Parse(data []byte) (*Image, error) {
+	r := &reader{b: data}
+	if m := r.u32(); r.err == nil && m != magic {
+		return nil, fmt.Errorf("%w: not an OpenEXR file", ErrFormat)
+	}
+	version := r.u32()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if version&0xFF != formatVersion {
+		return nil, fmt.Errorf("%w: format version %d", ErrUnsupported, version&0xFF)
+	}
+	switch {
+	case version&flagTiled != 0:
+		return nil, fmt.Errorf("%w: tiled image", ErrUnsupported)
+	case version&flagDeep != 0:
+		return nil, fmt.Errorf("%w: deep image", ErrUnsupported)
+	case version&flagMultipart != 0:
+		return nil, fmt.Errorf("%w: multi-part file", ErrUnsupported)
+	}
+	maxName := 31
+	if version&flagLongNames != 0 {
+		maxName = 255
+	}
+
+	img := &Image{Header: Header{PixelAspectRatio: 1, ScreenWindowWidth: 1}}
+	var channels []channelInfo
+	seen := map[string]bool{}
+	for {
+		name := r.cstring(maxName)
+		if r.err != nil {
+			return nil, r.err
+		}
+		if name == "" {
+			break
+		}
+		typ := r.cstring(maxName)
+		size := int(int32(r.u32()))
+		value := r.bytes(size)
+		if r.err != nil {
+			return nil, r.err
+		}
+		seen[name] = true
+		if err := img.setAttribute(name, typ, value, &channels); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range []string{"channels", "compression", "dataWindow"} {
+		if !seen[name] {
+			return nil, fmt.Errorf("%w: missing %s attribute", ErrFormat, name)
+		}
+	}
+	if !seen["displayWindow"] {
+		img.DisplayWindow = img.DataWindow
+	}
+
+	lines := img.Compression.linesPerBlock()
+	if lines == 0 {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupported, img.Compression)
+	}
+	dw := img.DataWindow
+	width, height := int64(dw.Dx()), int64(dw.Dy())
+	if dw.Empty() || width*height > maxPixels || width*height*int64(len(channels)) > maxPixels {
+		return nil, fmt.Errorf("%w: data window %v", ErrFormat, dw)
+	}
+
+	// Offset table, one entry per block
+	chunks := int((height + int64(lines) - 1) / int64(lines))
+	if chunks > (len(data)-r.pos)/8 {
+		return nil, fmt.Errorf("%w: offset table truncated", ErrFormat)
+	}
+	offsets := make([]uint64, chunks)
+	for i := range offsets {
+		offsets[i] = r.u64()
+	}
+
+	for _, ch := range channels {
+		img.Channels = append(img.Channels, Channel{Name: ch.name, Linear: ch.linear, Data: make([]float16.Float16, width*height)})
+	}
+	// Bytes per sample of each channel, in file order
+	sizes := make([]int, len(channels))
+	lineBytes := 0
+	for i, ch := range channels {
+		sizes[i] = 2
+		if ch.pixelType == pixelFloat {
+			sizes[i] = 4
+		}
+		lineBytes += sizes[i] * int(width)
+	}
+
+	done := make([]bool, chunks)
+	for _, off := range offsets {
+		if off > uint64(len(data)) {
+			return nil, fmt.Errorf("%w: chunk offset %d beyond end of file", ErrFormat, off)
+		}
+		cr := &reader{b: data, pos: int(off)}
+		y := int(int32(cr.u32()))
+		size := int(int32(cr.u32()))
+		chunk := cr.bytes(size)
+		if cr.err != nil {
+			return nil, cr.err
+		}
+		if y < dw.Min.Y || y >= dw.Max.Y || (y-dw.Min.Y)%lines != 0 {
+			return nil, fmt.Errorf("%w: chunk at line %d", ErrFormat, y)
+		}
+		block := (y - dw.Min.Y) / lines
+		if done[block] {
+			return nil, fmt.Errorf("%w: duplicate chunk at line %d", ErrFormat, y)
+		}
+		done[block] = true
+
+		n := min(lines, dw.Max.Y-y)
+		raw := make([]byte, n*lineBytes)
+		if err := decompressChunk(img.Compression, raw, chunk); err != nil {
+			return nil, err
+		}
+		img.unpack(raw, y-dw.Min.Y, n, channels, sizes)
+	}
+	return img, nil
+}
+
+// unpack copies n decoded scanlines starting at row into the channel planes
+func (img *Image) unpack(raw []byte, row, n int, channels []channelInfo, sizes []int) {
+	width := img.DataWindow.Dx()
+	for l := 0; l < n; l++ {
+		for i, ch := range channels {
+			dst := img.Channels[i].Data[(row+l)*width : (row+l+1)*width]
+			if ch.pixelType == pixelHalf {
+				float16.DecodeFloat16s(dst, raw[:2*width], binary.LittleEndian)
+			} else {
+				for x := range dst {
+					dst[x] = float16.FromFloat32(math.Float32frombits(binary.LittleEndian.Uint32(raw[4*x:])))
+				}
+			}
+			raw = raw[sizes[i]*width:]
+		}
+	}
+}
+
+// setAttribute stores a header attribute, parsing the standard ones
+func (img *Image) setAttribute(name, typ string, value []byte, channels *[]channelInfo) error {
+	want, ok := standardAttrs[name]
+	if !ok {
+		a, err := decodeAttribute(name, typ, value)
+		if err != nil {
+			return err
+		}
+		img.Attributes = append(img.Attributes, a)
+		return nil
+	}
+
+	if typ != want {
+		return fmt.Errorf("%w: attribute %q has type %s, want %s", ErrFormat, name, typ, want)
+	}
+	if name == "channels" {
+		chs, err := parseChannels(value)
+		*channels = chs
+		return err
+	}
+	a, err := decodeAttribute(name, typ, value)
+	if err != nil {
+		return err
+	}
+	switch name {
+	case "compression":
+		img.Compression = Compression(value[0])
+	case "dataWindow":
+		img.DataWindow = a.Value.(image.Rectangle)
+	case "displayWindow":
+		img.DisplayWindow = a.Value.(image.Rectangle)
+	case "lineOrder":
+		img.LineOrder = LineOrder(value[0])
+	case "pixelAspectRatio":
+		img.PixelAspectRatio = a.Value.(float32)
+	case "screenWindowCenter":
+		img.ScreenWindowCenter = a.Value.([2]float32)
+	case "screenWindowWidth":
+		img.ScreenWindowWidth = a.Value.(float32)
+	}
+	return nil
+}
+
+// parseChannels decodes a chlist attribute
+func parseChannels(b []byte) ([]channelInfo, error) {
+	r := &reader{b: b}
+	var chs []channelInfo
+	for {
+		name := r.cstring(255)
+		if r.err != nil {
+			return nil, r.err
+		}
+		if name == "" {
+			break
+		}
+		pixelType := int32(r.u32())
+		flags := r.bytes(4)
+		xs, ys := int32(r.u32()), int32(r.u32())
+		if r.err != nil {
+			return nil, r.err
+		}
+		switch {
+		case pixelType == pixelUint:
+			return nil, fmt.Errorf("%w: UINT channel %q", ErrUnsupported, name)
+		case pixelType != pixelHalf && pixelType != pixelFloat:
+			return nil, fmt.Errorf("%w: channel %q has pixel type %d", ErrFormat, name, pixelType)
+		case xs != 1 || ys != 1:
+			return nil, fmt.Errorf("%w: channel %q is subsampled", ErrUnsupported, name)
+		}
+		chs = append(chs, channelInfo{name: name, pixelType: pixelType, linear: flags[0] != 0})
+	}
+	if len(chs) == 0 {
+		return nil, fmt.Errorf("%w: no channels", ErrFormat)
+	}
+	for i := 1; i < len(chs); i++ {
+		if chs[i].name <= chs[i-1].name {
+			return nil, fmt.Errorf("%w: channels not sorted by name", ErrFormat)
+		}
+	}
+	return chs, nil
+}
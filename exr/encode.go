@@ -0,0 +1,160 @@
+package exr
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/zerfoo/float16"
+)
+
+// appendAttribute appends one header attribute
+func appendAttribute(dst []byte, name, typ string, value []byte) []byte {
+	dst = append(dst, name...)
+	dst = append(dst, 0)
+	dst = append(dst, typ...)
+	dst = append(dst, 0)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(value)))
+	return append(dst, value...)
+}
+
+// Encode writes img as a single-part scanline OpenEXR file with HALF
+// channels, in increasing line order and using img.Compression. Channels
+// are stored sorted by name, as the format requires.
+func Encode(w io.Writer, img *Image) error {
+	lines := img.Compression.linesPerBlock()
+	if lines == 0 {
+		return fmt.Errorf("%w: %v", ErrUnsupported, img.Compression)
+	}
+	dw := img.DataWindow
+	if dw.Empty() {
+		return fmt.Errorf("exr: empty data window %v", dw)
+	}
+	width, height := dw.Dx(), dw.Dy()
+
+	order := make([]int, len(img.Channels))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return img.Channels[order[i]].Name < img.Channels[order[j]].Name })
+	if len(order) == 0 {
+		return fmt.Errorf("exr: image has no channels")
+	}
+
+	long := false
+	var chlist []byte
+	for k, i := range order {
+		c := &img.Channels[i]
+		switch {
+		case c.Name == "":
+			return fmt.Errorf("exr: channel %d has no name", i)
+		case k > 0 && c.Name == img.Channels[order[k-1]].Name:
+			return fmt.Errorf("exr: duplicate channel %q", c.Name)
+		case len(c.Data) != width*height:
+			return fmt.Errorf("exr: channel %q has %d samples, want %d", c.Name, len(c.Data), width*height)
+		}
+		long = long || len(c.Name) > 31
+		var linear byte
+		if c.Linear {
+			linear = 1
+		}
+		chlist = append(chlist, c.Name...)
+		chlist = append(chlist, 0)
+		chlist = binary.LittleEndian.AppendUint32(chlist, pixelHalf)
+		chlist = append(chlist, linear, 0, 0, 0)
+		chlist = binary.LittleEndian.AppendUint32(chlist, 1)
+		chlist = binary.LittleEndian.AppendUint32(chlist, 1)
+	}
+	chlist = append(chlist, 0)
+
+	// Header: standard attributes in alphabetical order, then the others
+	var hdr []byte
+	hdr = appendAttribute(hdr, "channels", "chlist", chlist)
+	hdr = appendAttribute(hdr, "compression", "compression", []byte{byte(img.Compression)})
+	hdr = appendAttribute(hdr, "dataWindow", "box2i", appendBox2i(nil, dw))
+	hdr = appendAttribute(hdr, "displayWindow", "box2i", appendBox2i(nil, img.DisplayWindow))
+	hdr = appendAttribute(hdr, "lineOrder", "lineOrder", []byte{byte(IncreasingY)})
+	hdr = appendAttribute(hdr, "pixelAspectRatio", "float", appendFloats(nil, img.PixelAspectRatio))
+	hdr = appendAttribute(hdr, "screenWindowCenter", "v2f", appendFloats(nil, img.ScreenWindowCenter[:]...))
+	hdr = appendAttribute(hdr, "screenWindowWidth", "float", appendFloats(nil, img.ScreenWindowWidth))
+	for _, a := range img.Attributes {
+		if _, ok := standardAttrs[a.Name]; ok {
+			return fmt.Errorf("exr: attribute %q must be set through Header fields", a.Name)
+		}
+		if a.Name == "" || a.Type == "" {
+			return fmt.Errorf("exr: attribute %q has no name or type", a.Name)
+		}
+		value, err := a.encodeValue()
+		if err != nil {
+			return err
+		}
+		long = long || len(a.Name) > 31 || len(a.Type) > 31
+		hdr = appendAttribute(hdr, a.Name, a.Type, value)
+	}
+	hdr = append(hdr, 0)
+
+	version := uint32(formatVersion)
+	if long {
+		version |= flagLongNames
+	}
+
+	// Compress every block up front to build the offset table
+	chunks := (height + lines - 1) / lines
+	blocks := make([][]byte, chunks)
+	raw := make([]byte, 0, lines*width*2*len(order))
+	for b := range blocks {
+		row := b * lines
+		n := min(lines, height-row)
+		raw = raw[:0]
+		for l := row; l < row+n; l++ {
+			for _, i := range order {
+				raw = float16.AppendFloat16s(raw, binary.LittleEndian, img.Channels[i].Data[l*width:(l+1)*width]...)
+			}
+		}
+		data, err := compressChunk(img.Compression, raw)
+		if err != nil {
+			return err
+		}
+		if len(data) >= len(raw) {
+			data = raw
+		}
+		chunk := binary.LittleEndian.AppendUint32(nil, uint32(int32(dw.Min.Y+row)))
+		chunk = binary.LittleEndian.AppendUint32(chunk, uint32(len(data)))
+		blocks[b] = append(chunk, data...)
+	}
+
+	bw := bufio.NewWriter(w)
+	var prefix [8]byte
+	binary.LittleEndian.PutUint32(prefix[:], magic)
+	binary.LittleEndian.PutUint32(prefix[4:], version)
+	bw.Write(prefix[:])
+	bw.Write(hdr)
+
+	offset := uint64(len(prefix)+len(hdr)) + 8*uint64(chunks)
+	var table []byte
+	for _, blk := range blocks {
+		table = binary.LittleEndian.AppendUint64(table, offset)
+		offset += uint64(len(blk))
+	}
+	bw.Write(table)
+	for _, blk := range blocks {
+		bw.Write(blk)
+	}
+	return bw.Flush()
+}
+
+// WriteFile writes img to the named file
+func WriteFile(name string, img *Image) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,164 @@
+// Package exr reads and writes OpenEXR scanline images with half-precision
+// channels.
+//
+// Each channel is a plane of float16.Float16 samples covering the data
+// window in row-major order. Single-part scanline files with the
+// NO_COMPRESSION, RLE, ZIPS and ZIP methods are supported. Files are always
+// written with HALF channels; FLOAT channels are rounded to half precision
+// when read. Tiled, deep and multi-part files, subsampled channels, UINT
+// channels and the lossy or wavelet compression methods (PIZ, PXR24, B44,
+// DWA) are not supported.
+//
+// See: https://openexr.com/en/latest/OpenEXRFileLayout.html
+package exr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/zerfoo/float16"
+)
+
+// magic is the first four bytes of every OpenEXR file
+const magic = 20000630
+
+// Version field: format version 2 in the low byte, flags above
+const (
+	formatVersion = 2
+	flagTiled     = 0x200
+	flagLongNames = 0x400
+	flagDeep      = 0x800
+	flagMultipart = 0x1000
+)
+
+// Pixel types of the chlist attribute
+const (
+	pixelUint  = 0
+	pixelHalf  = 1
+	pixelFloat = 2
+)
+
+// Errors returned when decoding OpenEXR data
+var (
+	ErrFormat      = errors.New("exr: malformed file")
+	ErrUnsupported = errors.New("exr: unsupported feature")
+)
+
+// Compression is the method used to compress scanline blocks
+type Compression uint8
+
+// Compression methods
+const (
+	NoCompression Compression = iota
+	RLECompression
+	ZIPSCompression
+	ZIPCompression
+	PIZCompression
+	PXR24Compression
+	B44Compression
+	B44ACompression
+	DWAACompression
+	DWABCompression
+)
+
+func (c Compression) String() string {
+	names := [...]string{"NO_COMPRESSION", "RLE_COMPRESSION", "ZIPS_COMPRESSION", "ZIP_COMPRESSION",
+		"PIZ_COMPRESSION", "PXR24_COMPRESSION", "B44_COMPRESSION", "B44A_COMPRESSION",
+		"DWAA_COMPRESSION", "DWAB_COMPRESSION"}
+	if int(c) < len(names) {
+		return names[c]
+	}
+	return fmt.Sprintf("Compression(%d)", uint8(c))
+}
+
+// linesPerBlock returns the number of scanlines stored in each chunk, or 0
+// for unsupported methods
+func (c Compression) linesPerBlock() int {
+	switch c {
+	case NoCompression, RLECompression, ZIPSCompression:
+		return 1
+	case ZIPCompression:
+		return 16
+	}
+	return 0
+}
+
+// LineOrder is the order in which scanline blocks are stored
+type LineOrder uint8
+
+// Line orders
+const (
+	IncreasingY LineOrder = iota
+	DecreasingY
+	RandomY
+)
+
+// Header holds the standard attributes of an image. Windows are in pixel
+// coordinates with exclusive maxima, as for image.Rectangle; the file stores
+// them as inclusive box2i values.
+type Header struct {
+	Compression        Compression
+	DataWindow         image.Rectangle
+	DisplayWindow      image.Rectangle
+	LineOrder          LineOrder
+	PixelAspectRatio   float32
+	ScreenWindowCenter [2]float32
+	ScreenWindowWidth  float32
+
+	// Attributes holds any other attributes, in file order
+	Attributes []Attribute
+}
+
+// Channel is one plane of half-precision samples covering the data window
+type Channel struct {
+	Name string
+	// Linear reports that the channel holds perceptually linear values
+	Linear bool
+	Data   []float16.Float16
+}
+
+// Image is a scanline OpenEXR image
+type Image struct {
+	Header
+	Channels []Channel
+}
+
+// NewImage returns a width×height image with zeroed channels of the given
+// names, ZIP compression and matching data and display windows
+func NewImage(width, height int, names ...string) *Image {
+	r := image.Rect(0, 0, width, height)
+	img := &Image{Header: Header{
+		Compression:       ZIPCompression,
+		DataWindow:        r,
+		DisplayWindow:     r,
+		LineOrder:         IncreasingY,
+		PixelAspectRatio:  1,
+		ScreenWindowWidth: 1,
+	}}
+	for _, name := range names {
+		img.Channels = append(img.Channels, Channel{Name: name, Data: make([]float16.Float16, width*height)})
+	}
+	return img
+}
+
+// Channel returns the channel with the given name, or nil
+func (img *Image) Channel(name string) *Channel {
+	for i := range img.Channels {
+		if img.Channels[i].Name == name {
+			return &img.Channels[i]
+		}
+	}
+	return nil
+}
+
+// At returns the sample of the named channel at (x, y) in data window
+// coordinates, or zero if the channel does not exist or the point lies
+// outside the data window
+func (img *Image) At(name string, x, y int) float16.Float16 {
+	c := img.Channel(name)
+	if c == nil || !image.Pt(x, y).In(img.DataWindow) {
+		return 0
+	}
+	return c.Data[(y-img.DataWindow.Min.Y)*img.DataWindow.Dx()+x-img.DataWindow.Min.X]
+}
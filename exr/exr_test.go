@@ -0,0 +1,329 @@
+package exr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+// testImage returns an image with smooth, noisy and special-valued channels
+func testImage(width, height int, c Compression) *Image {
+	img := NewImage(width, height, "R", "G", "B", "A")
+	img.Compression = c
+	rng := rand.New(rand.NewSource(1))
+	specials := []float16.Float16{float16.PositiveInfinity, float16.NegativeInfinity, 0x7E01, float16.NegativeZero, float16.SmallestSubnormal, float16.MaxValue}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			img.Channels[0].Data[i] = float16.FromFloat32(float32(x) / float32(width))
+			img.Channels[1].Data[i] = float16.FromFloat32(float32(y) * 0.25)
+			img.Channels[2].Data[i] = float16.FromBits(uint16(rng.Intn(0x7C00)))
+			img.Channels[3].Data[i] = specials[i%len(specials)]
+		}
+	}
+	return img
+}
+
+func encode(t *testing.T, img *Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := Encode(&buf, img); err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func checkImage(t *testing.T, got, want *Image) {
+	t.Helper()
+	if got.DataWindow != want.DataWindow || got.DisplayWindow != want.DisplayWindow {
+		t.Errorf("windows = %v %v, want %v %v", got.DataWindow, got.DisplayWindow, want.DataWindow, want.DisplayWindow)
+	}
+	if got.Compression != want.Compression {
+		t.Errorf("Compression = %v, want %v", got.Compression, want.Compression)
+	}
+	for _, wc := range want.Channels {
+		gc := got.Channel(wc.Name)
+		if gc == nil {
+			t.Errorf("channel %q missing", wc.Name)
+			continue
+		}
+		if gc.Linear != wc.Linear {
+			t.Errorf("channel %q Linear = %v, want %v", wc.Name, gc.Linear, wc.Linear)
+		}
+		for i := range wc.Data {
+			if gc.Data[i] != wc.Data[i] {
+				t.Errorf("channel %q sample %d = %#04x, want %#04x", wc.Name, i, gc.Data[i].Bits(), wc.Data[i].Bits())
+				break
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	sizes := []struct{ w, h int }{{1, 1}, {7, 5}, {64, 33}, {3, 40}}
+	for _, c := range []Compression{NoCompression, RLECompression, ZIPSCompression, ZIPCompression} {
+		for _, sz := range sizes {
+			t.Run(c.String(), func(t *testing.T) {
+				img := testImage(sz.w, sz.h, c)
+				got, err := Parse(encode(t, img))
+				if err != nil {
+					t.Fatalf("Parse() unexpected error: %v", err)
+				}
+				checkImage(t, got, img)
+			})
+		}
+	}
+}
+
+func TestCompressionShrinksSmoothImages(t *testing.T) {
+	smooth := func(c Compression) int {
+		img := NewImage(128, 64, "Y")
+		img.Compression = c
+		for i := range img.Channels[0].Data {
+			img.Channels[0].Data[i] = float16.FromFloat32(float32(i%128) / 128)
+		}
+		return len(encode(t, img))
+	}
+	none := smooth(NoCompression)
+	for _, c := range []Compression{RLECompression, ZIPSCompression, ZIPCompression} {
+		if n := smooth(c); n >= none/2 {
+			t.Errorf("%v file is %d bytes, uncompressed %d", c, n, none)
+		}
+	}
+}
+
+func TestFileLayout(t *testing.T) {
+	img := NewImage(2, 1, "Y")
+	img.Compression = NoCompression
+	img.Channels[0].Data[0] = float16.FromFloat32(1)
+	img.Channels[0].Data[1] = float16.FromFloat32(-2)
+	data := encode(t, img)
+
+	if !bytes.HasPrefix(data, []byte{0x76, 0x2f, 0x31, 0x01, 2, 0, 0, 0}) {
+		t.Errorf("file prefix = % x", data[:8])
+	}
+	if !bytes.Contains(data, []byte("channels\x00chlist\x00\x13\x00\x00\x00Y\x00\x01\x00\x00\x00\x00\x00\x00\x00\x01\x00\x00\x00\x01\x00\x00\x00\x00")) {
+		t.Error("chlist attribute not found")
+	}
+	// The single chunk: y = 0, 4 bytes of little-endian halves
+	if !bytes.HasSuffix(data, []byte{0, 0, 0, 0, 4, 0, 0, 0, 0x00, 0x3c, 0x00, 0xc0}) {
+		t.Errorf("chunk = % x", data[len(data)-12:])
+	}
+}
+
+func TestHeaderAttributes(t *testing.T) {
+	img := testImage(4, 3, ZIPCompression)
+	img.DataWindow = image.Rect(-2, 10, 2, 13)
+	img.DisplayWindow = image.Rect(0, 0, 1920, 1080)
+	img.PixelAspectRatio = 1.5
+	img.ScreenWindowCenter = [2]float32{0.25, -0.5}
+	img.ScreenWindowWidth = 2
+	img.Channels[3].Linear = true
+	img.Attributes = []Attribute{
+		{Name: "owner", Type: "string", Value: "render farm"},
+		{Name: "frame", Type: "int", Value: int32(-12)},
+		{Name: "exposure", Type: "float", Value: float32(0.5)},
+		{Name: "time", Type: "double", Value: 1.25},
+		{Name: "origin", Type: "v2i", Value: [2]int32{3, -4}},
+		{Name: "whiteLuminance", Type: "v3f", Value: [3]float32{1, 2, 3}},
+		{Name: "chromaticities", Type: "chromaticities", Value: [8]float32{0.64, 0.33, 0.3, 0.6, 0.15, 0.06, 0.3127, 0.329}},
+		{Name: "worldToCamera", Type: "m44f", Value: [16]float32{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}},
+		{Name: "crop", Type: "box2i", Value: image.Rect(1, 2, 3, 4)},
+		{Name: "cropf", Type: "box2f", Value: [4]float32{0, 0, 0.5, 0.5}},
+		{Name: "capDate", Type: "timecode", Value: []byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		{Name: strings.Repeat("x", 40), Type: "string", Value: "long name"},
+	}
+
+	data := encode(t, img)
+	if v := binary.LittleEndian.Uint32(data[4:]); v&flagLongNames == 0 {
+		t.Errorf("version = %#x, want long names flag", v)
+	}
+	got, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	checkImage(t, got, img)
+	if got.PixelAspectRatio != 1.5 || got.ScreenWindowCenter != img.ScreenWindowCenter || got.ScreenWindowWidth != 2 {
+		t.Errorf("header = %+v", got.Header)
+	}
+	if !reflect.DeepEqual(got.Attributes, img.Attributes) {
+		t.Errorf("Attributes = %+v, want %+v", got.Attributes, img.Attributes)
+	}
+	if s := got.At("G", -2, 12); s != img.Channels[1].Data[8] {
+		t.Errorf("At(G, -2, 12) = %v, want %v", s, img.Channels[1].Data[8])
+	}
+	if s := got.At("G", 5, 12); s != 0 {
+		t.Errorf("At outside data window = %v, want 0", s)
+	}
+}
+
+func TestFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frame.exr")
+	img := testImage(16, 16, RLECompression)
+	if err := WriteFile(path, img); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() unexpected error: %v", err)
+	}
+	checkImage(t, got, img)
+}
+
+// buildFile assembles a file by hand from a channel list, a compression
+// method and per-line raw data
+func buildFile(chlist []byte, c Compression, width int, lines [][]byte) []byte {
+	var hdr []byte
+	hdr = appendAttribute(hdr, "channels", "chlist", chlist)
+	hdr = appendAttribute(hdr, "compression", "compression", []byte{byte(c)})
+	hdr = appendAttribute(hdr, "dataWindow", "box2i", appendBox2i(nil, image.Rect(0, 0, width, len(lines))))
+	hdr = append(hdr, 0)
+
+	out := binary.LittleEndian.AppendUint32(nil, magic)
+	out = binary.LittleEndian.AppendUint32(out, formatVersion)
+	out = append(out, hdr...)
+
+	// Store chunks in decreasing line order to exercise the offset table
+	table := make([]byte, 8*len(lines))
+	offset := len(out) + len(table)
+	var chunks []byte
+	for y := len(lines) - 1; y >= 0; y-- {
+		binary.LittleEndian.PutUint64(table[8*y:], uint64(offset+len(chunks)))
+		chunks = binary.LittleEndian.AppendUint32(chunks, uint32(y))
+		chunks = binary.LittleEndian.AppendUint32(chunks, uint32(len(lines[y])))
+		chunks = append(chunks, lines[y]...)
+	}
+	return append(append(out, table...), chunks...)
+}
+
+func chlistEntry(name string, pixelType, xs int) []byte {
+	b := append([]byte(name), 0)
+	b = binary.LittleEndian.AppendUint32(b, uint32(pixelType))
+	b = append(b, 0, 0, 0, 0)
+	b = binary.LittleEndian.AppendUint32(b, uint32(xs))
+	return binary.LittleEndian.AppendUint32(b, 1)
+}
+
+func TestReadFloatChannel(t *testing.T) {
+	chlist := append(chlistEntry("Z", pixelFloat, 1), 0)
+	var line []byte
+	for _, f := range []float32{0.1, 70000, -3} {
+		line = binary.LittleEndian.AppendUint32(line, math.Float32bits(f))
+	}
+	img, err := Parse(buildFile(chlist, NoCompression, 3, [][]byte{line, line}))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	want := []float16.Float16{float16.FromFloat32(0.1), float16.PositiveInfinity, float16.FromFloat32(-3)}
+	want = append(want, want...)
+	if !reflect.DeepEqual(img.Channel("Z").Data, want) {
+		t.Errorf("Z = %v, want %v", img.Channel("Z").Data, want)
+	}
+	if img.DisplayWindow != img.DataWindow || img.PixelAspectRatio != 1 {
+		t.Errorf("defaults not applied: %+v", img.Header)
+	}
+}
+
+func TestRLE(t *testing.T) {
+	tests := []struct {
+		in, out []byte
+	}{
+		{[]byte{5, 5, 5, 5}, []byte{3, 5}},
+		{[]byte{1, 2, 3}, []byte{0xFD, 1, 2, 3}},
+		{[]byte{1, 2, 2, 2, 2}, []byte{0xFF, 1, 3, 2}},
+		{[]byte{7, 7, 1}, []byte{0xFD, 7, 7, 1}},
+	}
+	for _, tt := range tests {
+		got := rleCompress(tt.in)
+		if !bytes.Equal(got, tt.out) {
+			t.Errorf("rleCompress(% x) = % x, want % x", tt.in, got, tt.out)
+		}
+		dst := make([]byte, len(tt.in))
+		if err := rleUncompress(dst, got); err != nil || !bytes.Equal(dst, tt.in) {
+			t.Errorf("rleUncompress(% x) = % x, %v", got, dst, err)
+		}
+	}
+
+	long := bytes.Repeat([]byte{9}, 300)
+	long = append(long, bytes.Repeat([]byte{1, 2}, 150)...)
+	dst := make([]byte, len(long))
+	if err := rleUncompress(dst, rleCompress(long)); err != nil || !bytes.Equal(dst, long) {
+		t.Errorf("long RLE round trip failed: %v", err)
+	}
+	if err := rleUncompress(make([]byte, 2), []byte{5, 1}); !errors.Is(err, ErrFormat) {
+		t.Errorf("rleUncompress overrun error = %v, want ErrFormat", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	good := encode(t, testImage(5, 20, ZIPCompression))
+	half := append(chlistEntry("Y", pixelHalf, 1), 0)
+	line := []byte{0, 0x3c}
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, ErrFormat},
+		{"bad magic", append([]byte{1, 2, 3, 4}, good[4:]...), ErrFormat},
+		{"tiled", append(append([]byte(nil), good[:4]...), append([]byte{2, 2, 0, 0}, good[8:]...)...), ErrUnsupported},
+		{"truncated header", good[:40], ErrFormat},
+		{"truncated chunk", good[:len(good)-10], ErrFormat},
+		{"uint channel", buildFile(append(chlistEntry("Y", pixelUint, 1), 0), NoCompression, 1, [][]byte{{0, 0, 0, 0}}), ErrUnsupported},
+		{"subsampled", buildFile(append(chlistEntry("Y", pixelHalf, 2), 0), NoCompression, 1, [][]byte{line}), ErrUnsupported},
+		{"unsorted channels", buildFile(append(append(chlistEntry("Y", pixelHalf, 1), chlistEntry("A", pixelHalf, 1)...), 0), NoCompression, 1, [][]byte{{0, 0, 0, 0}}), ErrFormat},
+		{"piz", buildFile(half, PIZCompression, 1, [][]byte{line}), ErrUnsupported},
+		{"short chunk", buildFile(half, NoCompression, 1, [][]byte{{0}}), ErrFormat},
+		{"bad zlib", buildFile(half, ZIPSCompression, 2, [][]byte{{1, 2, 3}}), ErrFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Corrupting any byte must never panic
+	for i := range good {
+		data := append([]byte(nil), good...)
+		data[i] ^= 0x5A
+		Parse(data)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Image)
+	}{
+		{"no channels", func(img *Image) { img.Channels = nil }},
+		{"duplicate channel", func(img *Image) { img.Channels[1].Name = "R" }},
+		{"short channel", func(img *Image) { img.Channels[0].Data = img.Channels[0].Data[1:] }},
+		{"empty window", func(img *Image) { img.DataWindow = image.Rectangle{} }},
+		{"piz", func(img *Image) { img.Compression = PIZCompression }},
+		{"standard attribute", func(img *Image) {
+			img.Attributes = []Attribute{{Name: "dataWindow", Type: "box2i", Value: image.Rect(0, 0, 1, 1)}}
+		}},
+		{"value type", func(img *Image) { img.Attributes = []Attribute{{Name: "n", Type: "int", Value: 3}} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := testImage(3, 3, ZIPCompression)
+			tt.mutate(img)
+			if err := Encode(&bytes.Buffer{}, img); err == nil {
+				t.Error("Encode() expected error, got nil")
+			}
+		})
+	}
+}
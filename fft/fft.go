@@ -0,0 +1,325 @@
+// Package fft computes discrete Fourier transforms of half-precision data.
+//
+// Inputs and outputs are stored as float16.Complex32 or float16.Float16,
+// while the transform itself runs on a wider working copy. Fast computes
+// the butterflies in float32 with float32 twiddle factors; Accurate uses
+// float64 for both. Each output is rounded to half precision once.
+//
+// Any length n ≥ 1 is supported. n is factored into primes and transformed
+// with a mixed-radix Cooley-Tukey algorithm, using a dedicated radix-2
+// butterfly and a direct butterfly for odd primes; a prime factor p costs
+// O(n·p), so lengths with small factors are fastest.
+//
+// # Error bound
+//
+// Let X be the exact transform of the half-precision input (including the
+// normalization factor), r the largest prime factor of n, and u = 2^-24 for
+// Fast or u = 2^-53 for Accurate. When no output overflows Float16, the
+// computed X̂ satisfies
+//
+//	‖X̂ − X‖₂ ≤ (2^-11 + 4·r·⌈log₂ n⌉·u)·‖X‖₂ + 2^-25·√(2n)
+//
+// The 2^-11 term is the final rounding to half precision and the last term
+// covers outputs that round into the subnormal range; the middle term is
+// the error of the working precision, a variant of the classical bound
+// (Higham, Accuracy and Stability of Numerical Algorithms, §24.1). For real
+// transforms the bound holds with ⌈log₂ n⌉ + 2 in place of ⌈log₂ n⌉. In
+// Fast mode the working-precision term stays below the rounding term until
+// r·⌈log₂ n⌉ approaches 2^11, so Accurate only pays off for lengths with
+// large prime factors.
+//
+// The unnormalized forward transform grows values by up to a factor of n
+// and can overflow Float16 (maximum 65504); NormOrtho or NormForward keep
+// the spectrum in range.
+//
+// See: https://en.wikipedia.org/wiki/Cooley%E2%80%93Tukey_FFT_algorithm
+package fft
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/zerfoo/float16"
+)
+
+// ErrLength is returned for invalid transform lengths and for slices whose
+// length does not match the plan
+var ErrLength = errors.New("fft: invalid length")
+
+// Precision selects the working precision of a transform
+type Precision int
+
+const (
+	// Fast computes the transform in float32, with half the working memory
+	// of Accurate
+	Fast Precision = iota
+	// Accurate computes the transform in float64
+	Accurate
+)
+
+// Norm selects which direction of the transform is scaled, with the same
+// meaning as NumPy's norm argument
+type Norm int
+
+const (
+	// NormBackward leaves the forward transform unscaled and scales the
+	// inverse by 1/n
+	NormBackward Norm = iota
+	// NormOrtho scales both directions by 1/√n
+	NormOrtho
+	// NormForward scales the forward transform by 1/n and leaves the inverse
+	// unscaled
+	NormForward
+)
+
+// Options configures a plan. The zero value selects Fast and NormBackward.
+type Options struct {
+	Precision Precision
+	Norm      Norm
+}
+
+// scales returns the forward and inverse scale factors for length n
+func (o Options) scales(n int) (fwd, inv float64) {
+	switch o.Norm {
+	case NormOrtho:
+		s := 1 / math.Sqrt(float64(n))
+		return s, s
+	case NormForward:
+		return 1 / float64(n), 1
+	}
+	return 1, 1 / float64(n)
+}
+
+// twiddles returns e^(-2πi·k/n) for k in [0, n)
+func twiddles[C complex64 | complex128](n int) []C {
+	w := make([]C, n)
+	for k := range n {
+		s, c := math.Sincos(-2 * math.Pi * float64(k) / float64(n))
+		w[k] = C(complex(c, s))
+	}
+	return w
+}
+
+// Plan is a reusable complex transform of a fixed length. A Plan keeps its
+// working buffers between calls, so it must not be used by several
+// goroutines at once.
+type Plan struct {
+	n       int
+	opts    Options
+	factors []int
+	tw32    []complex64
+	tw64    []complex128
+
+	buf   []complex128
+	buf32 []complex64
+}
+
+// NewPlan returns a plan for complex transforms of length n. A nil opts
+// selects the defaults.
+func NewPlan(n int, opts *Options) (*Plan, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrLength, n)
+	}
+	p := &Plan{n: n, factors: factorize(n)}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.Precision == Accurate {
+		p.tw64 = twiddles[complex128](n)
+	} else {
+		p.tw32 = twiddles[complex64](n)
+	}
+	return p, nil
+}
+
+// Len returns the transform length
+func (p *Plan) Len() int { return p.n }
+
+// factorize returns the prime factors of n, twos first and then ascending
+func factorize(n int) []int {
+	var f []int
+	for d := 2; n > 1; {
+		if d*d > n {
+			f = append(f, n)
+			break
+		}
+		if n%d == 0 {
+			f = append(f, d)
+			n /= d
+			continue
+		}
+		if d == 2 {
+			d = 3
+		} else {
+			d += 2
+		}
+	}
+	return f
+}
+
+// Forward stores the discrete Fourier transform of src in dst. Both slices
+// must have length Len; they may be the same slice.
+func (p *Plan) Forward(dst, src []float16.Complex32) error {
+	return p.transform(dst, src, false)
+}
+
+// Inverse stores the inverse discrete Fourier transform of src in dst. Both
+// slices must have length Len; they may be the same slice.
+func (p *Plan) Inverse(dst, src []float16.Complex32) error {
+	return p.transform(dst, src, true)
+}
+
+func (p *Plan) transform(dst, src []float16.Complex32, inverse bool) error {
+	if len(dst) != p.n || len(src) != p.n {
+		return fmt.Errorf("%w: got %d and %d values for a length %d plan", ErrLength, len(dst), len(src), p.n)
+	}
+	fwd, inv := p.opts.scales(p.n)
+	scale := fwd
+	if inverse {
+		scale = inv
+	}
+
+	if p.opts.Precision == Fast {
+		// Stay in complex64 rather than staging through exec
+		in, out := p.buffers32()
+		for i, v := range src {
+			in[i] = v.Complex64()
+		}
+		run(out, in, p.tw32, p.factors, inverse)
+		s := float32(scale)
+		for i, v := range out {
+			dst[i] = float16.Complex32FromComplex64(complex(real(v)*s, imag(v)*s))
+		}
+		return nil
+	}
+
+	if p.buf == nil {
+		p.buf = make([]complex128, 2*p.n)
+	}
+	in, out := p.buf[:p.n], p.buf[p.n:]
+	for i, v := range src {
+		in[i] = v.Complex128()
+	}
+	run(out, in, p.tw64, p.factors, inverse)
+	for i, v := range out {
+		dst[i] = float16.Complex32FromComplex128(complex(real(v)*scale, imag(v)*scale))
+	}
+	return nil
+}
+
+func (p *Plan) buffers32() (in, out []complex64) {
+	if p.buf32 == nil {
+		p.buf32 = make([]complex64, 2*p.n)
+	}
+	return p.buf32[:p.n], p.buf32[p.n:]
+}
+
+// exec computes the unnormalized transform of in into out in the working
+// precision of the plan. Values are passed as complex128 either way; in Fast
+// mode they are exactly representable in complex64.
+func (p *Plan) exec(out, in []complex128, inverse bool) {
+	if p.opts.Precision == Accurate {
+		run(out, in, p.tw64, p.factors, inverse)
+		return
+	}
+	in32, out32 := p.buffers32()
+	for i, v := range in {
+		in32[i] = complex64(v)
+	}
+	run(out32, in32, p.tw32, p.factors, inverse)
+	for i, v := range out32 {
+		out[i] = complex128(v)
+	}
+}
+
+// run transforms in into out with the twiddles w of the working precision
+func run[C complex64 | complex128](out, in []C, w []C, factors []int, inverse bool) {
+	k := kernel[C]{n: len(in), inverse: inverse, w: w}
+	if r := slices.Max(append([]int{1}, factors...)); r > 2 {
+		k.t = make([]C, r)
+	}
+	k.rec(out, in, len(in), 1, factors)
+}
+
+// kernel is the mixed-radix decimation-in-time transform for one working
+// precision
+type kernel[C complex64 | complex128] struct {
+	n       int
+	inverse bool
+	w       []C // e^(-2πi·i/n) for i in [0, n)
+	t       []C // butterfly scratch
+}
+
+// twiddle returns e^(∓2πi·i/n), the sign depending on the direction
+func (k *kernel[C]) twiddle(i int) C {
+	i %= k.n
+	if k.inverse && i != 0 {
+		i = k.n - i
+	}
+	return k.w[i]
+}
+
+// rec transforms the size elements in[0], in[stride], ... into out[:size]
+func (k *kernel[C]) rec(out, in []C, size, stride int, factors []int) {
+	if size == 1 {
+		out[0] = in[0]
+		return
+	}
+	r := factors[0]
+	m := size / r
+	for q := range r {
+		k.rec(out[q*m:(q+1)*m], in[q*stride:], m, stride*r, factors[1:])
+	}
+
+	step := k.n / size // w_size = w_n^step
+	if r == 2 {
+		// j·step < n/2, so the table can be indexed directly
+		for j := range m {
+			i := j * step
+			if k.inverse && i != 0 {
+				i = k.n - i
+			}
+			a, b := out[j], out[m+j]*k.w[i]
+			out[j], out[m+j] = a+b, a-b
+		}
+		return
+	}
+
+	t := k.t[:r]
+	rootStep := k.n / r // w_r = w_n^rootStep
+	for j := range m {
+		for q := range r {
+			t[q] = out[q*m+j] * k.twiddle(q*j*step)
+		}
+		for s := range r {
+			sum := t[0]
+			for q := 1; q < r; q++ {
+				sum += t[q] * k.twiddle(q*s%r*rootStep)
+			}
+			out[s*m+j] = sum
+		}
+	}
+}
+
+// FFT returns the discrete Fourier transform of x using the default options
+func FFT(x []float16.Complex32) []float16.Complex32 {
+	return transformOnce(x, false)
+}
+
+// IFFT returns the inverse discrete Fourier transform of x, scaled by 1/n,
+// using the default options
+func IFFT(x []float16.Complex32) []float16.Complex32 {
+	return transformOnce(x, true)
+}
+
+func transformOnce(x []float16.Complex32, inverse bool) []float16.Complex32 {
+	dst := make([]float16.Complex32, len(x))
+	if len(x) == 0 {
+		return dst
+	}
+	p, _ := NewPlan(len(x), nil)
+	p.transform(dst, x, inverse)
+	return dst
+}
@@ -0,0 +1,300 @@
+package fft
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+// dft is the float64 reference transform of x, scaled by scale
+func dft(x []complex128, inverse bool, scale float64) []complex128 {
+	n := len(x)
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	out := make([]complex128, n)
+	for k := range n {
+		var sum complex128
+		for j, v := range x {
+			s, c := math.Sincos(sign * 2 * math.Pi * float64(j*k%n) / float64(n))
+			sum += v * complex(c, s)
+		}
+		out[k] = sum * complex(scale, 0)
+	}
+	return out
+}
+
+func randomComplex(rng *rand.Rand, n int) []float16.Complex32 {
+	x := make([]float16.Complex32, n)
+	for i := range x {
+		x[i] = float16.Complex32FromComplex128(complex(2*rng.Float64()-1, 2*rng.Float64()-1))
+	}
+	return x
+}
+
+func widen(x []float16.Complex32) []complex128 {
+	out := make([]complex128, len(x))
+	for i, v := range x {
+		out[i] = v.Complex128()
+	}
+	return out
+}
+
+// errorBound is the documented bound on ‖X̂ − X‖₂ for the exact result want
+func errorBound(n int, precision Precision, want []complex128, extraStages int) float64 {
+	u := math.Ldexp(1, -24)
+	if precision == Accurate {
+		u = math.Ldexp(1, -53)
+	}
+	r := slices.Max(append([]int{1}, factorize(n)...))
+	stages := math.Ceil(math.Log2(float64(n))) + float64(extraStages)
+	return (math.Ldexp(1, -11)+4*float64(r)*stages*u)*norm(want) + math.Ldexp(1, -25)*math.Sqrt(2*float64(n))
+}
+
+func norm(x []complex128) float64 {
+	var s float64
+	for _, v := range x {
+		s += real(v)*real(v) + imag(v)*imag(v)
+	}
+	return math.Sqrt(s)
+}
+
+func errNorm(got []float16.Complex32, want []complex128) float64 {
+	d := make([]complex128, len(want))
+	for i := range want {
+		d[i] = got[i].Complex128() - want[i]
+	}
+	return norm(d)
+}
+
+var testLengths = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 12, 15, 16, 30, 49, 64, 97, 100, 128, 210, 256, 1000, 1024}
+
+func TestFactorize(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{1, nil},
+		{2, []int{2}},
+		{12, []int{2, 2, 3}},
+		{97, []int{97}},
+		{210, []int{2, 3, 5, 7}},
+		{1024, []int{2, 2, 2, 2, 2, 2, 2, 2, 2, 2}},
+		{3 * 3 * 5 * 11, []int{3, 3, 5, 11}},
+	}
+	for _, tt := range tests {
+		if got := factorize(tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("factorize(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestPlanAccuracy(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, precision := range []Precision{Fast, Accurate} {
+		for _, nrm := range []Norm{NormBackward, NormOrtho, NormForward} {
+			opts := &Options{Precision: precision, Norm: nrm}
+			for _, n := range testLengths {
+				p, err := NewPlan(n, opts)
+				if err != nil {
+					t.Fatalf("NewPlan(%d) error: %v", n, err)
+				}
+				fwd, inv := opts.scales(n)
+				x := randomComplex(rng, n)
+
+				got := make([]float16.Complex32, n)
+				if err := p.Forward(got, x); err != nil {
+					t.Fatalf("Forward() error: %v", err)
+				}
+				want := dft(widen(x), false, fwd)
+				if e, b := errNorm(got, want), errorBound(n, precision, want, 0); e > b {
+					t.Errorf("precision %d norm %d n %d: forward error %g exceeds bound %g", precision, nrm, n, e, b)
+				}
+
+				if err := p.Inverse(got, x); err != nil {
+					t.Fatalf("Inverse() error: %v", err)
+				}
+				want = dft(widen(x), true, inv)
+				if e, b := errNorm(got, want), errorBound(n, precision, want, 0); e > b {
+					t.Errorf("precision %d norm %d n %d: inverse error %g exceeds bound %g", precision, nrm, n, e, b)
+				}
+			}
+		}
+	}
+}
+
+func TestPlanRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, n := range []int{8, 12, 97, 256} {
+		p, _ := NewPlan(n, &Options{Precision: Accurate, Norm: NormOrtho})
+		x := randomComplex(rng, n)
+		y := slices.Clone(x)
+		// Reuse the plan and transform in place
+		p.Forward(y, y)
+		p.Inverse(y, y)
+		for i := range x {
+			if d := cmplx.Abs(y[i].Complex128() - x[i].Complex128()); d > 2*math.Ldexp(1, -10) {
+				t.Errorf("n %d: round trip [%d] = %v, want %v", n, i, y[i], x[i])
+			}
+		}
+	}
+}
+
+func TestKnownTransforms(t *testing.T) {
+	one := float16.Complex32FromComplex128(1)
+
+	// An impulse transforms to all ones
+	x := make([]float16.Complex32, 6)
+	x[0] = one
+	for i, v := range FFT(x) {
+		if v != one {
+			t.Errorf("FFT(impulse)[%d] = %v, want 1", i, v)
+		}
+	}
+
+	// A constant transforms to n at the zero frequency
+	for i := range x {
+		x[i] = one
+	}
+	X := FFT(x)
+	if X[0] != float16.Complex32FromComplex128(6) {
+		t.Errorf("FFT(ones)[0] = %v, want 6", X[0])
+	}
+	for i, v := range X[1:] {
+		if cmplx.Abs(v.Complex128()) > 1e-3 {
+			t.Errorf("FFT(ones)[%d] = %v, want 0", i+1, v)
+		}
+	}
+	for i, v := range IFFT(X) {
+		if v != one {
+			t.Errorf("IFFT(FFT(ones))[%d] = %v, want 1", i, v)
+		}
+	}
+
+	// A complex exponential at frequency 1 has a single non-zero bin
+	for i := range x {
+		s, c := math.Sincos(2 * math.Pi * float64(i) / 6)
+		x[i] = float16.Complex32FromComplex128(complex(c, s))
+	}
+	X = FFT(x)
+	if d := cmplx.Abs(X[1].Complex128() - 6); d > 1e-2 {
+		t.Errorf("FFT(exp)[1] = %v, want 6", X[1])
+	}
+
+	if len(FFT(nil)) != 0 || len(RFFT(nil)) != 0 {
+		t.Error("transform of an empty slice is not empty")
+	}
+}
+
+func TestRealPlan(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for _, precision := range []Precision{Fast, Accurate} {
+		for _, nrm := range []Norm{NormBackward, NormOrtho, NormForward} {
+			opts := &Options{Precision: precision, Norm: nrm}
+			for _, n := range testLengths {
+				p, err := NewRealPlan(n, opts)
+				if err != nil {
+					t.Fatalf("NewRealPlan(%d) error: %v", n, err)
+				}
+				fwd, _ := opts.scales(n)
+				x := make([]float16.Float16, n)
+				xc := make([]complex128, n)
+				for i := range x {
+					x[i] = float16.FromFloat64(2*rng.Float64() - 1)
+					xc[i] = complex(x[i].ToFloat64(), 0)
+				}
+
+				bins := make([]float16.Complex32, p.Bins())
+				if err := p.Forward(bins, x); err != nil {
+					t.Fatalf("Forward() error: %v", err)
+				}
+				want := dft(xc, false, fwd)[:p.Bins()]
+				if e, b := errNorm(bins, want), errorBound(n, precision, want, 2); e > b {
+					t.Errorf("precision %d norm %d n %d: real forward error %g exceeds bound %g", precision, nrm, n, e, b)
+				}
+				if bins[0].Im != 0 || (n%2 == 0 && bins[n/2].Im.Abs() != 0) {
+					t.Errorf("n %d: zero or Nyquist bin is not real: %v, %v", n, bins[0], bins[n/2])
+				}
+
+				// Invert the exact spectrum so the error is that of the inverse
+				for i := range bins {
+					bins[i] = float16.Complex32FromComplex128(want[i])
+				}
+				got := make([]float16.Float16, n)
+				if err := p.Inverse(got, bins); err != nil {
+					t.Fatalf("Inverse() error: %v", err)
+				}
+				// The bins were rounded to half precision before the inverse, so
+				// allow that rounding error on top of the bound
+				var d []complex128
+				for i := range x {
+					d = append(d, complex(got[i].ToFloat64()-x[i].ToFloat64(), 0))
+				}
+				if e, b := norm(d), 2*errorBound(n, precision, xc, 2); e > b {
+					t.Errorf("precision %d norm %d n %d: real inverse error %g exceeds %g", precision, nrm, n, e, b)
+				}
+			}
+		}
+	}
+}
+
+func TestRFFTConvenience(t *testing.T) {
+	x := []float16.Float16{float16.FromFloat32(1), float16.FromFloat32(2), float16.FromFloat32(3), float16.FromFloat32(4)}
+	X := RFFT(x)
+	want := []complex128{10, -2 + 2i, -2}
+	for i := range want {
+		if X[i].Complex128() != want[i] {
+			t.Errorf("RFFT()[%d] = %v, want %v", i, X[i], want[i])
+		}
+	}
+	y, err := IRFFT(X, 4)
+	if err != nil {
+		t.Fatalf("IRFFT() error: %v", err)
+	}
+	if !slices.Equal(x, y) {
+		t.Errorf("IRFFT(RFFT(x)) = %v, want %v", y, x)
+	}
+	// An odd length from the same number of bins
+	if y, err := IRFFT(X[:2], 3); err != nil || len(y) != 3 {
+		t.Errorf("IRFFT(bins, 3) = %v, %v", y, err)
+	}
+}
+
+func TestLengthErrors(t *testing.T) {
+	if _, err := NewPlan(0, nil); !errors.Is(err, ErrLength) {
+		t.Errorf("NewPlan(0) error = %v, want ErrLength", err)
+	}
+	if _, err := NewRealPlan(-1, nil); !errors.Is(err, ErrLength) {
+		t.Errorf("NewRealPlan(-1) error = %v, want ErrLength", err)
+	}
+	p, _ := NewPlan(4, nil)
+	if err := p.Forward(make([]float16.Complex32, 4), make([]float16.Complex32, 3)); !errors.Is(err, ErrLength) {
+		t.Errorf("Forward() with short src error = %v, want ErrLength", err)
+	}
+	rp, _ := NewRealPlan(4, nil)
+	if err := rp.Forward(make([]float16.Complex32, 4), make([]float16.Float16, 4)); !errors.Is(err, ErrLength) {
+		t.Errorf("RealPlan.Forward() with 4 bins error = %v, want ErrLength", err)
+	}
+	if _, err := IRFFT(make([]float16.Complex32, 2), 4); !errors.Is(err, ErrLength) {
+		t.Errorf("IRFFT() with 2 bins for n=4 error = %v, want ErrLength", err)
+	}
+}
+
+func BenchmarkPlanForward(b *testing.B) {
+	for _, precision := range []Precision{Fast, Accurate} {
+		p, _ := NewPlan(1024, &Options{Precision: precision})
+		x := randomComplex(rand.New(rand.NewSource(4)), 1024)
+		dst := make([]float16.Complex32, len(x))
+		b.Run([]string{"Fast", "Accurate"}[precision], func(b *testing.B) {
+			for range b.N {
+				p.Forward(dst, x)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package fft
+
+import (
+	"fmt"
+	"math/cmplx"
+
+	"github.com/zerfoo/float16"
+)
+
+// RealPlan is a reusable transform of real input of a fixed length n,
+// producing the n/2+1 non-negative frequency bins; the remaining bins are
+// their complex conjugates. Even lengths are computed with a complex
+// transform of length n/2. Like Plan, a RealPlan must not be used by
+// several goroutines at once.
+type RealPlan struct {
+	n    int
+	opts Options
+	half *Plan // length n/2 plan for even n, length n plan for odd n
+
+	// e^(-2πi·k/n) for k in [0, n/2], for splitting the packed transform
+	tw  []complex128
+	buf []complex128
+}
+
+// NewRealPlan returns a plan for real transforms of length n. A nil opts
+// selects the defaults.
+func NewRealPlan(n int, opts *Options) (*RealPlan, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrLength, n)
+	}
+	p := &RealPlan{n: n}
+	if opts != nil {
+		p.opts = *opts
+	}
+	m := n
+	if n%2 == 0 {
+		m = n / 2
+		p.tw = twiddles[complex128](n)[:m+1]
+	}
+	// The inner plan is unnormalized; scaling is applied once at the end
+	p.half, _ = NewPlan(m, &Options{Precision: p.opts.Precision})
+	return p, nil
+}
+
+// Len returns the length of the real signal
+func (p *RealPlan) Len() int { return p.n }
+
+// Bins returns the number of frequency bins, n/2+1
+func (p *RealPlan) Bins() int { return p.n/2 + 1 }
+
+// buffers returns the working buffers, with room for m+1 values each
+func (p *RealPlan) buffers() (in, out []complex128) {
+	m := p.half.n
+	if p.buf == nil {
+		p.buf = make([]complex128, 2*(m+1))
+	}
+	return p.buf[:m+1], p.buf[m+1:]
+}
+
+// Forward stores the first n/2+1 bins of the discrete Fourier transform of
+// the real signal src in dst
+func (p *RealPlan) Forward(dst []float16.Complex32, src []float16.Float16) error {
+	if len(dst) != p.Bins() || len(src) != p.n {
+		return fmt.Errorf("%w: got %d bins and %d values for a length %d real plan", ErrLength, len(dst), len(src), p.n)
+	}
+	scale, _ := p.opts.scales(p.n)
+	in, out := p.buffers()
+	m := p.half.n
+
+	if p.n%2 == 1 {
+		for i, v := range src {
+			in[i] = complex(v.ToFloat64(), 0)
+		}
+		p.half.exec(out[:m], in[:m], false)
+	} else {
+		// Pack the samples as z[j] = x[2j] + i·x[2j+1] and split the length m
+		// transform into the bins of the even and odd samples,
+		// E[k] = (Z[k] + conj(Z[m-k]))/2 and O[k] = (Z[k] - conj(Z[m-k]))/2i,
+		// so that X[k] = E[k] + w^k·O[k]
+		for j := range m {
+			in[j] = complex(src[2*j].ToFloat64(), src[2*j+1].ToFloat64())
+		}
+		p.half.exec(out[:m], in[:m], false)
+		out[m] = out[0]
+		for k := 0; k <= m; k++ {
+			zk, zmk := out[k], out[m-k]
+			e := (zk + cmplx.Conj(zmk)) / 2
+			o := (zk - cmplx.Conj(zmk)) / 2i
+			in[k] = e + p.tw[k]*o
+		}
+		out = in
+	}
+
+	for i, v := range out[:p.Bins()] {
+		dst[i] = float16.Complex32FromComplex128(complex(real(v)*scale, imag(v)*scale))
+	}
+	return nil
+}
+
+// Inverse stores the real signal whose transform has the bins src in dst.
+// The imaginary parts of the zero frequency bin, and of the Nyquist bin for
+// even n, are ignored.
+func (p *RealPlan) Inverse(dst []float16.Float16, src []float16.Complex32) error {
+	if len(dst) != p.n || len(src) != p.Bins() {
+		return fmt.Errorf("%w: got %d values and %d bins for a length %d real plan", ErrLength, len(dst), len(src), p.n)
+	}
+	_, scale := p.opts.scales(p.n)
+	in, out := p.buffers()
+	m := p.half.n
+
+	x := func(k int) complex128 {
+		v := src[k]
+		// The zero and Nyquist bins of a real signal are real
+		if k == 0 || 2*k == p.n {
+			return complex(v.Re.ToFloat64(), 0)
+		}
+		return v.Complex128()
+	}
+
+	if p.n%2 == 1 {
+		// Rebuild the full Hermitian spectrum
+		for k := range p.n {
+			if k < p.Bins() {
+				in[k] = x(k)
+			} else {
+				in[k] = cmplx.Conj(x(p.n - k))
+			}
+		}
+		p.half.exec(out[:m], in[:m], true)
+		for i := range dst {
+			dst[i] = float16.FromFloat64(real(out[i]) * scale)
+		}
+		return nil
+	}
+
+	// Rebuild Z[k] = E[k] + i·O[k] from E[k] = X[k] + conj(X[m-k]) and
+	// O[k] = (X[k] - conj(X[m-k]))·w^-k; the inverse of Z holds the even
+	// samples in its real parts and the odd samples in its imaginary parts
+	for k := range m {
+		xk, xmk := x(k), cmplx.Conj(x(m-k))
+		e := xk + xmk
+		o := (xk - xmk) * cmplx.Conj(p.tw[k])
+		in[k] = e + 1i*o
+	}
+	p.half.exec(out[:m], in[:m], true)
+	for j, v := range out[:m] {
+		dst[2*j] = float16.FromFloat64(real(v) * scale)
+		dst[2*j+1] = float16.FromFloat64(imag(v) * scale)
+	}
+	return nil
+}
+
+// RFFT returns the n/2+1 non-negative frequency bins of the real signal x
+// using the default options
+func RFFT(x []float16.Float16) []float16.Complex32 {
+	if len(x) == 0 {
+		return nil
+	}
+	p, _ := NewRealPlan(len(x), nil)
+	dst := make([]float16.Complex32, p.Bins())
+	p.Forward(dst, x)
+	return dst
+}
+
+// IRFFT returns the real signal of length n whose non-negative frequency
+// bins are x, scaled by 1/n, using the default options. x must hold n/2+1
+// bins.
+func IRFFT(x []float16.Complex32, n int) ([]float16.Float16, error) {
+	p, err := NewRealPlan(n, nil)
+	if err != nil {
+		return nil, err
+	}
+	dst := make([]float16.Float16, n)
+	if err := p.Inverse(dst, x); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
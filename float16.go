@@ -197,7 +197,7 @@ func Signbit(f Float16) bool {
 // NextAfter returns the next representable Float16 value after f in the direction of g
 func NextAfter(f, g Float16) Float16 {
 	if f.IsNaN() || g.IsNaN() {
-		return QuietNaN
+		return propagateNaN(f, g)
 	}
 
 	if Equal(f, g) {
@@ -245,6 +245,41 @@ func Ldexp(frac Float16, exp int) Float16 {
 	return FromFloat32(float32(result))
 }
 
+// Scalbn returns f × 2^n, rounded once to Float16
+func Scalbn(f Float16, n int) Float16 {
+	if f.IsZero() || f.IsNaN() || f.IsInf(0) {
+		return f
+	}
+
+	return FromFloat64(math.Ldexp(f.ToFloat64(), n))
+}
+
+// Scalb returns f × 2^exp for an integral exp. Scalb(f, ±Inf) is ±Inf or
+// ±0 with the sign of f, except that 0 × 2^+Inf and Inf × 2^-Inf are NaN;
+// a non-integral exp gives NaN.
+func Scalb(f, exp Float16) Float16 {
+	switch {
+	case f.IsNaN():
+		return f
+	case exp.IsNaN():
+		return exp
+	case exp.IsInf(1):
+		if f.IsZero() {
+			return QuietNaN
+		}
+		return PositiveInfinity.CopySign(f)
+	case exp.IsInf(-1):
+		if f.IsInf(0) {
+			return QuietNaN
+		}
+		return PositiveZero.CopySign(f)
+	case Trunc(exp) != exp:
+		return QuietNaN
+	}
+	// |exp| <= 65504 fits an int and saturates Ldexp either way
+	return Scalbn(f, int(exp.ToFloat32()))
+}
+
 // Modf returns integer and fractional floating-point numbers that sum to f
 // Both values have the same sign as f
 func Modf(f Float16) (integer, frac Float16) {
@@ -368,7 +403,8 @@ type SliceStats struct {
 	Length int
 }
 
-// ComputeSliceStats calculates statistics for a Float16 slice
+// ComputeSliceStats calculates statistics for a Float16 slice; use
+// StatsAccumulator for streams, variances and value counts
 func ComputeSliceStats(s []Float16) SliceStats {
 	if len(s) == 0 {
 		return SliceStats{}
@@ -377,7 +413,6 @@ func ComputeSliceStats(s []Float16) SliceStats {
 	stats := SliceStats{
 		Min:    s[0],
 		Max:    s[0],
-		Sum:    PositiveZero,
 		Length: len(s),
 	}
 
@@ -390,12 +425,12 @@ func ComputeSliceStats(s []Float16) SliceStats {
 				stats.Max = v
 			}
 		}
-		stats.Sum = Add(stats.Sum, v)
 	}
 
-	if stats.Length > 0 {
-		stats.Mean = Div(stats.Sum, FromFloat32(float32(stats.Length)))
-	}
+	// Sum in float64 so the mean is not limited by a Float16 accumulator
+	sum := accumulate(len(s), func(i int) float64 { return s[i].ToFloat64() }, SumFloat64Acc)
+	stats.Sum = FromFloat64(sum)
+	stats.Mean = FromFloat64(sum / float64(len(s)))
 
 	return stats
 }
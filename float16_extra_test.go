@@ -216,8 +216,8 @@ func TestFastAdd(t *testing.T) {
 }
 
 func TestFastMul(t *testing.T) {
-	a := FromInt(2)
-	b := FromInt(3)
+	a := FromFloat32(2)
+	b := FromFloat32(3)
 	result := FastMul(a, b)
 	if result != 0x4600 {
 		t.Errorf("FastMul(2, 3) = %v, want 6", result)
@@ -234,8 +234,8 @@ func TestVectorAdd(t *testing.T) {
 }
 
 func TestVectorMul(t *testing.T) {
-	a := []Float16{FromInt(2), FromInt(3)}
-	b := []Float16{FromInt(3), FromInt(4)}
+	a := []Float16{FromFloat32(2), FromFloat32(3)}
+	b := []Float16{FromFloat32(3), FromFloat32(4)}
 	result := VectorMul(a, b)
 	if !Equal(result[0], 0x4600) || !Equal(result[1], 0x4A00) {
 		t.Errorf("VectorMul([2, 3], [3, 4]) = [%v (0x%04X), %v (0x%04X)], want [6 (0x4600), 12 (0x4A00)]", result[0], uint16(result[0]), result[1], uint16(result[1]))
@@ -244,7 +244,7 @@ func TestVectorMul(t *testing.T) {
 
 func TestToSlice16(t *testing.T) {
 	input := []float32{0.0, 1.0, 2.0, -1.0}
-	expected := []Float16{PositiveZero, ToFloat16(1.0), ToFloat16(2.0), ToFloat16(-1.0)}
+	expected := []Float16{PositiveZero, FromFloat32(1.0), FromFloat32(2.0), FromFloat32(-1.0)}
 
 	result := ToSlice16(input)
 	if len(result) != len(expected) {
@@ -259,7 +259,7 @@ func TestToSlice16(t *testing.T) {
 }
 
 func TestToSlice32(t *testing.T) {
-	input := []Float16{PositiveZero, ToFloat16(1.0), ToFloat16(2.0), ToFloat16(-1.0)}
+	input := []Float16{PositiveZero, FromFloat32(1.0), FromFloat32(2.0), FromFloat32(-1.0)}
 	expected := []float32{0.0, 1.0, 2.0, -1.0}
 
 	result := ToSlice32(input)
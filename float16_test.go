@@ -826,6 +826,90 @@ func TestModf(t *testing.T) {
 	}
 }
 
+func TestLogb(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Float16
+		want Float16
+		ilog int
+	}{
+		{"one", FromFloat32(1.0), PositiveZero, 0},
+		{"three", FromFloat32(3.0), FromFloat32(1.0), 1},
+		{"-0.25", FromFloat32(-0.25), FromFloat32(-2.0), -2},
+		{"max", MaxValue, FromFloat32(15.0), 15},
+		{"smallest normal", FromBits(0x0400), FromFloat32(-14.0), -14},
+		{"largest subnormal", FromBits(0x03FF), FromFloat32(-15.0), -15},
+		{"smallest subnormal", SmallestSubnormal, FromFloat32(-24.0), -24},
+		{"zero", PositiveZero, NegativeInfinity, math.MinInt32},
+		{"-zero", NegativeZero, NegativeInfinity, math.MinInt32},
+		{"inf", PositiveInfinity, PositiveInfinity, math.MaxInt32},
+		{"-inf", NegativeInfinity, PositiveInfinity, math.MaxInt32},
+		{"nan", QuietNaN, QuietNaN, math.MaxInt32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Logb(tt.f); got != tt.want {
+				t.Errorf("Logb() = %v, want %v", got, tt.want)
+			}
+			if got := Ilogb(tt.f); got != tt.ilog {
+				t.Errorf("Ilogb() = %v, want %v", got, tt.ilog)
+			}
+		})
+	}
+}
+
+func TestScalbn(t *testing.T) {
+	tests := []struct {
+		name string
+		f    Float16
+		n    int
+		want Float16
+	}{
+		{"1.5, 3", FromFloat32(1.5), 3, FromFloat32(12.0)},
+		{"1, -24", FromFloat32(1.0), -24, SmallestSubnormal},
+		{"1, -25", FromFloat32(1.0), -25, PositiveZero},      // tie to even
+		{"3, -26", FromFloat32(3.0), -26, SmallestSubnormal}, // 0.75 of the smallest subnormal
+		{"-1, 16", FromFloat32(-1.0), 16, NegativeInfinity},
+		{"max, MinInt", MaxValue, math.MinInt, PositiveZero},
+		{"-zero", NegativeZero, 10, NegativeZero},
+		{"inf", PositiveInfinity, -10, PositiveInfinity},
+		{"nan", QuietNaN, 10, QuietNaN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Scalbn(tt.f, tt.n); got != tt.want {
+				t.Errorf("Scalbn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScalb(t *testing.T) {
+	tests := []struct {
+		name   string
+		f, exp Float16
+		want   Float16
+	}{
+		{"1.5, 3", FromFloat32(1.5), FromFloat32(3.0), FromFloat32(12.0)},
+		{"1, -24", FromFloat32(1.0), FromFloat32(-24.0), SmallestSubnormal},
+		{"1, 0.5", FromFloat32(1.0), FromFloat32(0.5), QuietNaN},
+		{"-2, inf", FromFloat32(-2.0), PositiveInfinity, NegativeInfinity},
+		{"-2, -inf", FromFloat32(-2.0), NegativeInfinity, NegativeZero},
+		{"0, inf", PositiveZero, PositiveInfinity, QuietNaN},
+		{"inf, -inf", PositiveInfinity, NegativeInfinity, QuietNaN},
+		{"1, 65504", FromFloat32(1.0), MaxValue, PositiveInfinity},
+		{"nan, 1", QuietNaN, FromFloat32(1.0), QuietNaN},
+		{"1, nan", FromFloat32(1.0), QuietNaN, QuietNaN},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Scalb(tt.f, tt.exp); got != tt.want {
+				t.Errorf("Scalb() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestComputeSliceStats(t *testing.T) {
 	t.Run("empty slice", func(t *testing.T) {
 		stats := ComputeSliceStats([]Float16{})
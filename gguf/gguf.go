@@ -0,0 +1,337 @@
+// Package gguf reads and writes GGUF model container files with access to
+// half-precision tensor data.
+//
+// A GGUF file holds a header, a list of typed metadata key/value pairs, a
+// list of tensor descriptors, and an aligned data section. F16 tensors are
+// exposed as []float16.Float16 views of the data section; F32, F64, BF16,
+// the legacy block-quantized GGML types (Q4_0, Q4_1, Q5_0, Q5_1, Q8_0,
+// Q8_1) and the K-quants (Q2_K, Q3_K, Q4_K, Q5_K, Q6_K, Q8_K) can be
+// decoded to []float16.Float16. The integer types cannot be decoded, and
+// tensors in other quantizations, such as the IQ types, cannot be read.
+//
+// Only little-endian files with format version 2 or 3 are supported.
+//
+// See: https://github.com/ggml-org/ggml/blob/master/docs/gguf.md
+package gguf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/zerfoo/float16"
+	"github.com/zerfoo/float16/internal/mmap"
+)
+
+// magic is "GGUF" read as a little-endian uint32
+const magic = 0x46554747
+
+// DefaultAlignment is the data alignment used when general.alignment is absent
+const DefaultAlignment = 32
+
+// alignmentKey is the metadata key that overrides DefaultAlignment
+const alignmentKey = "general.alignment"
+
+// Errors returned when decoding GGUF data
+var (
+	ErrFormat          = errors.New("gguf: malformed file")
+	ErrVersion         = errors.New("gguf: unsupported version")
+	ErrNotFound        = errors.New("gguf: not found")
+	ErrTypeMismatch    = errors.New("gguf: metadata type mismatch")
+	ErrUnsupportedType = errors.New("gguf: unsupported tensor type")
+)
+
+// TensorInfo describes one tensor in the file.
+// Shape lists dimensions with the fastest-varying dimension first, as GGML does.
+type TensorInfo struct {
+	Name   string
+	Shape  []uint64
+	Type   GGMLType
+	Offset uint64 // relative to the start of the data section
+}
+
+// Elements returns the number of elements in the tensor
+func (ti *TensorInfo) Elements() uint64 {
+	n := uint64(1)
+	for _, d := range ti.Shape {
+		n *= d
+	}
+	return n
+}
+
+// File is a parsed GGUF file backed by a byte slice
+type File struct {
+	Version   uint32
+	Alignment int
+	Tensors   []TensorInfo
+
+	keys    []string
+	values  map[string]Value
+	tensors map[string]int
+	data    []byte
+	close   func() error
+}
+
+// Tensor is a tensor descriptor together with its raw bytes, which alias the file
+type Tensor struct {
+	TensorInfo
+	Data []byte
+}
+
+// Open maps the named file into memory and parses it.
+// Tensor data refers to the mapping and must not be used after Close.
+func Open(name string) (*File, error) {
+	data, release, err := mmap.Map(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(data)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	f.close = release
+	return f, nil
+}
+
+// Close releases the mapping of a File returned by Open.
+// It is a no-op for files returned from Parse.
+func (f *File) Close() error {
+	if f.close == nil {
+		return nil
+	}
+	err := f.close()
+	f.close = nil
+	return err
+}
+
+// Parse decodes a complete GGUF file held in b.
+// The returned File and its tensors alias b.
+func Parse(b []byte) (*File, error) {
+	d := &decoder{b: b}
+	if d.u32() != magic {
+		return nil, fmt.Errorf("%w: bad magic", ErrFormat)
+	}
+	f := &File{
+		Version:   d.u32(),
+		Alignment: DefaultAlignment,
+		values:    make(map[string]Value),
+		tensors:   make(map[string]int),
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if f.Version != 2 && f.Version != 3 {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, f.Version)
+	}
+
+	nTensors := d.u64()
+	nKV := d.u64()
+	if d.err == nil && (nTensors > uint64(len(b)) || nKV > uint64(len(b))) {
+		return nil, fmt.Errorf("%w: implausible counts", ErrFormat)
+	}
+
+	for i := uint64(0); i < nKV && d.err == nil; i++ {
+		key := d.str()
+		v := d.value(ValueType(d.u32()))
+		if d.err != nil {
+			break
+		}
+		if _, dup := f.values[key]; !dup {
+			f.keys = append(f.keys, key)
+		}
+		f.values[key] = v
+	}
+
+	for i := uint64(0); i < nTensors && d.err == nil; i++ {
+		ti := TensorInfo{Name: d.str()}
+		nDims := d.u32()
+		if nDims > 8 {
+			d.fail("tensor %q has %d dimensions", ti.Name, nDims)
+			break
+		}
+		ti.Shape = make([]uint64, nDims)
+		for j := range ti.Shape {
+			ti.Shape[j] = d.u64()
+		}
+		ti.Type = GGMLType(d.u32())
+		ti.Offset = d.u64()
+		f.tensors[ti.Name] = len(f.Tensors)
+		f.Tensors = append(f.Tensors, ti)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if a, err := f.Uint(alignmentKey); err == nil {
+		if a == 0 || a&(a-1) != 0 || a > 1<<20 {
+			return nil, fmt.Errorf("%w: invalid alignment %d", ErrFormat, a)
+		}
+		f.Alignment = int(a)
+	}
+	start := alignUp(d.pos, f.Alignment)
+	if start > len(b) {
+		start = len(b)
+	}
+	f.data = b[start:]
+
+	for i := range f.Tensors {
+		if err := f.validate(&f.Tensors[i]); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// validate checks that a tensor with a known type fits in the data section
+func (f *File) validate(ti *TensorInfo) error {
+	traits, ok := typeTraits[ti.Type]
+	if !ok {
+		// Unknown types are listed but cannot be read
+		return nil
+	}
+	n := uint64(1)
+	for _, d := range ti.Shape {
+		if d != 0 && n > math.MaxInt64/d {
+			return fmt.Errorf("%w: tensor %q has invalid shape %v", ErrFormat, ti.Name, ti.Shape)
+		}
+		n *= d
+	}
+	if n%uint64(traits.blockSize) != 0 {
+		return fmt.Errorf("%w: tensor %q has %d elements, not a multiple of block size %d", ErrFormat, ti.Name, n, traits.blockSize)
+	}
+	size := n / uint64(traits.blockSize) * uint64(traits.typeSize)
+	if ti.Offset > uint64(len(f.data)) || size > uint64(len(f.data))-ti.Offset {
+		return fmt.Errorf("%w: tensor %q data out of range", ErrFormat, ti.Name)
+	}
+	return nil
+}
+
+// Keys returns the metadata keys in file order
+func (f *File) Keys() []string {
+	return append([]string(nil), f.keys...)
+}
+
+// Value returns the raw metadata value for key
+func (f *File) Value(key string) (Value, bool) {
+	v, ok := f.values[key]
+	return v, ok
+}
+
+// Tensor returns the named tensor with its raw data
+func (f *File) Tensor(name string) (*Tensor, error) {
+	i, ok := f.tensors[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: tensor %q", ErrNotFound, name)
+	}
+	ti := f.Tensors[i]
+	traits, ok := typeTraits[ti.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, ti.Type)
+	}
+	size := ti.Elements() / uint64(traits.blockSize) * uint64(traits.typeSize)
+	end := ti.Offset + size
+	return &Tensor{TensorInfo: ti, Data: f.data[ti.Offset:end:end]}, nil
+}
+
+// Float16s returns the data of an F16 tensor as a []float16.Float16 view of
+// the file contents. If the host is big-endian or the data is misaligned the
+// values are decoded into a new slice instead. Other types return
+// ErrUnsupportedType; use ToFloat16s to convert them.
+//
+// When the File came from Open, the view aliases a read-only memory mapping
+// and writing to it crashes the program; copy it before mutating.
+func (t *Tensor) Float16s() ([]float16.Float16, error) {
+	if t.Type != TypeF16 {
+		return nil, fmt.Errorf("%w: %s is not F16", ErrUnsupportedType, t.Type)
+	}
+	if hostLittleEndian() {
+		if view, err := float16.BytesAsFloat16s(t.Data); err == nil {
+			return view, nil
+		}
+	}
+	out := make([]float16.Float16, len(t.Data)/2)
+	float16.DecodeFloat16s(out, t.Data, binary.LittleEndian)
+	return out, nil
+}
+
+// ToFloat16s decodes the tensor into a new []float16.Float16.
+// F32 and BF16 values and dequantized block values are rounded to nearest even.
+func (t *Tensor) ToFloat16s() ([]float16.Float16, error) {
+	out := make([]float16.Float16, t.Elements())
+	if err := dequantize(t.Type, t.Data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// alignUp rounds n up to a multiple of align, which must be a power of two
+func alignUp(n, align int) int {
+	return (n + align - 1) &^ (align - 1)
+}
+
+// hostLittleEndian reports whether the host stores integers little-endian
+func hostLittleEndian() bool {
+	var probe [2]byte
+	binary.NativeEndian.PutUint16(probe[:], 1)
+	return probe[0] == 1
+}
+
+// decoder reads little-endian GGUF primitives, recording the first error
+type decoder struct {
+	b   []byte
+	pos int
+	err error
+}
+
+func (d *decoder) fail(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s at offset %d", ErrFormat, fmt.Sprintf(format, args...), d.pos)
+	}
+}
+
+func (d *decoder) take(n uint64) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.b)-d.pos) {
+		d.fail("unexpected end of file")
+		return nil
+	}
+	p := d.b[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return p
+}
+
+func (d *decoder) u8() uint8 {
+	if p := d.take(1); p != nil {
+		return p[0]
+	}
+	return 0
+}
+
+func (d *decoder) u16() uint16 {
+	if p := d.take(2); p != nil {
+		return binary.LittleEndian.Uint16(p)
+	}
+	return 0
+}
+
+func (d *decoder) u32() uint32 {
+	if p := d.take(4); p != nil {
+		return binary.LittleEndian.Uint32(p)
+	}
+	return 0
+}
+
+func (d *decoder) u64() uint64 {
+	if p := d.take(8); p != nil {
+		return binary.LittleEndian.Uint64(p)
+	}
+	return 0
+}
+
+func (d *decoder) str() string {
+	return string(d.take(d.u64()))
+}
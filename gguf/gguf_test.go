@@ -0,0 +1,520 @@
+package gguf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+// buildFile writes a small model with metadata and a few tensor types
+func buildFile(t *testing.T, align int) []byte {
+	t.Helper()
+	w := NewWriter()
+	w.Alignment = align
+	meta := []struct {
+		key string
+		v   interface{}
+	}{
+		{"general.architecture", "llama"},
+		{"general.quantization_version", uint32(2)},
+		{"llama.context_length", uint64(4096)},
+		{"llama.rope.freq_base", float32(10000)},
+		{"llama.use_parallel_residual", true},
+		{"tokenizer.ggml.tokens", []string{"<s>", "</s>", "hello"}},
+		{"tokenizer.ggml.scores", []float32{0, -1, -2.5}},
+		{"tokenizer.ggml.token_type", []int32{3, 3, 1}},
+		{"test.offset", int8(-3)},
+	}
+	for _, m := range meta {
+		if err := w.SetMetadata(m.key, m.v); err != nil {
+			t.Fatalf("SetMetadata(%q) unexpected error: %v", m.key, err)
+		}
+	}
+
+	if err := w.AddFloat16("tok_embd.weight", []uint64{3, 2}, []float16.Float16{0x3C00, 0x4000, 0x4200, 0xBC00, 0xC000, 0xC200}); err != nil {
+		t.Fatalf("AddFloat16() unexpected error: %v", err)
+	}
+
+	f32 := binary.LittleEndian.AppendUint32(nil, math.Float32bits(0.25))
+	f32 = binary.LittleEndian.AppendUint32(f32, math.Float32bits(-8))
+	if err := w.AddTensor("norm.weight", []uint64{2}, TypeF32, f32); err != nil {
+		t.Fatalf("AddTensor(F32) unexpected error: %v", err)
+	}
+
+	// Q8_0 block: scale 0.5, quants -16..15
+	q8 := binary.LittleEndian.AppendUint16(nil, 0x3800)
+	for j := 0; j < qk; j++ {
+		q8 = append(q8, byte(int8(j-16)))
+	}
+	if err := w.AddTensor("q8.weight", []uint64{32}, TypeQ8_0, q8); err != nil {
+		t.Fatalf("AddTensor(Q8_0) unexpected error: %v", err)
+	}
+	return writeBytes(t, w)
+}
+
+func writeBytes(t *testing.T, w *Writer) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	n, err := w.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() unexpected error: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, wrote %d bytes", n, buf.Len())
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, align := range []int{0, 64} {
+		f, err := Parse(buildFile(t, align))
+		if err != nil {
+			t.Fatalf("Parse() unexpected error: %v", err)
+		}
+		if f.Version != 3 {
+			t.Errorf("Version = %d, want 3", f.Version)
+		}
+		wantAlign := align
+		if align == 0 {
+			wantAlign = DefaultAlignment
+		}
+		if f.Alignment != wantAlign {
+			t.Errorf("Alignment = %d, want %d", f.Alignment, wantAlign)
+		}
+		if len(f.Tensors) != 3 {
+			t.Fatalf("len(Tensors) = %d, want 3", len(f.Tensors))
+		}
+		for _, ti := range f.Tensors {
+			if ti.Offset%uint64(wantAlign) != 0 {
+				t.Errorf("tensor %q offset %d not aligned to %d", ti.Name, ti.Offset, wantAlign)
+			}
+		}
+
+		emb, err := f.Tensor("tok_embd.weight")
+		if err != nil {
+			t.Fatalf("Tensor() unexpected error: %v", err)
+		}
+		if emb.Shape[0] != 3 || emb.Shape[1] != 2 {
+			t.Errorf("Shape = %v, want [3 2]", emb.Shape)
+		}
+		view, err := emb.Float16s()
+		if err != nil {
+			t.Fatalf("Float16s() unexpected error: %v", err)
+		}
+		if len(view) != 6 || view[2] != 0x4200 || view[5] != 0xC200 {
+			t.Errorf("Float16s() = %v, want [1 2 3 -1 -2 -3]", view)
+		}
+
+		norm, _ := f.Tensor("norm.weight")
+		if _, err := norm.Float16s(); !errors.Is(err, ErrUnsupportedType) {
+			t.Errorf("Float16s() on F32 error = %v, want ErrUnsupportedType", err)
+		}
+		vals, err := norm.ToFloat16s()
+		if err != nil || vals[0] != 0x3400 || vals[1] != 0xC800 {
+			t.Errorf("ToFloat16s() on F32 = %v, %v; want [0.25 -8]", vals, err)
+		}
+
+		q8, _ := f.Tensor("q8.weight")
+		vals, err = q8.ToFloat16s()
+		if err != nil {
+			t.Fatalf("ToFloat16s() on Q8_0 unexpected error: %v", err)
+		}
+		for j, v := range vals {
+			if want := float64(j-16) * 0.5; v.ToFloat64() != want {
+				t.Errorf("Q8_0[%d] = %v, want %v", j, v, want)
+			}
+		}
+	}
+}
+
+func TestMetadataGetters(t *testing.T) {
+	f, err := Parse(buildFile(t, 0))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	if s, err := f.String("general.architecture"); err != nil || s != "llama" {
+		t.Errorf("String() = %q, %v; want llama", s, err)
+	}
+	if u, err := f.Uint("general.quantization_version"); err != nil || u != 2 {
+		t.Errorf("Uint() = %d, %v; want 2", u, err)
+	}
+	if i, err := f.Int("llama.context_length"); err != nil || i != 4096 {
+		t.Errorf("Int() = %d, %v; want 4096", i, err)
+	}
+	if i, err := f.Int("test.offset"); err != nil || i != -3 {
+		t.Errorf("Int() = %d, %v; want -3", i, err)
+	}
+	if _, err := f.Uint("test.offset"); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Uint() of negative value error = %v, want ErrTypeMismatch", err)
+	}
+	if v, err := f.Float("llama.rope.freq_base"); err != nil || v != 10000 {
+		t.Errorf("Float() = %v, %v; want 10000", v, err)
+	}
+	if b, err := f.Bool("llama.use_parallel_residual"); err != nil || !b {
+		t.Errorf("Bool() = %v, %v; want true", b, err)
+	}
+	if toks, err := f.Strings("tokenizer.ggml.tokens"); err != nil || len(toks) != 3 || toks[2] != "hello" {
+		t.Errorf("Strings() = %v, %v", toks, err)
+	}
+	if scores, err := f.Floats("tokenizer.ggml.scores"); err != nil || scores[2] != -2.5 {
+		t.Errorf("Floats() = %v, %v", scores, err)
+	}
+	if types, err := f.Ints("tokenizer.ggml.token_type"); err != nil || types[0] != 3 {
+		t.Errorf("Ints() = %v, %v", types, err)
+	}
+	if _, err := f.String("llama.context_length"); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("String() on uint64 error = %v, want ErrTypeMismatch", err)
+	}
+	if _, err := f.String("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("String() on missing key error = %v, want ErrNotFound", err)
+	}
+	if keys := f.Keys(); len(keys) != 9 || keys[0] != "general.architecture" {
+		t.Errorf("Keys() = %v", keys)
+	}
+}
+
+func TestDequantize(t *testing.T) {
+	// Quants 0..15 in the low nibbles and 15..0 in the high nibbles
+	qs := make([]byte, qk/2)
+	for j := range qs {
+		qs[j] = byte(j) | byte(15-j)<<4
+	}
+	scale := binary.LittleEndian.AppendUint16(nil, 0x4000) // 2.0
+	min := binary.LittleEndian.AppendUint16(nil, 0xBC00)   // -1.0
+	// High bits set for the first element of each half only
+	qh := binary.LittleEndian.AppendUint32(nil, 1|1<<16)
+	q8 := make([]byte, qk)
+	for j := range q8 {
+		q8[j] = byte(int8(j - 16))
+	}
+	sum := binary.LittleEndian.AppendUint16(nil, 0xD000) // -32.0, unused
+
+	join := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+	tests := []struct {
+		name string
+		typ  GGMLType
+		data []byte
+		want func(j int) float64
+	}{
+		{"Q4_0", TypeQ4_0, join(scale, qs), func(j int) float64 {
+			if j < 16 {
+				return float64(j-8) * 2
+			}
+			return float64(15-(j-16)-8) * 2
+		}},
+		{"Q4_1", TypeQ4_1, join(scale, min, qs), func(j int) float64 {
+			if j < 16 {
+				return float64(j)*2 - 1
+			}
+			return float64(15-(j-16))*2 - 1
+		}},
+		{"Q5_0", TypeQ5_0, join(scale, qh, qs), func(j int) float64 {
+			q := j
+			if j >= 16 {
+				q = 15 - (j - 16)
+			}
+			if j == 0 || j == 16 {
+				q |= 16
+			}
+			return float64(q-16) * 2
+		}},
+		{"Q5_1", TypeQ5_1, join(scale, min, qh, qs), func(j int) float64 {
+			q := j
+			if j >= 16 {
+				q = 15 - (j - 16)
+			}
+			if j == 0 || j == 16 {
+				q |= 16
+			}
+			return float64(q)*2 - 1
+		}},
+		{"Q8_1", TypeQ8_1, join(scale, sum, q8), func(j int) float64 {
+			return float64(j-16) * 2
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.data) != typeTraits[tt.typ].typeSize {
+				t.Fatalf("block is %d bytes, want %d", len(tt.data), typeTraits[tt.typ].typeSize)
+			}
+			out := make([]float16.Float16, qk)
+			if err := dequantize(tt.typ, tt.data, out); err != nil {
+				t.Fatalf("dequantize() unexpected error: %v", err)
+			}
+			for j, v := range out {
+				if want := tt.want(j); v.ToFloat64() != want {
+					t.Errorf("%s[%d] = %v, want %v", tt.name, j, v, want)
+				}
+			}
+		})
+	}
+
+	if err := dequantize(TypeI8, make([]byte, qk), make([]float16.Float16, qk)); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("dequantize(I8) error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+// The K-quant blocks below are packed as quantize_row_*_K_ref in
+// ggml-quants.c packs them, from quants L[j] and per-sub-block scales and
+// mins chosen so that every dequantized value is exact in float32
+func TestDequantizeKQuants(t *testing.T) {
+	f16 := func(v float32) []byte {
+		return binary.LittleEndian.AppendUint16(nil, uint16(float16.FromFloat32(v)))
+	}
+	// quants returns L[j] = (7j + j/16) mod levels, which visits every level
+	// in each sub-block
+	quants := func(levels int) []int {
+		L := make([]int, qkK)
+		for j := range L {
+			L[j] = (7*j + j/16) % levels
+		}
+		return L
+	}
+	// packScaleMinK4 packs eight 6-bit scales and mins as Q4_K and Q5_K do
+	packScaleMinK4 := func(ls, lm []int) []byte {
+		b := make([]byte, 12)
+		for j := 0; j < 8; j++ {
+			if j < 4 {
+				b[j], b[j+4] = byte(ls[j]), byte(lm[j])
+				continue
+			}
+			b[j+4] = byte(ls[j]&0x0F | (lm[j]&0x0F)<<4)
+			b[j-4] |= byte(ls[j]>>4) << 6
+			b[j] |= byte(lm[j]>>4) << 6
+		}
+		return b
+	}
+	// pack2 packs 2-bit quants as Q2_K and Q3_K do
+	pack2 := func(L []int) []byte {
+		qs := make([]byte, qkK/4)
+		for j := 0; j < qkK; j += 128 {
+			for l := 0; l < 32; l++ {
+				qs[j/4+l] = byte(L[j+l] | L[j+l+32]<<2 | L[j+l+64]<<4 | L[j+l+96]<<6)
+			}
+		}
+		return qs
+	}
+	sub := func(n int, f func(s int) int) []int {
+		v := make([]int, n)
+		for s := range v {
+			v[s] = f(s)
+		}
+		return v
+	}
+	join := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+	// Q2_K: 4-bit scales and mins per 16 elements, d = 0.5, dmin = 0.25
+	q2L := quants(4)
+	q2sc := sub(16, func(s int) int { return (5*s + 3) % 16 })
+	q2m := sub(16, func(s int) int { return (3*s + 1) % 16 })
+	q2scales := make([]byte, 16)
+	for s := range q2scales {
+		q2scales[s] = byte(q2sc[s] | q2m[s]<<4)
+	}
+
+	// Q3_K: 6-bit scales biased by 32 per 16 elements, quants biased by 4
+	q3L := quants(8)
+	q3ls := sub(16, func(s int) int { return (13*s + 5) % 64 })
+	hmask := make([]byte, qkK/8)
+	low := make([]int, qkK)
+	for j, l := range q3L {
+		if l > 3 {
+			hmask[j%32] |= 1 << (j / 32)
+			l -= 4
+		}
+		low[j] = l
+	}
+	q3scales := make([]byte, 12)
+	for j, l := range q3ls {
+		if j < 8 {
+			q3scales[j] = byte(l & 0x0F)
+		} else {
+			q3scales[j-8] |= byte(l&0x0F) << 4
+		}
+		q3scales[j%4+8] |= byte(l>>4) << (2 * (j / 4))
+	}
+
+	// Q4_K and Q5_K: 6-bit scales and mins per 32 elements
+	kls := sub(8, func(s int) int { return (11*s + 7) % 64 })
+	klm := sub(8, func(s int) int { return (17*s + 3) % 64 })
+	q4L := quants(16)
+	q4qs := make([]byte, qkK/2)
+	for j := 0; j < qkK; j += 64 {
+		for l := 0; l < 32; l++ {
+			q4qs[j/2+l] = byte(q4L[j+l] | q4L[j+l+32]<<4)
+		}
+	}
+	q5L := quants(32)
+	q5qh, q5qs := make([]byte, qkK/8), make([]byte, qkK/2)
+	for n, m1 := 0, byte(1); n < qkK; n, m1 = n+64, m1<<2 {
+		for j := 0; j < 32; j++ {
+			l1, l2 := q5L[n+j], q5L[n+j+32]
+			if l1 > 15 {
+				l1 -= 16
+				q5qh[j] |= m1
+			}
+			if l2 > 15 {
+				l2 -= 16
+				q5qh[j] |= m1 << 1
+			}
+			q5qs[n/2+j] = byte(l1 | l2<<4)
+		}
+	}
+
+	// Q6_K: int8 scales per 16 elements, quants biased by 32
+	q6L := quants(64)
+	q6sc := sub(16, func(s int) int { return 37*s%256 - 128 })
+	q6ql, q6qh := make([]byte, qkK/2), make([]byte, qkK/4)
+	for j := 0; j < qkK; j += 128 {
+		ql, qh := q6ql[j/2:], q6qh[j/4:]
+		for l := 0; l < 32; l++ {
+			ql[l] = byte(q6L[j+l]&0x0F | (q6L[j+l+64]&0x0F)<<4)
+			ql[l+32] = byte(q6L[j+l+32]&0x0F | (q6L[j+l+96]&0x0F)<<4)
+			qh[l] = byte(q6L[j+l]>>4 | q6L[j+l+32]>>4<<2 | q6L[j+l+64]>>4<<4 | q6L[j+l+96]>>4<<6)
+		}
+	}
+	q6scales := make([]byte, 16)
+	for s, v := range q6sc {
+		q6scales[s] = byte(int8(v))
+	}
+
+	// Q8_K: float32 scale, int8 quants and unused block sums
+	q8qs := make([]byte, qkK)
+	for j := range q8qs {
+		q8qs[j] = byte(int8(7*j%256 - 128))
+	}
+
+	tests := []struct {
+		name string
+		typ  GGMLType
+		data []byte
+		want func(j int) float64
+	}{
+		{"Q2_K", TypeQ2_K, join(q2scales, pack2(q2L), f16(0.5), f16(0.25)), func(j int) float64 {
+			return 0.5*float64(q2sc[j/16]*q2L[j]) - 0.25*float64(q2m[j/16])
+		}},
+		{"Q3_K", TypeQ3_K, join(hmask, pack2(low), q3scales, f16(0.5)), func(j int) float64 {
+			return 0.5 * float64((q3ls[j/16]-32)*(q3L[j]-4))
+		}},
+		{"Q4_K", TypeQ4_K, join(f16(0.5), f16(0.25), packScaleMinK4(kls, klm), q4qs), func(j int) float64 {
+			return 0.5*float64(kls[j/32]*q4L[j]) - 0.25*float64(klm[j/32])
+		}},
+		{"Q5_K", TypeQ5_K, join(f16(0.5), f16(0.25), packScaleMinK4(kls, klm), q5qh, q5qs), func(j int) float64 {
+			return 0.5*float64(kls[j/32]*q5L[j]) - 0.25*float64(klm[j/32])
+		}},
+		{"Q6_K", TypeQ6_K, join(q6ql, q6qh, q6scales, f16(0.5)), func(j int) float64 {
+			return 0.5 * float64(q6sc[j/16]*(q6L[j]-32))
+		}},
+		{"Q8_K", TypeQ8_K, join(binary.LittleEndian.AppendUint32(nil, math.Float32bits(0.25)), q8qs, make([]byte, 32)), func(j int) float64 {
+			return 0.25 * float64(int8(q8qs[j]))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.data) != typeTraits[tt.typ].typeSize {
+				t.Fatalf("block is %d bytes, want %d", len(tt.data), typeTraits[tt.typ].typeSize)
+			}
+			// Two blocks, to check the stride
+			out := make([]float16.Float16, 2*qkK)
+			if err := dequantize(tt.typ, join(tt.data, tt.data), out); err != nil {
+				t.Fatalf("dequantize() unexpected error: %v", err)
+			}
+			for j, v := range out {
+				// Compared by value, since a negative scale times 0 gives -0
+				if want := float16.FromFloat64(tt.want(j % qkK)); v.ToFloat64() != want.ToFloat64() {
+					t.Fatalf("%s[%d] = %v, want %v", tt.name, j, v, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	good := buildFile(t, 0)
+
+	badMagic := append([]byte("GGML"), good[4:]...)
+	badVersion := append([]byte(nil), good...)
+	binary.LittleEndian.PutUint32(badVersion[4:], 1)
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, ErrFormat},
+		{"bad magic", badMagic, ErrFormat},
+		{"bad version", badVersion, ErrVersion},
+		{"truncated header", good[:40], ErrFormat},
+		{"truncated data", good[:len(good)-40], ErrFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	w := NewWriter()
+	if err := w.SetMetadata("x", struct{}{}); err == nil {
+		t.Error("SetMetadata() with unsupported type should fail")
+	}
+	if err := w.AddTensor("q", []uint64{31}, TypeQ8_0, make([]byte, 34)); err == nil {
+		t.Error("AddTensor() with partial block should fail")
+	}
+	if err := w.AddFloat16("a", []uint64{1}, []float16.Float16{0}); err != nil {
+		t.Fatalf("AddFloat16() unexpected error: %v", err)
+	}
+	if err := w.AddFloat16("a", []uint64{1}, []float16.Float16{0}); err == nil {
+		t.Error("AddFloat16() with duplicate name should fail")
+	}
+}
+
+func TestOpenPatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.gguf")
+	w := NewWriter()
+	w.SetMetadata("general.name", "patch-test")
+	w.AddFloat16("w", []uint64{4}, []float16.Float16{0x3C00, 0x3C00, 0x3C00, 0x3C00})
+	if err := w.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+
+	f, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	tensor, _ := f.Tensor("w")
+	vals, err := tensor.ToFloat16s()
+	if err != nil {
+		t.Fatalf("ToFloat16s() unexpected error: %v", err)
+	}
+	name, _ := f.String("general.name")
+	f.Close()
+
+	// Patch one value and write a new file with the same metadata
+	vals[2] = 0x4000
+	out := NewWriter()
+	out.SetMetadata("general.name", name)
+	out.AddFloat16("w", tensor.Shape, vals)
+	patched, err := Parse(writeBytes(t, out))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	pt, _ := patched.Tensor("w")
+	got, _ := pt.Float16s()
+	if got[2] != 0x4000 || got[1] != 0x3C00 {
+		t.Errorf("patched tensor = %v, want [1 1 2 1]", got)
+	}
+}
@@ -0,0 +1,289 @@
+package gguf
+
+import (
+	"fmt"
+	"math"
+)
+
+// ValueType identifies the type of a metadata value
+type ValueType uint32
+
+// Metadata value types defined by the GGUF specification
+const (
+	TypeUint8 ValueType = iota
+	TypeInt8
+	TypeUint16
+	TypeInt16
+	TypeUint32
+	TypeInt32
+	TypeFloat32
+	TypeBool
+	TypeString
+	TypeArray
+	TypeUint64
+	TypeInt64
+	TypeFloat64
+)
+
+func (t ValueType) String() string {
+	names := [...]string{"uint8", "int8", "uint16", "int16", "uint32", "int32", "float32", "bool", "string", "array", "uint64", "int64", "float64"}
+	if int(t) < len(names) {
+		return names[t]
+	}
+	return fmt.Sprintf("ValueType(%d)", uint32(t))
+}
+
+// Value is a decoded metadata value.
+//
+// Scalars hold the matching Go type (uint8, int8, ..., float64, bool,
+// string). Arrays hold a []Value whose elements all have type ElemType.
+type Value struct {
+	Type     ValueType
+	ElemType ValueType // element type when Type is TypeArray
+	Data     interface{}
+}
+
+// maxArrayLen bounds metadata arrays so corrupt counts cannot exhaust memory
+const maxArrayLen = 1 << 28
+
+// value decodes a metadata value of type t
+func (d *decoder) value(t ValueType) Value {
+	v := Value{Type: t}
+	switch t {
+	case TypeUint8:
+		v.Data = d.u8()
+	case TypeInt8:
+		v.Data = int8(d.u8())
+	case TypeUint16:
+		v.Data = d.u16()
+	case TypeInt16:
+		v.Data = int16(d.u16())
+	case TypeUint32:
+		v.Data = d.u32()
+	case TypeInt32:
+		v.Data = int32(d.u32())
+	case TypeFloat32:
+		v.Data = math.Float32frombits(d.u32())
+	case TypeBool:
+		b := d.u8()
+		if b > 1 {
+			d.fail("invalid bool %d", b)
+		}
+		v.Data = b == 1
+	case TypeString:
+		v.Data = d.str()
+	case TypeUint64:
+		v.Data = d.u64()
+	case TypeInt64:
+		v.Data = int64(d.u64())
+	case TypeFloat64:
+		v.Data = math.Float64frombits(d.u64())
+	case TypeArray:
+		v.ElemType = ValueType(d.u32())
+		n := d.u64()
+		if v.ElemType == TypeArray {
+			d.fail("nested arrays are not supported")
+		}
+		if n > maxArrayLen || n > uint64(len(d.b)-d.pos) {
+			d.fail("implausible array length %d", n)
+		}
+		if d.err != nil {
+			return v
+		}
+		elems := make([]Value, n)
+		for i := range elems {
+			elems[i] = d.value(v.ElemType)
+		}
+		v.Data = elems
+	default:
+		d.fail("unknown value type %d", uint32(t))
+	}
+	return v
+}
+
+// lookup returns the value for key or ErrNotFound
+func (f *File) lookup(key string) (Value, error) {
+	v, ok := f.values[key]
+	if !ok {
+		return Value{}, fmt.Errorf("%w: key %q", ErrNotFound, key)
+	}
+	return v, nil
+}
+
+func mismatch(key string, v Value, want string) error {
+	return fmt.Errorf("%w: key %q is %s, not %s", ErrTypeMismatch, key, v.Type, want)
+}
+
+// String returns a string metadata value
+func (f *File) String(key string) (string, error) {
+	v, err := f.lookup(key)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.Data.(string)
+	if !ok {
+		return "", mismatch(key, v, "string")
+	}
+	return s, nil
+}
+
+// Bool returns a bool metadata value
+func (f *File) Bool(key string) (bool, error) {
+	v, err := f.lookup(key)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.Data.(bool)
+	if !ok {
+		return false, mismatch(key, v, "bool")
+	}
+	return b, nil
+}
+
+// Uint returns an unsigned integer metadata value of any width.
+// Signed values are accepted when they are non-negative.
+func (f *File) Uint(key string) (uint64, error) {
+	v, err := f.lookup(key)
+	if err != nil {
+		return 0, err
+	}
+	if u, ok := asUint(v); ok {
+		return u, nil
+	}
+	return 0, mismatch(key, v, "unsigned integer")
+}
+
+// Int returns a signed integer metadata value of any width.
+// Unsigned values are accepted when they fit in an int64.
+func (f *File) Int(key string) (int64, error) {
+	v, err := f.lookup(key)
+	if err != nil {
+		return 0, err
+	}
+	if i, ok := asInt(v); ok {
+		return i, nil
+	}
+	return 0, mismatch(key, v, "integer")
+}
+
+// Float returns a float32 or float64 metadata value as float64
+func (f *File) Float(key string) (float64, error) {
+	v, err := f.lookup(key)
+	if err != nil {
+		return 0, err
+	}
+	switch x := v.Data.(type) {
+	case float32:
+		return float64(x), nil
+	case float64:
+		return x, nil
+	}
+	return 0, mismatch(key, v, "float")
+}
+
+// Strings returns a string array metadata value
+func (f *File) Strings(key string) ([]string, error) {
+	elems, err := f.array(key, "string array")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(elems))
+	for i, e := range elems {
+		s, ok := e.Data.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: key %q is not a string array", ErrTypeMismatch, key)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// Ints returns an integer array metadata value
+func (f *File) Ints(key string) ([]int64, error) {
+	elems, err := f.array(key, "integer array")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]int64, len(elems))
+	for i, e := range elems {
+		n, ok := asInt(e)
+		if !ok {
+			return nil, fmt.Errorf("%w: key %q is not an integer array", ErrTypeMismatch, key)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+// Floats returns a float array metadata value
+func (f *File) Floats(key string) ([]float64, error) {
+	elems, err := f.array(key, "float array")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, len(elems))
+	for i, e := range elems {
+		switch x := e.Data.(type) {
+		case float32:
+			out[i] = float64(x)
+		case float64:
+			out[i] = x
+		default:
+			return nil, fmt.Errorf("%w: key %q is not a float array", ErrTypeMismatch, key)
+		}
+	}
+	return out, nil
+}
+
+func (f *File) array(key, want string) ([]Value, error) {
+	v, err := f.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	elems, ok := v.Data.([]Value)
+	if !ok {
+		return nil, mismatch(key, v, want)
+	}
+	return elems, nil
+}
+
+func asUint(v Value) (uint64, bool) {
+	switch x := v.Data.(type) {
+	case uint8:
+		return uint64(x), true
+	case uint16:
+		return uint64(x), true
+	case uint32:
+		return uint64(x), true
+	case uint64:
+		return x, true
+	}
+	if i, ok := asInt(v); ok && i >= 0 {
+		return uint64(i), true
+	}
+	return 0, false
+}
+
+func asInt(v Value) (int64, bool) {
+	switch x := v.Data.(type) {
+	case int8:
+		return int64(x), true
+	case int16:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case int64:
+		return x, true
+	case uint8:
+		return int64(x), true
+	case uint16:
+		return int64(x), true
+	case uint32:
+		return int64(x), true
+	case uint64:
+		if x <= math.MaxInt64 {
+			return int64(x), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,345 @@
+package gguf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/zerfoo/float16"
+)
+
+// GGMLType is the element type of a tensor
+type GGMLType uint32
+
+// Tensor types defined by GGML. Only the types with entries in typeTraits
+// can be read; of those, the integer types cannot be decoded to Float16.
+const (
+	TypeF32  GGMLType = 0
+	TypeF16  GGMLType = 1
+	TypeQ4_0 GGMLType = 2
+	TypeQ4_1 GGMLType = 3
+	TypeQ5_0 GGMLType = 6
+	TypeQ5_1 GGMLType = 7
+	TypeQ8_0 GGMLType = 8
+	TypeQ8_1 GGMLType = 9
+	TypeQ2_K GGMLType = 10
+	TypeQ3_K GGMLType = 11
+	TypeQ4_K GGMLType = 12
+	TypeQ5_K GGMLType = 13
+	TypeQ6_K GGMLType = 14
+	TypeQ8_K GGMLType = 15
+	TypeI8   GGMLType = 24
+	TypeI16  GGMLType = 25
+	TypeI32  GGMLType = 26
+	TypeI64  GGMLType = 27
+	TypeF64  GGMLType = 28
+	TypeBF16 GGMLType = 30
+)
+
+// traits gives the number of elements per block and the bytes per block
+type traits struct {
+	name      string
+	blockSize int
+	typeSize  int
+}
+
+var typeTraits = map[GGMLType]traits{
+	TypeF32:  {"F32", 1, 4},
+	TypeF16:  {"F16", 1, 2},
+	TypeQ4_0: {"Q4_0", qk, 2 + qk/2},
+	TypeQ4_1: {"Q4_1", qk, 4 + qk/2},
+	TypeQ5_0: {"Q5_0", qk, 6 + qk/2},
+	TypeQ5_1: {"Q5_1", qk, 8 + qk/2},
+	TypeQ8_0: {"Q8_0", qk, 2 + qk},
+	TypeQ8_1: {"Q8_1", qk, 4 + qk},
+	TypeQ2_K: {"Q2_K", qkK, 84},
+	TypeQ3_K: {"Q3_K", qkK, 110},
+	TypeQ4_K: {"Q4_K", qkK, 144},
+	TypeQ5_K: {"Q5_K", qkK, 176},
+	TypeQ6_K: {"Q6_K", qkK, 210},
+	TypeQ8_K: {"Q8_K", qkK, 292},
+	TypeI8:   {"I8", 1, 1},
+	TypeI16:  {"I16", 1, 2},
+	TypeI32:  {"I32", 1, 4},
+	TypeI64:  {"I64", 1, 8},
+	TypeF64:  {"F64", 1, 8},
+	TypeBF16: {"BF16", 1, 2},
+}
+
+// Block sizes of the legacy and K-quant formats
+const (
+	qk  = 32
+	qkK = 256
+)
+
+func (t GGMLType) String() string {
+	if tr, ok := typeTraits[t]; ok {
+		return tr.name
+	}
+	return fmt.Sprintf("GGMLType(%d)", uint32(t))
+}
+
+// round converts a dequantized value to Float16 with round-to-nearest-even
+func round(v float32) float16.Float16 {
+	h, _ := float16.FromFloat32WithMode(v, float16.ModeIEEE, float16.RoundNearestEven)
+	return h
+}
+
+// half reads a little-endian Float16 scale and widens it
+func half(b []byte) float32 {
+	return float16.Float16(binary.LittleEndian.Uint16(b)).ToFloat32()
+}
+
+// dequantize decodes src, holding len(dst) elements of type t, into dst.
+// The layouts follow ggml-quants.c.
+func dequantize(t GGMLType, src []byte, dst []float16.Float16) error {
+	switch t {
+	case TypeF16:
+		float16.DecodeFloat16s(dst, src, binary.LittleEndian)
+	case TypeF32:
+		for i := range dst {
+			dst[i] = round(math.Float32frombits(binary.LittleEndian.Uint32(src[4*i:])))
+		}
+	case TypeF64:
+		for i := range dst {
+			dst[i], _ = float16.FromFloat64WithMode(math.Float64frombits(binary.LittleEndian.Uint64(src[8*i:])), float16.ModeIEEE, float16.RoundNearestEven)
+		}
+	case TypeBF16:
+		for i := range dst {
+			dst[i] = round(math.Float32frombits(uint32(binary.LittleEndian.Uint16(src[2*i:])) << 16))
+		}
+	case TypeQ4_0:
+		for b := 0; b < len(dst)/qk; b++ {
+			blk, out := src[b*18:], dst[b*qk:]
+			d := half(blk)
+			qs := blk[2:18]
+			for j := 0; j < qk/2; j++ {
+				out[j] = round(float32(int(qs[j]&0x0F)-8) * d)
+				out[j+qk/2] = round(float32(int(qs[j]>>4)-8) * d)
+			}
+		}
+	case TypeQ4_1:
+		for b := 0; b < len(dst)/qk; b++ {
+			blk, out := src[b*20:], dst[b*qk:]
+			d, m := half(blk), half(blk[2:])
+			qs := blk[4:20]
+			for j := 0; j < qk/2; j++ {
+				out[j] = round(float32(qs[j]&0x0F)*d + m)
+				out[j+qk/2] = round(float32(qs[j]>>4)*d + m)
+			}
+		}
+	case TypeQ5_0:
+		for b := 0; b < len(dst)/qk; b++ {
+			blk, out := src[b*22:], dst[b*qk:]
+			d := half(blk)
+			qh := binary.LittleEndian.Uint32(blk[2:])
+			qs := blk[6:22]
+			for j := 0; j < qk/2; j++ {
+				xh0 := byte((qh>>j)<<4) & 0x10
+				xh1 := byte(qh>>(j+12)) & 0x10
+				out[j] = round(float32(int(qs[j]&0x0F|xh0)-16) * d)
+				out[j+qk/2] = round(float32(int(qs[j]>>4|xh1)-16) * d)
+			}
+		}
+	case TypeQ5_1:
+		for b := 0; b < len(dst)/qk; b++ {
+			blk, out := src[b*24:], dst[b*qk:]
+			d, m := half(blk), half(blk[2:])
+			qh := binary.LittleEndian.Uint32(blk[4:])
+			qs := blk[8:24]
+			for j := 0; j < qk/2; j++ {
+				xh0 := byte((qh>>j)<<4) & 0x10
+				xh1 := byte(qh>>(j+12)) & 0x10
+				out[j] = round(float32(qs[j]&0x0F|xh0)*d + m)
+				out[j+qk/2] = round(float32(qs[j]>>4|xh1)*d + m)
+			}
+		}
+	case TypeQ8_0:
+		for b := 0; b < len(dst)/qk; b++ {
+			blk, out := src[b*34:], dst[b*qk:]
+			d := half(blk)
+			for j := 0; j < qk; j++ {
+				out[j] = round(float32(int8(blk[2+j])) * d)
+			}
+		}
+	case TypeQ8_1:
+		// The second half is the sum of the quants times d, used only by
+		// dot products
+		for b := 0; b < len(dst)/qk; b++ {
+			blk, out := src[b*36:], dst[b*qk:]
+			d := half(blk)
+			for j := 0; j < qk; j++ {
+				out[j] = round(float32(int8(blk[4+j])) * d)
+			}
+		}
+	case TypeQ2_K:
+		for b := 0; b < len(dst)/qkK; b++ {
+			dequantizeQ2K(src[b*84:], dst[b*qkK:])
+		}
+	case TypeQ3_K:
+		for b := 0; b < len(dst)/qkK; b++ {
+			dequantizeQ3K(src[b*110:], dst[b*qkK:])
+		}
+	case TypeQ4_K:
+		for b := 0; b < len(dst)/qkK; b++ {
+			dequantizeQ4K(src[b*144:], dst[b*qkK:])
+		}
+	case TypeQ5_K:
+		for b := 0; b < len(dst)/qkK; b++ {
+			dequantizeQ5K(src[b*176:], dst[b*qkK:])
+		}
+	case TypeQ6_K:
+		for b := 0; b < len(dst)/qkK; b++ {
+			dequantizeQ6K(src[b*210:], dst[b*qkK:])
+		}
+	case TypeQ8_K:
+		for b := 0; b < len(dst)/qkK; b++ {
+			blk, out := src[b*292:], dst[b*qkK:]
+			// Q8_K has a float32 scale, followed by the quants and 16 block
+			// sums that only dot products use
+			d := math.Float32frombits(binary.LittleEndian.Uint32(blk))
+			for j := 0; j < qkK; j++ {
+				out[j] = round(float32(int8(blk[4+j])) * d)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: cannot decode %s", ErrUnsupportedType, t)
+	}
+	return nil
+}
+
+// dequantizeQ2K decodes one Q2_K block: 16 scale bytes, each a 4-bit scale
+// and 4-bit min for 16 elements, 64 bytes of 2-bit quants, and the Float16
+// super-block scale d and min scale dmin
+func dequantizeQ2K(blk []byte, out []float16.Float16) {
+	scales, qs := blk[:16], blk[16:80]
+	d, dmin := half(blk[80:]), half(blk[82:])
+	is := 0
+	for n := 0; n < qkK; n += 128 {
+		q := qs[n/4:]
+		for shift := 0; shift < 8; shift += 2 {
+			for _, off := range []int{0, 16} {
+				sc := scales[is]
+				is++
+				dl, ml := d*float32(sc&0x0F), dmin*float32(sc>>4)
+				for l := 0; l < 16; l++ {
+					out[l] = round(dl*float32((q[l+off]>>shift)&3) - ml)
+				}
+				out = out[16:]
+			}
+		}
+	}
+}
+
+// dequantizeQ3K decodes one Q3_K block: 32 bytes holding the high bit of
+// each 3-bit quant, 64 bytes of low 2-bit quants, 12 bytes packing sixteen
+// 6-bit scales biased by 32, and the Float16 super-block scale
+func dequantizeQ3K(blk []byte, out []float16.Float16) {
+	hmask, qs, packed := blk[:32], blk[32:96], blk[96:108]
+	d := half(blk[108:])
+
+	// Scale j has its low 4 bits in nibble j%8 of bytes 0-7 and its high 2
+	// bits in bits 2*(j/4) of byte 8+j%4
+	var scales [16]int
+	for j := range scales {
+		lo := packed[j%8] >> (4 * (j / 8)) & 0x0F
+		hi := packed[8+j%4] >> (2 * (j / 4)) & 3
+		scales[j] = int(lo|hi<<4) - 32
+	}
+
+	is := 0
+	m := byte(1)
+	for n := 0; n < qkK; n += 128 {
+		q := qs[n/4:]
+		for shift := 0; shift < 8; shift += 2 {
+			for _, off := range []int{0, 16} {
+				dl := d * float32(scales[is])
+				is++
+				for l := 0; l < 16; l++ {
+					v := int((q[l+off] >> shift) & 3)
+					if hmask[l+off]&m == 0 {
+						v -= 4
+					}
+					out[l] = round(dl * float32(v))
+				}
+				out = out[16:]
+			}
+			m <<= 1
+		}
+	}
+}
+
+// scaleMinK4 unpacks the 6-bit scale and min of sub-block j from the 12
+// bytes shared by Q4_K and Q5_K
+func scaleMinK4(j int, q []byte) (sc, m byte) {
+	if j < 4 {
+		return q[j] & 63, q[j+4] & 63
+	}
+	return q[j+4]&0x0F | (q[j-4]>>6)<<4, q[j+4]>>4 | (q[j]>>6)<<4
+}
+
+// dequantizeQ4K decodes one Q4_K block: the Float16 super-block scale d and
+// min scale dmin, 12 bytes of 6-bit scales and mins for 32 elements each,
+// and 128 bytes of 4-bit quants
+func dequantizeQ4K(blk []byte, out []float16.Float16) {
+	d, dmin := half(blk), half(blk[2:])
+	scales, qs := blk[4:16], blk[16:144]
+	for j := 0; j < qkK/64; j++ {
+		sc1, m1 := scaleMinK4(2*j, scales)
+		sc2, m2 := scaleMinK4(2*j+1, scales)
+		d1, min1 := d*float32(sc1), dmin*float32(m1)
+		d2, min2 := d*float32(sc2), dmin*float32(m2)
+		q := qs[32*j:]
+		for l := 0; l < 32; l++ {
+			out[64*j+l] = round(d1*float32(q[l]&0x0F) - min1)
+			out[64*j+32+l] = round(d2*float32(q[l]>>4) - min2)
+		}
+	}
+}
+
+// dequantizeQ5K decodes one Q5_K block, laid out as Q4_K with 32 bytes
+// holding the fifth bit of each quant before the low nibbles
+func dequantizeQ5K(blk []byte, out []float16.Float16) {
+	d, dmin := half(blk), half(blk[2:])
+	scales, qh, qs := blk[4:16], blk[16:48], blk[48:176]
+	for j := 0; j < qkK/64; j++ {
+		sc1, m1 := scaleMinK4(2*j, scales)
+		sc2, m2 := scaleMinK4(2*j+1, scales)
+		d1, min1 := d*float32(sc1), dmin*float32(m1)
+		d2, min2 := d*float32(sc2), dmin*float32(m2)
+		u1, u2 := byte(1)<<(2*j), byte(2)<<(2*j)
+		q := qs[32*j:]
+		for l := 0; l < 32; l++ {
+			lo, hi := q[l]&0x0F, q[l]>>4
+			if qh[l]&u1 != 0 {
+				lo += 16
+			}
+			if qh[l]&u2 != 0 {
+				hi += 16
+			}
+			out[64*j+l] = round(d1*float32(lo) - min1)
+			out[64*j+32+l] = round(d2*float32(hi) - min2)
+		}
+	}
+}
+
+// dequantizeQ6K decodes one Q6_K block: 128 bytes of low 4-bit quants, 64
+// bytes of high 2-bit quants, sixteen int8 scales for 16 elements each, and
+// the Float16 super-block scale. Quants are biased by 32.
+func dequantizeQ6K(blk []byte, out []float16.Float16) {
+	d := half(blk[208:])
+	for n := 0; n < qkK/128; n++ {
+		ql, qh, sc, y := blk[64*n:], blk[128+32*n:], blk[192+8*n:], out[128*n:]
+		for l := 0; l < 32; l++ {
+			is := l / 16
+			q1 := int(ql[l]&0x0F|(qh[l]>>0&3)<<4) - 32
+			q2 := int(ql[l+32]&0x0F|(qh[l]>>2&3)<<4) - 32
+			q3 := int(ql[l]>>4|(qh[l]>>4&3)<<4) - 32
+			q4 := int(ql[l+32]>>4|(qh[l]>>6&3)<<4) - 32
+			y[l] = round(d * float32(int8(sc[is])) * float32(q1))
+			y[l+32] = round(d * float32(int8(sc[is+2])) * float32(q2))
+			y[l+64] = round(d * float32(int8(sc[is+4])) * float32(q3))
+			y[l+96] = round(d * float32(int8(sc[is+6])) * float32(q4))
+		}
+	}
+}
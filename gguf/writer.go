@@ -0,0 +1,266 @@
+package gguf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/zerfoo/float16"
+)
+
+// Writer builds a GGUF version 3 file.
+// Metadata and tensors are written in the order they were added.
+type Writer struct {
+	// Alignment of tensor data; DefaultAlignment when zero. A non-default
+	// value is recorded in the general.alignment metadata key.
+	Alignment int
+
+	keys    []string
+	values  map[string]Value
+	tensors []writerTensor
+	names   map[string]bool
+}
+
+type writerTensor struct {
+	info TensorInfo
+	data []byte
+}
+
+// NewWriter returns an empty Writer
+func NewWriter() *Writer {
+	return &Writer{values: make(map[string]Value), names: make(map[string]bool)}
+}
+
+// SetMetadata sets a metadata key. v must be one of uint8, int8, uint16,
+// int16, uint32, int32, uint64, int64, float32, float64, bool or string, a
+// slice of one of those types, or a Value.
+func (w *Writer) SetMetadata(key string, v interface{}) error {
+	val, err := toValue(v)
+	if err != nil {
+		return fmt.Errorf("gguf: metadata %q: %w", key, err)
+	}
+	if _, ok := w.values[key]; !ok {
+		w.keys = append(w.keys, key)
+	}
+	w.values[key] = val
+	return nil
+}
+
+// AddTensor appends a tensor with raw data already encoded as typ
+func (w *Writer) AddTensor(name string, shape []uint64, typ GGMLType, data []byte) error {
+	if w.names[name] {
+		return fmt.Errorf("gguf: duplicate tensor %q", name)
+	}
+	tr, ok := typeTraits[typ]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, typ)
+	}
+	ti := TensorInfo{Name: name, Shape: append([]uint64(nil), shape...), Type: typ}
+	n := ti.Elements()
+	if n%uint64(tr.blockSize) != 0 || n/uint64(tr.blockSize)*uint64(tr.typeSize) != uint64(len(data)) {
+		return fmt.Errorf("gguf: tensor %q: %d bytes do not match %s shape %v", name, len(data), typ, shape)
+	}
+	w.names[name] = true
+	w.tensors = append(w.tensors, writerTensor{info: ti, data: data})
+	return nil
+}
+
+// AddFloat16 appends an F16 tensor
+func (w *Writer) AddFloat16(name string, shape []uint64, data []float16.Float16) error {
+	return w.AddTensor(name, shape, TypeF16, float16.AppendFloat16s(nil, binary.LittleEndian, data...))
+}
+
+// WriteTo encodes the file to out
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	align := w.Alignment
+	if align == 0 {
+		align = DefaultAlignment
+	}
+	if align < 0 || align&(align-1) != 0 {
+		return 0, fmt.Errorf("gguf: alignment %d is not a power of two", align)
+	}
+	keys, values := w.keys, w.values
+	if align != DefaultAlignment {
+		if _, ok := values[alignmentKey]; !ok {
+			keys = append(append([]string(nil), keys...), alignmentKey)
+		}
+		values = make(map[string]Value, len(w.values)+1)
+		for k, v := range w.values {
+			values[k] = v
+		}
+		values[alignmentKey] = Value{Type: TypeUint32, Data: uint32(align)}
+	}
+
+	e := &encoder{}
+	e.u32(magic)
+	e.u32(3)
+	e.u64(uint64(len(w.tensors)))
+	e.u64(uint64(len(keys)))
+	for _, k := range keys {
+		e.str(k)
+		e.u32(uint32(values[k].Type))
+		e.value(values[k])
+	}
+
+	var offset uint64
+	for _, t := range w.tensors {
+		e.str(t.info.Name)
+		e.u32(uint32(len(t.info.Shape)))
+		for _, d := range t.info.Shape {
+			e.u64(d)
+		}
+		e.u32(uint32(t.info.Type))
+		e.u64(offset)
+		offset = uint64(alignUp(int(offset)+len(t.data), align))
+	}
+	e.pad(align)
+
+	bw := bufio.NewWriter(out)
+	n, _ := bw.Write(e.b)
+	written := int64(n)
+	zeros := make([]byte, align)
+	for _, t := range w.tensors {
+		n, _ = bw.Write(t.data)
+		written += int64(n)
+		if p := alignUp(len(t.data), align) - len(t.data); p > 0 {
+			n, _ = bw.Write(zeros[:p])
+			written += int64(n)
+		}
+	}
+	return written, bw.Flush()
+}
+
+// WriteFile encodes the file to the named path
+func (w *Writer) WriteFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// toValue wraps a Go value as a metadata Value
+func toValue(v interface{}) (Value, error) {
+	switch x := v.(type) {
+	case Value:
+		return x, nil
+	case uint8:
+		return Value{Type: TypeUint8, Data: x}, nil
+	case int8:
+		return Value{Type: TypeInt8, Data: x}, nil
+	case uint16:
+		return Value{Type: TypeUint16, Data: x}, nil
+	case int16:
+		return Value{Type: TypeInt16, Data: x}, nil
+	case uint32:
+		return Value{Type: TypeUint32, Data: x}, nil
+	case int32:
+		return Value{Type: TypeInt32, Data: x}, nil
+	case uint64:
+		return Value{Type: TypeUint64, Data: x}, nil
+	case int64:
+		return Value{Type: TypeInt64, Data: x}, nil
+	case float32:
+		return Value{Type: TypeFloat32, Data: x}, nil
+	case float64:
+		return Value{Type: TypeFloat64, Data: x}, nil
+	case bool:
+		return Value{Type: TypeBool, Data: x}, nil
+	case string:
+		return Value{Type: TypeString, Data: x}, nil
+	case []string:
+		return arrayValue(TypeString, x)
+	case []uint32:
+		return arrayValue(TypeUint32, x)
+	case []int32:
+		return arrayValue(TypeInt32, x)
+	case []uint64:
+		return arrayValue(TypeUint64, x)
+	case []int64:
+		return arrayValue(TypeInt64, x)
+	case []float32:
+		return arrayValue(TypeFloat32, x)
+	case []float64:
+		return arrayValue(TypeFloat64, x)
+	case []bool:
+		return arrayValue(TypeBool, x)
+	case []uint8:
+		return arrayValue(TypeUint8, x)
+	case []int8:
+		return arrayValue(TypeInt8, x)
+	}
+	return Value{}, fmt.Errorf("unsupported type %T", v)
+}
+
+func arrayValue[T any](elem ValueType, xs []T) (Value, error) {
+	elems := make([]Value, len(xs))
+	for i, x := range xs {
+		elems[i] = Value{Type: elem, Data: x}
+	}
+	return Value{Type: TypeArray, ElemType: elem, Data: elems}, nil
+}
+
+// encoder appends little-endian GGUF primitives
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) u32(v uint32) { e.b = binary.LittleEndian.AppendUint32(e.b, v) }
+func (e *encoder) u64(v uint64) { e.b = binary.LittleEndian.AppendUint64(e.b, v) }
+
+func (e *encoder) str(s string) {
+	e.u64(uint64(len(s)))
+	e.b = append(e.b, s...)
+}
+
+func (e *encoder) pad(align int) {
+	for len(e.b)%align != 0 {
+		e.b = append(e.b, 0)
+	}
+}
+
+func (e *encoder) value(v Value) {
+	switch x := v.Data.(type) {
+	case uint8:
+		e.b = append(e.b, x)
+	case int8:
+		e.b = append(e.b, byte(x))
+	case uint16:
+		e.b = binary.LittleEndian.AppendUint16(e.b, x)
+	case int16:
+		e.b = binary.LittleEndian.AppendUint16(e.b, uint16(x))
+	case uint32:
+		e.u32(x)
+	case int32:
+		e.u32(uint32(x))
+	case uint64:
+		e.u64(x)
+	case int64:
+		e.u64(uint64(x))
+	case float32:
+		e.u32(math.Float32bits(x))
+	case float64:
+		e.u64(math.Float64bits(x))
+	case bool:
+		if x {
+			e.b = append(e.b, 1)
+		} else {
+			e.b = append(e.b, 0)
+		}
+	case string:
+		e.str(x)
+	case []Value:
+		e.u32(uint32(v.ElemType))
+		e.u64(uint64(len(x)))
+		for _, el := range x {
+			e.value(el)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package halfimage provides image.Image implementations with half-precision
+// samples, together with their color types and tone mapping to 8-bit
+// images.
+//
+// RGBA16F and Gray16F implement draw.Image, so they work with image/draw and
+// any other code written against the standard interfaces. Samples are
+// linear and unbounded: values above 1 carry high dynamic range, and are
+// clipped only when a color is read through the color.Color interface or
+// tone mapped. As in image/color, RGBA colors are alpha-premultiplied.
+package halfimage
+
+import (
+	"image/color"
+
+	"github.com/zerfoo/float16"
+)
+
+// RGBAColor is an alpha-premultiplied color with half-precision channels
+type RGBAColor struct {
+	R, G, B, A float16.Float16
+}
+
+// RGBA implements color.Color. Channels are clipped to [0, 1], with the
+// color channels further limited by alpha; NaN reads as 0.
+func (c RGBAColor) RGBA() (r, g, b, a uint32) {
+	a = to16(c.A)
+	return min(to16(c.R), a), min(to16(c.G), a), min(to16(c.B), a), a
+}
+
+// GrayColor is a half-precision gray level
+type GrayColor struct {
+	Y float16.Float16
+}
+
+// RGBA implements color.Color, clipping Y to [0, 1]
+func (c GrayColor) RGBA() (r, g, b, a uint32) {
+	y := to16(c.Y)
+	return y, y, y, 0xffff
+}
+
+// to16 clips a sample to [0, 1] and scales it to 16 bits
+func to16(f float16.Float16) uint32 {
+	v := f.ToFloat32()
+	if !(v > 0) { // also catches NaN
+		return 0
+	}
+	if v >= 1 {
+		return 0xffff
+	}
+	return uint32(v*0xffff + 0.5)
+}
+
+// from16 converts a 16-bit color channel to a sample in [0, 1]
+func from16(v uint32) float16.Float16 {
+	return float16.FromFloat32(float32(v) / 0xffff)
+}
+
+// Models for the color types
+var (
+	RGBAModel color.Model = color.ModelFunc(rgbaModel)
+	GrayModel color.Model = color.ModelFunc(grayModel)
+)
+
+func rgbaModel(c color.Color) color.Color {
+	if _, ok := c.(RGBAColor); ok {
+		return c
+	}
+	if g, ok := c.(GrayColor); ok {
+		return RGBAColor{g.Y, g.Y, g.Y, float16.FromFloat32(1)}
+	}
+	r, g, b, a := c.RGBA()
+	return RGBAColor{from16(r), from16(g), from16(b), from16(a)}
+}
+
+func grayModel(c color.Color) color.Color {
+	switch c := c.(type) {
+	case GrayColor:
+		return c
+	case RGBAColor:
+		// Keep high dynamic range values rather than clipping through RGBA
+		y := 0.299*c.R.ToFloat32() + 0.587*c.G.ToFloat32() + 0.114*c.B.ToFloat32()
+		return GrayColor{float16.FromFloat32(y)}
+	}
+	// Same weights as color.GrayModel
+	r, g, b, _ := c.RGBA()
+	y := (19595*r + 38470*g + 7471*b + 1<<15) >> 16
+	return GrayColor{from16(y)}
+}
@@ -0,0 +1,224 @@
+package halfimage
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+var (
+	_ draw.Image  = (*RGBA16F)(nil)
+	_ draw.Image  = (*Gray16F)(nil)
+	_ color.Color = RGBAColor{}
+	_ color.Color = GrayColor{}
+)
+
+func h(f float32) float16.Float16 { return float16.FromFloat32(f) }
+
+func TestRGBAColor(t *testing.T) {
+	tests := []struct {
+		name       string
+		c          RGBAColor
+		r, g, b, a uint32
+	}{
+		{"opaque", RGBAColor{h(1), h(0.5), h(0), h(1)}, 0xffff, 0x8000, 0, 0xffff},
+		{"hdr clipped", RGBAColor{h(8), h(1.5), h(-2), h(1)}, 0xffff, 0xffff, 0, 0xffff},
+		{"limited by alpha", RGBAColor{h(1), h(0.25), h(0), h(0.5)}, 0x8000, 0x4000, 0, 0x8000},
+		{"nan", RGBAColor{float16.QuietNaN, 0, 0, float16.QuietNaN}, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		r, g, b, a := tt.c.RGBA()
+		if r != tt.r || g != tt.g || b != tt.b || a != tt.a {
+			t.Errorf("%s: RGBA() = %#x %#x %#x %#x, want %#x %#x %#x %#x", tt.name, r, g, b, a, tt.r, tt.g, tt.b, tt.a)
+		}
+	}
+
+	if r, g, b, a := (GrayColor{h(0.25)}).RGBA(); r != 0x4000 || g != r || b != r || a != 0xffff {
+		t.Errorf("GrayColor.RGBA() = %#x %#x %#x %#x", r, g, b, a)
+	}
+}
+
+func TestModels(t *testing.T) {
+	got := RGBAModel.Convert(color.RGBA{0xff, 0x80, 0x00, 0xff}).(RGBAColor)
+	want := RGBAColor{h(1), h(float32(0x8080) / 0xffff), 0, h(1)}
+	if got != want {
+		t.Errorf("RGBAModel.Convert(color.RGBA) = %v, want %v", got, want)
+	}
+
+	// Converting an HDR color between the package models keeps its range
+	hdr := RGBAColor{h(4), h(4), h(4), h(1)}
+	if g := GrayModel.Convert(hdr).(GrayColor); g.Y != h(4) {
+		t.Errorf("GrayModel.Convert(HDR) = %v, want 4", g.Y)
+	}
+	if c := RGBAModel.Convert(GrayColor{h(3)}).(RGBAColor); c != (RGBAColor{h(3), h(3), h(3), h(1)}) {
+		t.Errorf("RGBAModel.Convert(GrayColor) = %v", c)
+	}
+
+	// Standard colors follow color.GrayModel
+	for _, c := range []color.Color{color.RGBA{10, 200, 30, 255}, color.Gray16{0x1234}, color.White} {
+		g := GrayModel.Convert(c).(GrayColor)
+		std := color.Gray16Model.Convert(c).(color.Gray16)
+		y, _, _, _ := g.RGBA()
+		if diff := int(y) - int(std.Y); diff < -16 || diff > 16 {
+			t.Errorf("GrayModel.Convert(%v) = %#x, color.Gray16Model gives %#x", c, y, std.Y)
+		}
+	}
+}
+
+func TestRGBA16F(t *testing.T) {
+	img := NewRGBA16F(image.Rect(-2, -1, 3, 4))
+	c := RGBAColor{h(2.5), h(0.5), h(0.25), h(1)}
+	img.SetRGBA(0, 0, c)
+	if got := img.RGBAAt(0, 0); got != c {
+		t.Errorf("RGBAAt(0, 0) = %v, want %v", got, c)
+	}
+	if got := img.RGBAAt(10, 10); got != (RGBAColor{}) {
+		t.Errorf("RGBAAt outside bounds = %v, want zero", got)
+	}
+	img.SetRGBA(10, 10, c) // no-op
+
+	img.Set(1, 2, color.NRGBA{0xff, 0, 0, 0x80})
+	r, _, _, a := img.At(1, 2).RGBA()
+	if r != 0x8080 || a != 0x8080 {
+		t.Errorf("At(1, 2) after Set = r %#x a %#x, want 0x8080 0x8080", r, a)
+	}
+
+	sub := img.SubImage(image.Rect(0, 0, 2, 3)).(*RGBA16F)
+	if got := sub.RGBAAt(0, 0); got != c {
+		t.Errorf("SubImage RGBAAt(0, 0) = %v, want %v", got, c)
+	}
+	sub.SetRGBA(1, 2, c)
+	if got := img.RGBAAt(1, 2); got != c {
+		t.Error("SubImage does not share pixels")
+	}
+	if !img.SubImage(image.Rect(10, 10, 20, 20)).Bounds().Empty() {
+		t.Error("SubImage outside bounds is not empty")
+	}
+
+	if img.Opaque() {
+		t.Error("Opaque() = true for transparent image")
+	}
+	draw.Draw(img, img.Bounds(), image.NewUniform(RGBAColor{h(5), h(5), h(5), h(1)}), image.Point{}, draw.Src)
+	if !img.Opaque() {
+		t.Error("Opaque() = false after filling with opaque color")
+	}
+	if got := img.RGBAAt(-2, -1); got.R != h(5) {
+		t.Errorf("draw.Draw kept %v, want HDR value 5", got.R)
+	}
+}
+
+func TestGray16F(t *testing.T) {
+	img := NewGray16F(image.Rect(0, 0, 4, 3))
+	img.SetGray(3, 2, GrayColor{h(100)})
+	if got := img.GrayAt(3, 2); got.Y != h(100) {
+		t.Errorf("GrayAt(3, 2) = %v, want 100", got.Y)
+	}
+	img.Set(0, 0, color.Gray{0x80})
+	if got := img.GrayAt(0, 0).Y.ToFloat32(); math.Abs(float64(got)-float64(0x80)/0xff) > 1e-3 {
+		t.Errorf("GrayAt(0, 0) after Set = %v, want 0.502", got)
+	}
+
+	sub := img.SubImage(image.Rect(2, 1, 4, 3)).(*Gray16F)
+	if got := sub.GrayAt(3, 2); got.Y != h(100) {
+		t.Errorf("SubImage GrayAt(3, 2) = %v, want 100", got.Y)
+	}
+
+	// Round trip through image/draw to a standard image and back
+	std := image.NewGray16(img.Bounds())
+	draw.Draw(std, std.Bounds(), img, image.Point{}, draw.Src)
+	if std.Gray16At(3, 2).Y != 0xffff {
+		t.Errorf("draw to Gray16 = %#x, want clipped 0xffff", std.Gray16At(3, 2).Y)
+	}
+	back := NewGray16F(img.Bounds())
+	draw.Draw(back, back.Bounds(), std, image.Point{}, draw.Src)
+	if back.GrayAt(3, 2).Y != h(1) {
+		t.Errorf("draw back from Gray16 = %v, want 1", back.GrayAt(3, 2).Y)
+	}
+}
+
+func TestOperators(t *testing.T) {
+	tests := []struct {
+		name string
+		op   Operator
+		x    float32
+		want float32
+	}{
+		{"clip low", Clip, -1, 0},
+		{"clip mid", Clip, 0.25, 0.25},
+		{"clip high", Clip, 7, 1},
+		{"reinhard 1", Reinhard, 1, 0.5},
+		{"reinhard 3", Reinhard, 3, 0.75},
+		{"extended white", ReinhardExtended(4), 4, 1},
+		{"extended above white", ReinhardExtended(4), 100, 1},
+		{"aces 0", ACESFilmic, 0, 0},
+		{"aces 1", ACESFilmic, 1, 2.54 / 3.16},
+		{"aces bright", ACESFilmic, 1000, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.op(tt.x); math.Abs(float64(got-tt.want)) > 1e-4 {
+			t.Errorf("%s: op(%v) = %v, want %v", tt.name, tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestToneMapperMap(t *testing.T) {
+	tests := []struct {
+		name string
+		tm   ToneMapper
+		x    float32
+		want float32
+	}{
+		{"zero value clips", ToneMapper{}, 4, 1},
+		{"exposure", ToneMapper{Exposure: -2}, 2, 0.5},
+		{"gamma", ToneMapper{Gamma: 2}, 0.25, 0.5},
+		{"reinhard exposure", ToneMapper{Exposure: 1, Operator: Reinhard}, 0.5, 0.5},
+		{"nan", ToneMapper{Operator: Reinhard}, float32(math.NaN()), 0},
+		{"negative", ToneMapper{Operator: ACESFilmic}, -1, 0},
+		{"infinity", ToneMapper{Operator: Reinhard, Gamma: 2.2}, float32(math.Inf(1)), 1},
+	}
+	for _, tt := range tests {
+		if got := tt.tm.Map(tt.x); math.Abs(float64(got-tt.want)) > 1e-5 {
+			t.Errorf("%s: Map(%v) = %v, want %v", tt.name, tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestToRGBA(t *testing.T) {
+	src := NewRGBA16F(image.Rect(0, 0, 3, 1))
+	src.SetRGBA(0, 0, RGBAColor{h(1), h(3), h(0), h(1)})
+	src.SetRGBA(1, 0, RGBAColor{h(0.5), h(0.5), h(0.5), h(0.5)}) // premultiplied white at half alpha
+	src.SetRGBA(2, 0, RGBAColor{float16.PositiveInfinity, 0, 0, 0})
+
+	dst := ToneMapper{Operator: Reinhard}.ToRGBA(src)
+	want := []uint8{128, 191, 0, 255, 64, 64, 64, 128, 0, 0, 0, 0}
+	for i, v := range want {
+		if dst.Pix[i] != v {
+			t.Fatalf("ToRGBA() Pix = %v, want %v", dst.Pix, want)
+		}
+	}
+
+	gray := NewGray16F(image.Rect(5, 5, 7, 6))
+	gray.SetGray(5, 5, GrayColor{h(1)})
+	gray.SetGray(6, 5, GrayColor{h(1000)})
+	g := ToneMapper{Operator: Reinhard}.ToRGBA(gray)
+	if g.Bounds() != gray.Bounds() {
+		t.Errorf("ToRGBA() bounds = %v, want %v", g.Bounds(), gray.Bounds())
+	}
+	if c := g.RGBAAt(5, 5); c != (color.RGBA{128, 128, 128, 255}) {
+		t.Errorf("ToRGBA(gray) at (5, 5) = %v", c)
+	}
+	if c := g.RGBAAt(6, 5); c.R != 255 {
+		t.Errorf("ToRGBA(gray) at (6, 5) = %v", c)
+	}
+
+	// Generic images go through color.Color
+	std := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	std.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	if c := (ToneMapper{Operator: Reinhard}).ToRGBA(std).RGBAAt(0, 0); c != (color.RGBA{128, 0, 0, 255}) {
+		t.Errorf("ToRGBA(image.RGBA) = %v", c)
+	}
+}
@@ -0,0 +1,162 @@
+package halfimage
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/zerfoo/float16"
+)
+
+// RGBA16F is an in-memory image of RGBAColor values.
+//
+// Pix holds the samples in R, G, B, A order; the pixel at (x, y) starts at
+// Pix[(y-Rect.Min.Y)*Stride + (x-Rect.Min.X)*4]. Stride counts samples, not
+// bytes.
+type RGBA16F struct {
+	Pix    []float16.Float16
+	Stride int
+	Rect   image.Rectangle
+}
+
+// NewRGBA16F returns a new RGBA16F image with the given bounds
+func NewRGBA16F(r image.Rectangle) *RGBA16F {
+	return &RGBA16F{
+		Pix:    make([]float16.Float16, 4*r.Dx()*r.Dy()),
+		Stride: 4 * r.Dx(),
+		Rect:   r,
+	}
+}
+
+func (p *RGBA16F) ColorModel() color.Model { return RGBAModel }
+
+func (p *RGBA16F) Bounds() image.Rectangle { return p.Rect }
+
+func (p *RGBA16F) At(x, y int) color.Color {
+	return p.RGBAAt(x, y)
+}
+
+// RGBAAt returns the color at (x, y), or the zero color outside the bounds
+func (p *RGBA16F) RGBAAt(x, y int) RGBAColor {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return RGBAColor{}
+	}
+	i := p.PixOffset(x, y)
+	s := p.Pix[i : i+4 : i+4]
+	return RGBAColor{s[0], s[1], s[2], s[3]}
+}
+
+// PixOffset returns the index of the first sample of the pixel at (x, y)
+func (p *RGBA16F) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*4
+}
+
+func (p *RGBA16F) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	p.SetRGBA(x, y, RGBAModel.Convert(c).(RGBAColor))
+}
+
+// SetRGBA sets the color at (x, y)
+func (p *RGBA16F) SetRGBA(x, y int, c RGBAColor) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	i := p.PixOffset(x, y)
+	s := p.Pix[i : i+4 : i+4]
+	s[0], s[1], s[2], s[3] = c.R, c.G, c.B, c.A
+}
+
+// SubImage returns an image representing the portion of p visible through
+// r. The returned image shares pixels with p.
+func (p *RGBA16F) SubImage(r image.Rectangle) image.Image {
+	r = r.Intersect(p.Rect)
+	if r.Empty() {
+		return &RGBA16F{}
+	}
+	i := p.PixOffset(r.Min.X, r.Min.Y)
+	return &RGBA16F{Pix: p.Pix[i:], Stride: p.Stride, Rect: r}
+}
+
+// Opaque reports whether every pixel has an alpha of at least 1
+func (p *RGBA16F) Opaque() bool {
+	one := float16.FromFloat32(1)
+	for y := p.Rect.Min.Y; y < p.Rect.Max.Y; y++ {
+		i := p.PixOffset(p.Rect.Min.X, y)
+		for x := 0; x < p.Rect.Dx(); x++ {
+			if !float16.GreaterEqual(p.Pix[i+4*x+3], one) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Gray16F is an in-memory image of GrayColor values.
+//
+// The pixel at (x, y) is Pix[(y-Rect.Min.Y)*Stride + (x-Rect.Min.X)].
+type Gray16F struct {
+	Pix    []float16.Float16
+	Stride int
+	Rect   image.Rectangle
+}
+
+// NewGray16F returns a new Gray16F image with the given bounds
+func NewGray16F(r image.Rectangle) *Gray16F {
+	return &Gray16F{
+		Pix:    make([]float16.Float16, r.Dx()*r.Dy()),
+		Stride: r.Dx(),
+		Rect:   r,
+	}
+}
+
+func (p *Gray16F) ColorModel() color.Model { return GrayModel }
+
+func (p *Gray16F) Bounds() image.Rectangle { return p.Rect }
+
+func (p *Gray16F) At(x, y int) color.Color {
+	return p.GrayAt(x, y)
+}
+
+// GrayAt returns the gray level at (x, y), or zero outside the bounds
+func (p *Gray16F) GrayAt(x, y int) GrayColor {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return GrayColor{}
+	}
+	return GrayColor{p.Pix[p.PixOffset(x, y)]}
+}
+
+// PixOffset returns the index of the pixel at (x, y)
+func (p *Gray16F) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x - p.Rect.Min.X)
+}
+
+func (p *Gray16F) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	p.Pix[p.PixOffset(x, y)] = GrayModel.Convert(c).(GrayColor).Y
+}
+
+// SetGray sets the gray level at (x, y)
+func (p *Gray16F) SetGray(x, y int, c GrayColor) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	p.Pix[p.PixOffset(x, y)] = c.Y
+}
+
+// SubImage returns an image representing the portion of p visible through
+// r. The returned image shares pixels with p.
+func (p *Gray16F) SubImage(r image.Rectangle) image.Image {
+	r = r.Intersect(p.Rect)
+	if r.Empty() {
+		return &Gray16F{}
+	}
+	return &Gray16F{Pix: p.Pix[p.PixOffset(r.Min.X, r.Min.Y):], Stride: p.Stride, Rect: r}
+}
+
+// Opaque always returns true
+func (p *Gray16F) Opaque() bool {
+	return true
+}
@@ -0,0 +1,124 @@
+package halfimage
+
+import (
+	"image"
+	"math"
+)
+
+// Operator is a tone curve mapping a linear, non-negative scene value to a
+// display value in [0, 1]
+type Operator func(x float32) float32
+
+// Clip maps values by clipping them to [0, 1]. Combined with exposure and
+// gamma it gives the plain exposure/gamma transform.
+func Clip(x float32) float32 {
+	return min(max(x, 0), 1)
+}
+
+// Reinhard is the global Reinhard operator x / (1 + x)
+func Reinhard(x float32) float32 {
+	return x / (1 + x)
+}
+
+// ReinhardExtended returns the extended Reinhard operator, which maps white
+// and everything brighter to 1: x (1 + x/white²) / (1 + x)
+func ReinhardExtended(white float32) Operator {
+	w2 := white * white
+	return func(x float32) float32 {
+		return min(x*(1+x/w2)/(1+x), 1)
+	}
+}
+
+// ACESFilmic is Krzysztof Narkowicz's fit of the ACES filmic reference
+// rendering transform: x (2.51x + 0.03) / (x (2.43x + 0.59) + 0.14)
+func ACESFilmic(x float32) float32 {
+	return Clip(x * (2.51*x + 0.03) / (x*(2.43*x+0.59) + 0.14))
+}
+
+// ToneMapper converts high dynamic range images to 8-bit images.
+//
+// Each color channel is scaled by 2^Exposure, passed through Operator and
+// encoded with the power 1/Gamma. Alpha is clipped to [0, 1] and left
+// linear. The zero ToneMapper clips values with no exposure change or gamma.
+type ToneMapper struct {
+	// Exposure is the exposure adjustment in stops
+	Exposure float32
+	// Gamma is the display gamma, such as 2.2; values <= 0 mean 1
+	Gamma float32
+	// Operator is the tone curve; nil means Clip
+	Operator Operator
+}
+
+// Map tone maps a single linear value. NaN and negative values map to 0,
+// and +Inf maps to 1.
+func (tm ToneMapper) Map(x float32) float32 {
+	if !(x > 0) {
+		return 0
+	}
+	if tm.Exposure != 0 {
+		x *= float32(math.Exp2(float64(tm.Exposure)))
+	}
+	if math.IsInf(float64(x), 1) {
+		return 1
+	}
+	op := tm.Operator
+	if op == nil {
+		op = Clip
+	}
+	y := Clip(op(x))
+	if tm.Gamma > 0 && tm.Gamma != 1 {
+		y = float32(math.Pow(float64(y), 1/float64(tm.Gamma)))
+	}
+	return y
+}
+
+// to8 scales a value in [0, 1] to 8 bits
+func to8(v float32) uint8 {
+	return uint8(v*255 + 0.5)
+}
+
+// mapPixel tone maps a premultiplied color to a premultiplied 8-bit pixel
+func (tm ToneMapper) mapPixel(dst []uint8, r, g, b, a float32) {
+	alpha := Clip(a)
+	if !(alpha > 0) {
+		dst[0], dst[1], dst[2], dst[3] = 0, 0, 0, 0
+		return
+	}
+	// Tone map the straight color, then premultiply again
+	dst[0] = to8(tm.Map(r/a) * alpha)
+	dst[1] = to8(tm.Map(g/a) * alpha)
+	dst[2] = to8(tm.Map(b/a) * alpha)
+	dst[3] = to8(alpha)
+}
+
+// ToRGBA tone maps src into a new image.RGBA with the same bounds.
+// RGBA16F and Gray16F sources are read at full range; other images are read
+// through their color.Color values, which are already clipped to [0, 1].
+func (tm ToneMapper) ToRGBA(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	dst := image.NewRGBA(b)
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := dst.Pix[dst.PixOffset(b.Min.X, y):]
+		switch s := src.(type) {
+		case *Gray16F:
+			line := s.Pix[s.PixOffset(b.Min.X, y):]
+			for x := 0; x < b.Dx(); x++ {
+				v := to8(tm.Map(line[x].ToFloat32()))
+				row[4*x], row[4*x+1], row[4*x+2], row[4*x+3] = v, v, v, 0xff
+			}
+		case *RGBA16F:
+			line := s.Pix[s.PixOffset(b.Min.X, y):]
+			for x := 0; x < b.Dx(); x++ {
+				p := line[4*x : 4*x+4]
+				tm.mapPixel(row[4*x:4*x+4], p[0].ToFloat32(), p[1].ToFloat32(), p[2].ToFloat32(), p[3].ToFloat32())
+			}
+		default:
+			for x := 0; x < b.Dx(); x++ {
+				r, g, bl, a := src.At(b.Min.X+x, y).RGBA()
+				tm.mapPixel(row[4*x:4*x+4], float32(r)/0xffff, float32(g)/0xffff, float32(bl)/0xffff, float32(a)/0xffff)
+			}
+		}
+	}
+	return dst
+}
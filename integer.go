@@ -0,0 +1,274 @@
+package float16
+
+import "math"
+
+// Conversions between Float16 and the integer types. Every integer of
+// magnitude up to 2048 is exact in half precision; larger ones round to a
+// multiple of 2, 4, ... 32 and overflow from 65520.
+
+// FromInt64 converts i to the nearest Float16, ties to even, or to ±Inf
+// if |i| rounds beyond MaxValue
+func FromInt64(i int64) Float16 {
+	// float64 is exact wherever the result is finite
+	return FromFloat64(float64(i))
+}
+
+// FromInt32 converts i to the nearest Float16; see FromInt64
+func FromInt32(i int32) Float16 { return FromInt64(int64(i)) }
+
+// FromInt16 converts i to the nearest Float16; see FromInt64
+func FromInt16(i int16) Float16 { return FromInt64(int64(i)) }
+
+// FromInt8 converts i exactly to Float16
+func FromInt8(i int8) Float16 { return FromInt64(int64(i)) }
+
+// FromInt converts i to the nearest Float16; see FromInt64
+func FromInt(i int) Float16 { return FromInt64(int64(i)) }
+
+// FromUint64 converts u to the nearest Float16, ties to even, or to +Inf
+// if u rounds beyond MaxValue
+func FromUint64(u uint64) Float16 {
+	return FromFloat64(float64(u))
+}
+
+// FromUint32 converts u to the nearest Float16; see FromUint64
+func FromUint32(u uint32) Float16 { return FromUint64(uint64(u)) }
+
+// FromUint16 converts u to the nearest Float16; see FromUint64
+func FromUint16(u uint16) Float16 { return FromUint64(uint64(u)) }
+
+// FromUint8 converts u exactly to Float16
+func FromUint8(u uint8) Float16 { return FromUint64(uint64(u)) }
+
+// FromUint converts u to the nearest Float16; see FromUint64
+func FromUint(u uint) Float16 { return FromUint64(uint64(u)) }
+
+// FromInt64WithMode converts i to Float16 with the given conversion and
+// rounding modes, reporting overflow and inexact results as
+// FromFloat64WithMode does
+func FromInt64WithMode(i int64, convMode ConversionMode, rounding RoundingMode) (Float16, error) {
+	return FromFloat64WithMode(float64(i), convMode, rounding)
+}
+
+// FromUint64WithMode converts u to Float16 with the given conversion and
+// rounding modes; see FromInt64WithMode
+func FromUint64WithMode(u uint64, convMode ConversionMode, rounding RoundingMode) (Float16, error) {
+	return FromFloat64WithMode(float64(u), convMode, rounding)
+}
+
+// OverflowMode selects what a conversion to an integer type does with
+// values outside the range of the type, and with NaN
+type OverflowMode int
+
+const (
+	// OverflowSaturate clamps to the range of the type, so ±Inf give its
+	// minimum and maximum, and converts NaN to 0, as ARM FCVTZS and CUDA's
+	// saturating cvt instructions do
+	OverflowSaturate OverflowMode = iota
+	// OverflowError returns a *Float16Error, ErrOverflow for a finite value
+	// and ErrInfinity or ErrNaN otherwise, with the saturated result
+	OverflowError
+	// OverflowWrap reduces the rounded value modulo 2^n for an n-bit type,
+	// as Go's integer conversions do; NaN and ±Inf, which have no residue,
+	// give 0
+	OverflowWrap
+)
+
+// ToInt64 converts f to an integer, truncating toward zero like Go's
+// conversions. ±Inf give the minimum and maximum and NaN gives 0; use
+// ToInt64WithMode to choose the rounding and overflow handling.
+func (f Float16) ToInt64() int64 {
+	v, _ := f.ToInt64WithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToInt32 converts f to an int32, truncating toward zero and saturating;
+// see ToInt64
+func (f Float16) ToInt32() int32 {
+	v, _ := f.ToInt32WithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToInt16 converts f to an int16, truncating toward zero and saturating;
+// see ToInt64
+func (f Float16) ToInt16() int16 {
+	v, _ := f.ToInt16WithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToInt8 converts f to an int8, truncating toward zero and saturating;
+// see ToInt64
+func (f Float16) ToInt8() int8 {
+	v, _ := f.ToInt8WithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToInt converts f to an int, truncating toward zero and saturating; see
+// ToInt64
+func (f Float16) ToInt() int {
+	v, _ := f.ToIntWithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToUint64 converts f to a uint64, truncating toward zero and saturating,
+// so negative values give 0; see ToInt64
+func (f Float16) ToUint64() uint64 {
+	v, _ := f.ToUint64WithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToUint32 converts f to a uint32, truncating toward zero and saturating;
+// see ToUint64
+func (f Float16) ToUint32() uint32 {
+	v, _ := f.ToUint32WithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToUint16 converts f to a uint16, truncating toward zero and saturating;
+// see ToUint64
+func (f Float16) ToUint16() uint16 {
+	v, _ := f.ToUint16WithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToUint8 converts f to a uint8, truncating toward zero and saturating;
+// see ToUint64
+func (f Float16) ToUint8() uint8 {
+	v, _ := f.ToUint8WithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToUint converts f to a uint, truncating toward zero and saturating; see
+// ToUint64
+func (f Float16) ToUint() uint {
+	v, _ := f.ToUintWithMode(RoundTowardZero, OverflowSaturate)
+	return v
+}
+
+// ToInt64WithMode rounds f to an integer in the given rounding mode. No
+// finite Float16 overflows int64; see OverflowMode for ±Inf and NaN.
+func (f Float16) ToInt64WithMode(rounding RoundingMode, overflow OverflowMode) (int64, error) {
+	return f.toInteger("int64", math.MinInt64, math.MaxInt64, rounding, overflow)
+}
+
+// ToInt32WithMode rounds f to an int32 in the given rounding mode; see
+// ToInt64WithMode
+func (f Float16) ToInt32WithMode(rounding RoundingMode, overflow OverflowMode) (int32, error) {
+	v, err := f.toInteger("int32", math.MinInt32, math.MaxInt32, rounding, overflow)
+	return int32(v), err
+}
+
+// ToInt16WithMode rounds f to an int16 in the given rounding mode,
+// handling values outside [-32768, 32767] as overflow selects
+func (f Float16) ToInt16WithMode(rounding RoundingMode, overflow OverflowMode) (int16, error) {
+	v, err := f.toInteger("int16", math.MinInt16, math.MaxInt16, rounding, overflow)
+	return int16(v), err
+}
+
+// ToInt8WithMode rounds f to an int8 in the given rounding mode, handling
+// values outside [-128, 127] as overflow selects
+func (f Float16) ToInt8WithMode(rounding RoundingMode, overflow OverflowMode) (int8, error) {
+	v, err := f.toInteger("int8", math.MinInt8, math.MaxInt8, rounding, overflow)
+	return int8(v), err
+}
+
+// ToIntWithMode rounds f to an int in the given rounding mode; see
+// ToInt64WithMode
+func (f Float16) ToIntWithMode(rounding RoundingMode, overflow OverflowMode) (int, error) {
+	v, err := f.toInteger("int", math.MinInt, math.MaxInt, rounding, overflow)
+	return int(v), err
+}
+
+// ToUint64WithMode rounds f to a uint64 in the given rounding mode,
+// handling negative values as overflow selects
+func (f Float16) ToUint64WithMode(rounding RoundingMode, overflow OverflowMode) (uint64, error) {
+	// Every finite Float16 is below math.MaxInt64, which stands in for the
+	// maximum until +Inf saturates
+	v, err := f.toInteger("uint64", 0, math.MaxInt64, rounding, overflow)
+	if f.IsInf(1) && overflow != OverflowWrap {
+		return math.MaxUint64, err
+	}
+	return uint64(v), err
+}
+
+// ToUint32WithMode rounds f to a uint32 in the given rounding mode,
+// handling negative values as overflow selects
+func (f Float16) ToUint32WithMode(rounding RoundingMode, overflow OverflowMode) (uint32, error) {
+	v, err := f.toInteger("uint32", 0, math.MaxUint32, rounding, overflow)
+	return uint32(v), err
+}
+
+// ToUint16WithMode rounds f to a uint16 in the given rounding mode,
+// handling negative values as overflow selects; every other finite Float16
+// fits
+func (f Float16) ToUint16WithMode(rounding RoundingMode, overflow OverflowMode) (uint16, error) {
+	v, err := f.toInteger("uint16", 0, math.MaxUint16, rounding, overflow)
+	return uint16(v), err
+}
+
+// ToUint8WithMode rounds f to a uint8 in the given rounding mode, handling
+// values outside [0, 255] as overflow selects
+func (f Float16) ToUint8WithMode(rounding RoundingMode, overflow OverflowMode) (uint8, error) {
+	v, err := f.toInteger("uint8", 0, math.MaxUint8, rounding, overflow)
+	return uint8(v), err
+}
+
+// ToUintWithMode rounds f to a uint in the given rounding mode, handling
+// negative values as overflow selects
+func (f Float16) ToUintWithMode(rounding RoundingMode, overflow OverflowMode) (uint, error) {
+	v, err := f.toInteger("uint", 0, math.MaxInt64, rounding, overflow)
+	if f.IsInf(1) && overflow != OverflowWrap {
+		return math.MaxUint, err
+	}
+	return uint(v), err
+}
+
+// toInteger rounds f to an integer and applies overflow for a target type
+// named typ with range [lo, hi]. In wrap mode the result is left for the
+// caller's conversion to the target type to reduce.
+func (f Float16) toInteger(typ string, lo, hi int64, rounding RoundingMode, overflow OverflowMode) (int64, error) {
+	if f.IsNaN() {
+		if overflow == OverflowError {
+			return 0, &Float16Error{Op: "convert", Value: f, Msg: "NaN has no " + typ + " value", Code: ErrNaN}
+		}
+		return 0, nil
+	}
+	if f.IsInf(0) {
+		v := hi
+		if f.Signbit() {
+			v = lo
+		}
+		switch overflow {
+		case OverflowError:
+			return v, &Float16Error{Op: "convert", Value: f, Msg: "infinity out of range for " + typ, Code: ErrInfinity}
+		case OverflowWrap:
+			return 0, nil
+		}
+		return v, nil
+	}
+
+	// Every finite Float16 is exact in float64, as is its rounded value
+	x := f.ToFloat64()
+	switch rounding {
+	case RoundNearestEven:
+		x = math.RoundToEven(x)
+	case RoundNearestAway:
+		x = math.Round(x)
+	case RoundTowardZero:
+		x = math.Trunc(x)
+	case RoundTowardPositive:
+		x = math.Ceil(x)
+	case RoundTowardNegative:
+		x = math.Floor(x)
+	}
+	v := int64(x)
+	if (v >= lo && v <= hi) || overflow == OverflowWrap {
+		return v, nil
+	}
+
+	clamped := max(lo, min(v, hi))
+	if overflow == OverflowError {
+		return clamped, &Float16Error{Op: "convert", Value: f, Msg: "value out of range for " + typ, Code: ErrOverflow}
+	}
+	return clamped, nil
+}
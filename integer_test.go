@@ -0,0 +1,184 @@
+package float16
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFromIntRounding(t *testing.T) {
+	// Every result is the nearest Float16, ties to even, or an infinity
+	for i := int64(-70000); i <= 70000; i++ {
+		got := FromInt64(i)
+		if math.Abs(float64(i)) >= 65520 {
+			if !got.IsInf(int(math.Copysign(1, float64(i)))) {
+				t.Fatalf("FromInt64(%d) = %v, want infinity", i, got)
+			}
+			continue
+		}
+		v := got.ToFloat64()
+		for _, n := range []Float16{NextAfter(got, PositiveInfinity), NextAfter(got, NegativeInfinity)} {
+			d, dn := math.Abs(v-float64(i)), math.Abs(n.ToFloat64()-float64(i))
+			if dn < d || (dn == d && got&1 == 1) {
+				t.Fatalf("FromInt64(%d) = %v, but %v is nearer", i, got, n)
+			}
+		}
+		if i >= math.MinInt32 && i <= math.MaxInt32 && FromInt32(int32(i)) != got || FromInt(int(i)) != got {
+			t.Fatalf("FromInt32/FromInt(%d) disagree with FromInt64", i)
+		}
+		if i >= 0 && FromUint64(uint64(i)) != got {
+			t.Fatalf("FromUint64(%d) = %v, want %v", i, FromUint64(uint64(i)), got)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		got, want Float16
+	}{
+		{"FromInt8(-128)", FromInt8(-128), FromFloat32(-128)},
+		{"FromUint8(255)", FromUint8(255), FromFloat32(255)},
+		{"FromInt16(2049)", FromInt16(2049), FromFloat32(2048)},
+		{"FromInt16(2051)", FromInt16(2051), FromFloat32(2052)},
+		{"FromUint16(65504)", FromUint16(65504), MaxValue},
+		{"FromUint16(65535)", FromUint16(65535), PositiveInfinity},
+		{"FromUint32(65519)", FromUint32(65519), MaxValue},
+		{"FromUint(math.MaxUint)", FromUint(math.MaxUint), PositiveInfinity},
+		{"FromInt64(math.MinInt64)", FromInt64(math.MinInt64), NegativeInfinity},
+		{"FromUint64(1<<63+1)", FromUint64(1<<63 + 1), PositiveInfinity},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	if got, err := FromInt64WithMode(2049, ModeExact, RoundTowardPositive); got != FromFloat32(2050) || err == nil {
+		t.Errorf("FromInt64WithMode(2049, exact, up) = %v, %v, want 2050 and an error", got, err)
+	}
+	if got, err := FromUint64WithMode(1<<20, ModeStrict, RoundTowardZero); got != MaxValue || err == nil {
+		t.Errorf("FromUint64WithMode(1<<20, strict, toward zero) = %v, %v, want MaxValue and an error", got, err)
+	}
+}
+
+func TestToIntRounding(t *testing.T) {
+	rounders := map[RoundingMode]func(Float16) Float16{
+		RoundNearestEven:    RoundToEven,
+		RoundNearestAway:    Round,
+		RoundTowardZero:     Trunc,
+		RoundTowardPositive: Ceil,
+		RoundTowardNegative: Floor,
+	}
+	for rounding, round := range rounders {
+		for b := 0; b < 1<<16; b++ {
+			f := FromBits(uint16(b))
+			if !f.IsFinite() {
+				continue
+			}
+			want := int64(round(f).ToFloat64())
+			if got, err := f.ToInt64WithMode(rounding, OverflowError); got != want || err != nil {
+				t.Fatalf("ToInt64WithMode(%v, %v) = %d, %v, want %d", f, rounding, got, err, want)
+			}
+			if got, _ := f.ToInt16WithMode(rounding, OverflowSaturate); int64(got) != max(math.MinInt16, min(want, math.MaxInt16)) {
+				t.Fatalf("ToInt16WithMode(%v, %v, saturate) = %d, want %d", f, rounding, got, want)
+			}
+			if got, _ := f.ToUint8WithMode(rounding, OverflowWrap); got != uint8(want) {
+				t.Fatalf("ToUint8WithMode(%v, %v, wrap) = %d, want %d", f, rounding, got, uint8(want))
+			}
+		}
+	}
+}
+
+// widen adapts an integer conversion method to a common signature
+func widen[T int8 | int16 | int32 | int64 | int | uint8 | uint16 | uint32 | uint64 | uint](conv func(Float16, RoundingMode, OverflowMode) (T, error)) func(Float16, OverflowMode) (int64, error) {
+	return func(f Float16, m OverflowMode) (int64, error) {
+		v, err := conv(f, RoundNearestEven, m)
+		return int64(v), err
+	}
+}
+
+func TestToIntOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		conv    func(Float16, OverflowMode) (int64, error)
+		f       Float16
+		wrap    int64
+		sat     int64
+		errCode ErrorCode
+		errs    bool
+	}{
+		{"ToInt8(300)", widen(Float16.ToInt8WithMode), FromFloat32(300), 44, 127, ErrOverflow, true},
+		{"ToInt8(-129)", widen(Float16.ToInt8WithMode), FromFloat32(-129), 127, -128, ErrOverflow, true},
+		{"ToUint8(-129)", widen(Float16.ToUint8WithMode), FromFloat32(-129), 127, 0, ErrOverflow, true},
+		{"ToInt16(MaxValue)", widen(Float16.ToInt16WithMode), MaxValue, -32, 32767, ErrOverflow, true},
+		{"ToUint16(MaxValue)", widen(Float16.ToUint16WithMode), MaxValue, 65504, 65504, 0, false},
+		{"ToInt32(+Inf)", widen(Float16.ToInt32WithMode), PositiveInfinity, 0, math.MaxInt32, ErrInfinity, true},
+		{"ToInt64(-Inf)", widen(Float16.ToInt64WithMode), NegativeInfinity, 0, math.MinInt64, ErrInfinity, true},
+		{"ToUint32(-Inf)", widen(Float16.ToUint32WithMode), NegativeInfinity, 0, 0, ErrInfinity, true},
+		{"ToInt8(NaN)", widen(Float16.ToInt8WithMode), QuietNaN, 0, 0, ErrNaN, true},
+		{"ToUint(-NaN)", widen(Float16.ToUintWithMode), NegativeQNaN, 0, 0, ErrNaN, true},
+		{"ToInt(-0.5)", widen(Float16.ToIntWithMode), FromFloat32(-0.5), 0, 0, 0, false},
+		{"ToUint64(-0.5)", widen(Float16.ToUint64WithMode), FromFloat32(-0.5), 0, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got, err := tt.conv(tt.f, OverflowWrap); got != tt.wrap || err != nil {
+			t.Errorf("%s wrap = %d, %v, want %d", tt.name, got, err, tt.wrap)
+		}
+		if got, err := tt.conv(tt.f, OverflowSaturate); got != tt.sat || err != nil {
+			t.Errorf("%s saturate = %d, %v, want %d", tt.name, got, err, tt.sat)
+		}
+		got, err := tt.conv(tt.f, OverflowError)
+		if got != tt.sat {
+			t.Errorf("%s error mode = %d, want %d", tt.name, got, tt.sat)
+		}
+		e, ok := err.(*Float16Error)
+		if tt.errs != (err != nil) || (err != nil && (!ok || e.Code != tt.errCode)) {
+			t.Errorf("%s error mode: error %v, want code %v: %v", tt.name, err, tt.errCode, tt.errs)
+		}
+	}
+
+	// ToUint64 of -1 wraps like Go's conversion
+	if got, _ := FromFloat32(-1).ToUint64WithMode(RoundNearestEven, OverflowWrap); got != math.MaxUint64 {
+		t.Errorf("ToUint64WithMode(-1, wrap) = %d, want %d", got, uint64(math.MaxUint64))
+	}
+	if got, _ := PositiveInfinity.ToUint64WithMode(RoundNearestEven, OverflowSaturate); got != math.MaxUint64 {
+		t.Errorf("ToUint64WithMode(+Inf, saturate) = %d, want %d", got, uint64(math.MaxUint64))
+	}
+	if got, err := PositiveInfinity.ToUintWithMode(RoundNearestEven, OverflowError); got != math.MaxUint || err == nil {
+		t.Errorf("ToUintWithMode(+Inf, error) = %d, %v, want %d and an error", got, err, uint(math.MaxUint))
+	}
+}
+
+func TestToIntTruncate(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want int64
+	}{
+		{"ToInt8(-1.9)", int64(FromFloat32(-1.9).ToInt8()), -1},
+		{"ToInt8(300)", int64(FromFloat32(300).ToInt8()), 127},
+		{"ToInt16(-Inf)", int64(NegativeInfinity.ToInt16()), math.MinInt16},
+		{"ToInt64(NaN)", QuietNaN.ToInt64(), 0},
+		{"ToUint8(255.9)", int64(FromFloat32(255.9).ToUint8()), 255},
+		{"ToUint16(MaxValue)", int64(MaxValue.ToUint16()), 65504},
+		{"ToUint32(-3.5)", int64(FromFloat32(-3.5).ToUint32()), 0},
+		{"ToUint(0.99)", int64(FromFloat32(0.99).ToUint()), 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+	if got := PositiveInfinity.ToUint64(); got != math.MaxUint64 {
+		t.Errorf("ToUint64(+Inf) = %d, want %d", got, uint64(math.MaxUint64))
+	}
+}
+
+func BenchmarkToInt8Saturate(b *testing.B) {
+	s := benchmarkScores(4096)
+	q := make([]int8, len(s))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, f := range s {
+			q[j], _ = f.ToInt8WithMode(RoundNearestEven, OverflowSaturate)
+		}
+	}
+}
@@ -0,0 +1,453 @@
+// Package accuracy measures half-precision functions exhaustively: every
+// one of the 65536 Float16 inputs is evaluated and compared with the
+// correctly rounded result of a math/big reference. The root package's
+// accuracy tests use it to enforce error thresholds and to publish the
+// accuracy table in ACCURACY.md.
+//
+// The references come from internal/bigmath, which the float16 package also
+// uses to round its hardest cases. So that a fault there cannot go unnoticed
+// by agreeing with itself, every reference value is also checked against the
+// independent float64 implementation of the function.
+package accuracy
+
+import (
+	"cmp"
+	"math"
+	"math/big"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/zerfoo/float16"
+)
+
+// Prec is the precision in bits of the reference values. Any result of a
+// half-precision function is either exactly a Float16 value or rounding
+// midpoint, or lies much further than 2^-Prec from one, so Prec bits
+// decide the rounding in every mode.
+const Prec = 128
+
+// snapBits sets how close, in fractions of a half-precision ulp, a
+// reference value must be to a Float16 value or rounding midpoint to be
+// treated as exactly equal to it. Reference errors are near 2^-Prec; no
+// inexact result of the measured functions comes closer than about 2^-40.
+const snapBits = 80
+
+// Func describes a function of one or two Float16 arguments together with
+// its reference
+type Func struct {
+	Name string
+
+	// Ys lists the second arguments a binary function is measured with;
+	// every Float16 value is used as the first argument. Ys is nil for
+	// unary functions.
+	Ys []float16.Float16
+
+	// F is the function under test
+	F func(x, y float16.Float16, mode float16.RoundingMode) float16.Float16
+
+	// Rounded reports whether F honours its rounding mode, so that it can
+	// be measured in every mode rather than only in round to nearest even
+	Rounded bool
+
+	// Float is the float64 counterpart of F. It provides the result for
+	// non-finite arguments and domain errors (a NaN result), and the exact
+	// result where Big returns nil.
+	Float func(x, y float64) float64
+
+	// Big computes the reference for finite arguments, or returns nil if
+	// Float's result is exact, such as at poles
+	Big func(x, y *big.Float) *big.Float
+}
+
+// Unary returns a Func for a function of one argument measured in round to
+// nearest even
+func Unary(name string, f func(float16.Float16) float16.Float16, float func(float64) float64, ref func(*big.Float, uint) *big.Float) Func {
+	fn := UnaryRounded(name, func(x float16.Float16, _ float16.RoundingMode) float16.Float16 { return f(x) }, float, ref)
+	fn.Rounded = false
+	return fn
+}
+
+// UnaryRounded returns a Func for a function of one argument that takes a
+// rounding mode
+func UnaryRounded(name string, f func(float16.Float16, float16.RoundingMode) float16.Float16, float func(float64) float64, ref func(*big.Float, uint) *big.Float) Func {
+	fn := Func{
+		Name:    name,
+		F:       func(x, _ float16.Float16, mode float16.RoundingMode) float16.Float16 { return f(x, mode) },
+		Rounded: true,
+		Float:   func(x, _ float64) float64 { return float(x) },
+	}
+	if ref != nil {
+		fn.Big = func(x, _ *big.Float) *big.Float { return ref(x, Prec) }
+	}
+	return fn
+}
+
+// Binary returns a Func for a function of two arguments measured in round
+// to nearest even with the given second arguments
+func Binary(name string, f func(x, y float16.Float16) float16.Float16, ys []float16.Float16, float func(x, y float64) float64, ref func(x, y *big.Float, prec uint) *big.Float) Func {
+	fn := BinaryRounded(name, func(x, y float16.Float16, _ float16.RoundingMode) float16.Float16 { return f(x, y) }, ys, float, ref)
+	fn.Rounded = false
+	return fn
+}
+
+// BinaryRounded returns a Func for a function of two arguments that takes a
+// rounding mode, measured with the given second arguments
+func BinaryRounded(name string, f func(x, y float16.Float16, mode float16.RoundingMode) float16.Float16, ys []float16.Float16, float func(x, y float64) float64, ref func(x, y *big.Float, prec uint) *big.Float) Func {
+	fn := Func{
+		Name:    name,
+		Ys:      ys,
+		F:       f,
+		Rounded: true,
+		Float:   float,
+	}
+	if ref != nil {
+		fn.Big = func(x, y *big.Float) *big.Float { return ref(x, y, Prec) }
+	}
+	return fn
+}
+
+// Case is one evaluation of a function
+type Case struct {
+	X, Y float16.Float16 // Y is zero for unary functions
+	Got  float16.Float16
+	Want float16.Float16 // correctly rounded result
+	ULP  float64         // error of Got in units in the last place
+
+	binary bool
+}
+
+// Report summarizes the accuracy of a function in one rounding mode
+type Report struct {
+	Name   string
+	Mode   float16.RoundingMode
+	Inputs int
+
+	// Disagreements counts inputs where the math/big reference and the
+	// float64 function differ by more than RefTolerance ulp, which means
+	// that one of them is wrong. Disagree holds the first few, with Got the
+	// float64 value and Want the reference, each rounded to Float16, and
+	// ULP their difference.
+	Disagreements int
+	Disagree      []Case
+
+	// Incorrect counts results that differ from the correctly rounded
+	// value. Zeros of either sign compare equal, as do all NaNs.
+	Incorrect int
+
+	// MaxULP is the largest error, measured against the exact value in
+	// units of the Float16 spacing at that value. A correctly rounded
+	// function has MaxULP <= 0.5 in the round-to-nearest modes and < 1 in
+	// the directed ones. Returning NaN for a number, or a wrong infinity,
+	// counts as an infinite error.
+	MaxULP float64
+
+	// Worst holds the inputs with the largest errors, largest first
+	Worst []Case
+}
+
+// worstCases is the number of cases kept in Report.Worst
+const worstCases = 5
+
+// RefTolerance is the largest difference, in Float16 ulps, allowed between
+// a math/big reference and the float64 function it is checked against. The
+// float64 functions are accurate to a few float64 ulps, 2^-42 of a Float16
+// ulp, so only a genuine fault in either exceeds it.
+const RefTolerance = 0x1p-20
+
+// reference is the exact value of a function at one input, either a
+// finite big.Float or a float64 for non-finite results
+type reference struct {
+	big   *big.Float // nil for non-finite results
+	float float64
+
+	// fromBig reports whether big came from Func.Big rather than Func.Float,
+	// in which case float holds Func.Float's value to check it against
+	fromBig bool
+}
+
+// Measure evaluates f on every input and reports its accuracy in each of
+// the given rounding modes, or in round to nearest even if none are given.
+// The reference values are computed once for all modes.
+func Measure(f Func, modes ...float16.RoundingMode) []Report {
+	return MeasureSample(f, 1, modes...)
+}
+
+// MeasureSample is like Measure, but evaluates f only on every step-th
+// Float16 bit pattern and on the patterns whose low 9 bits are zero, which
+// include the zeros, infinities, the default NaN and every power of two.
+// Binary functions are still paired with each of their second arguments.
+func MeasureSample(f Func, step int, modes ...float16.RoundingMode) []Report {
+	if len(modes) == 0 {
+		modes = []float16.RoundingMode{float16.RoundNearestEven}
+	}
+	ys := f.Ys
+	if ys == nil {
+		ys = []float16.Float16{0}
+	}
+	var xs []float16.Float16
+	for b := range 65536 {
+		if b%step == 0 || b&0x1FF == 0 {
+			xs = append(xs, float16.FromBits(uint16(b)))
+		}
+	}
+	n := len(xs) * len(ys)
+	input := func(i int) (x, y float16.Float16) {
+		return xs[i%len(xs)], ys[i/len(xs)]
+	}
+
+	refs := make([]reference, n)
+	workers := runtime.GOMAXPROCS(0)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			for i := w; i < n; i += workers {
+				refs[i] = f.reference(input(i))
+			}
+		})
+	}
+	wg.Wait()
+
+	var disagreements int
+	var disagree []Case
+	for i, ref := range refs {
+		if !ref.fromBig {
+			continue
+		}
+		if c, ok := ref.check(); !ok {
+			disagreements++
+			if len(disagree) < worstCases {
+				c.X, c.Y = input(i)
+				c.binary = f.Ys != nil
+				disagree = append(disagree, c)
+			}
+		}
+	}
+
+	reports := make([]Report, len(modes))
+	for m, mode := range modes {
+		r := Report{Name: f.Name, Mode: mode, Inputs: n, Disagreements: disagreements, Disagree: disagree}
+		for i, ref := range refs {
+			x, y := input(i)
+			c := Case{X: x, Y: y, Got: f.F(x, y, mode), binary: f.Ys != nil}
+			c.Want, c.ULP = ref.compare(c.Got, mode)
+			if !same(c.Got, c.Want) {
+				r.Incorrect++
+			}
+			r.MaxULP = max(r.MaxULP, c.ULP)
+			if c.ULP > 0 {
+				r.Worst = keepWorst(r.Worst, c)
+			}
+		}
+		reports[m] = r
+	}
+	return reports
+}
+
+// reference returns the exact value of f at (x, y)
+func (f Func) reference(x, y float16.Float16) reference {
+	xf, yf := x.ToFloat64(), y.ToFloat64()
+	v := f.Float(xf, yf)
+	finite := !x.IsNaN() && !x.IsInf(0) && !y.IsNaN() && !y.IsInf(0)
+	if !finite || math.IsNaN(v) || f.Big == nil {
+		return floatReference(v)
+	}
+	b := f.Big(new(big.Float).SetFloat64(xf), new(big.Float).SetFloat64(yf))
+	if b == nil {
+		return floatReference(v)
+	}
+	return reference{big: snap(b), float: v, fromBig: true}
+}
+
+// check compares a math/big reference with the float64 function's value,
+// reporting false if they differ by more than RefTolerance ulp
+func (r reference) check() (Case, bool) {
+	c := Case{Got: float16.FromFloat64(r.float), Want: RoundBig(r.big, float16.RoundNearestEven)}
+	if math.IsInf(r.float, 0) {
+		// Some float64 functions overflow a little early, as math.Exp does
+		// at 709.5 on amd64, which is harmless as long as the reference is
+		// beyond the Float16 range too
+		c.ULP = math.Inf(1)
+		return c, r.big.MantExp(nil) > 17 && r.big.Sign() == int(math.Copysign(1, r.float))
+	}
+	d := new(big.Float).SetPrec(Prec).SetFloat64(r.float)
+	d.Sub(d, r.big)
+	if e := r.big.MantExp(nil); e > 17 {
+		// Beyond the Float16 range, compare relative to the value
+		d.SetMantExp(d, -e)
+		d.Abs(d)
+		c.ULP, _ = d.Float64()
+		return c, c.ULP <= 0x1p-40
+	}
+	d.SetMantExp(d, -ulpExp(r.big))
+	d.Abs(d)
+	c.ULP, _ = d.Float64()
+	return c, c.ULP <= RefTolerance
+}
+
+func floatReference(v float64) reference {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return reference{float: v}
+	}
+	return reference{big: new(big.Float).SetFloat64(v)}
+}
+
+// same reports whether two results are equal, treating zeros of either sign
+// and all NaNs as equal
+func same(a, b float16.Float16) bool {
+	return a == b || a.IsZero() && b.IsZero() || a.IsNaN() && b.IsNaN()
+}
+
+// compare returns the correctly rounded value of the reference in the given
+// mode and the error of got in ulps
+func (r reference) compare(got float16.Float16, mode float16.RoundingMode) (want float16.Float16, ulps float64) {
+	if r.big == nil {
+		want = float16.FromFloat64(r.float)
+		if same(got, want) {
+			return want, 0
+		}
+		return want, math.Inf(1)
+	}
+	want = RoundBig(r.big, mode)
+	exact := r.big
+	if exact.MantExp(nil) > 16 {
+		// Far beyond the Float16 range a correct result has no meaningful
+		// error; a wrong one is measured from 2^16, the next power of two
+		// after the largest finite value
+		if same(got, want) {
+			return want, 0
+		}
+		exact = new(big.Float).SetFloat64(math.Copysign(65536, float64(exact.Sign())))
+	}
+	if same(got, want) && want.IsInf(0) {
+		return want, 0
+	}
+	if got.IsNaN() || got.IsInf(0) {
+		return want, math.Inf(1)
+	}
+	d := new(big.Float).SetPrec(Prec).SetFloat64(got.ToFloat64())
+	d.Sub(d, exact)
+	d.Abs(d)
+	d.SetMantExp(d, -ulpExp(exact))
+	ulps, _ = d.Float64()
+	return want, ulps
+}
+
+// ulpExp returns the exponent of the Float16 spacing at x: 2^-24 in the
+// subnormal range and 2^5 for values beyond the largest binade
+func ulpExp(x *big.Float) int {
+	if x.Sign() == 0 {
+		return -24
+	}
+	e := x.MantExp(nil) - 1 // x in [2^e, 2^(e+1))
+	return min(max(e, -14), 15) - 10
+}
+
+// snap replaces a reference value within 2^-snapBits ulp of a Float16 value
+// or rounding midpoint by that exact value
+func snap(x *big.Float) *big.Float {
+	if x.Sign() == 0 || x.IsInf() || x.MantExp(nil) > 17 {
+		return x
+	}
+	e := ulpExp(x) - 1 // the grid includes midpoints
+	q := new(big.Float).SetPrec(Prec+64).SetMantExp(x, -e)
+	i, _ := q.Int(nil)
+	for _, k := range []*big.Int{i, new(big.Int).Add(i, big.NewInt(int64(q.Sign())))} {
+		if k.Sign() == 0 {
+			// The reference error is relative, so a nonzero value is
+			// never snapped to zero
+			continue
+		}
+		g := new(big.Float).SetPrec(Prec + 64).SetInt(k)
+		d := new(big.Float).SetPrec(Prec+64).Sub(q, g)
+		if d.Sign() == 0 || d.MantExp(nil) < -snapBits {
+			// The grid value may need more bits than x had
+			exact := new(big.Float).SetPrec(uint(max(k.BitLen(), 1))).SetInt(k)
+			return exact.SetMantExp(exact, e)
+		}
+	}
+	return x
+}
+
+// keepWorst inserts c into worst, keeping at most worstCases entries sorted
+// by decreasing error and, for equal errors, by input order
+func keepWorst(worst []Case, c Case) []Case {
+	if len(worst) == worstCases && c.ULP <= worst[len(worst)-1].ULP {
+		return worst
+	}
+	i, _ := slices.BinarySearchFunc(worst, c, func(a, b Case) int {
+		if a.ULP == b.ULP {
+			return -1 // place after existing equal errors
+		}
+		return cmp.Compare(b.ULP, a.ULP)
+	})
+	worst = slices.Insert(worst, i, c)
+	if len(worst) > worstCases {
+		worst = worst[:worstCases]
+	}
+	return worst
+}
+
+// bigModes maps Float16 rounding modes to their math/big equivalents
+var bigModes = map[float16.RoundingMode]big.RoundingMode{
+	float16.RoundNearestEven:    big.ToNearestEven,
+	float16.RoundNearestAway:    big.ToNearestAway,
+	float16.RoundTowardZero:     big.ToZero,
+	float16.RoundTowardPositive: big.ToPositiveInf,
+	float16.RoundTowardNegative: big.ToNegativeInf,
+}
+
+// RoundBig returns x rounded to Float16 in the given mode, with a single
+// rounding, including into the subnormal range and on overflow
+func RoundBig(x *big.Float, mode float16.RoundingMode) float16.Float16 {
+	bm := bigModes[mode]
+	neg := x.Signbit()
+	switch {
+	case x.IsInf():
+		return float16.FromFloat64(math.Inf(x.Sign()))
+	case x.Sign() == 0:
+		if neg {
+			return float16.NegativeZero
+		}
+		return float16.PositiveZero
+	}
+
+	if x.MantExp(nil) <= -14 { // |x| < 2^-14, the subnormal range
+		// Round |x|·2^24 to an integer k: offsetting by 2^11 (with the sign
+		// of x) makes the integers the values with 12 significant bits
+		// (SetMantExp takes the precision of x, so widen afterwards)
+		s := new(big.Float).SetMantExp(x, 24)
+		s.SetPrec(s.Prec() + 64)
+		if s.MantExp(nil) < -4 {
+			// Anything strictly between 0 and 1/2 rounds alike; keep it
+			// from vanishing in the addition below
+			s.SetFloat64(math.Copysign(0x1p-5, float64(x.Sign())))
+		}
+		off := new(big.Float).SetFloat64(math.Copysign(2048, float64(x.Sign())))
+		s.Add(s, off)
+		s.SetMode(bm).SetPrec(12)
+		s.Sub(s, off).Abs(s)
+		k, _ := s.Uint64()
+		return float16.FromBits(sign(neg) | uint16(k))
+	}
+
+	r := new(big.Float).SetMode(bm).SetPrec(11).Set(x)
+	v, _ := r.Float64()
+	if math.Abs(v) < 65536 {
+		return float16.FromFloat64(v)
+	}
+	// Overflow: infinity unless the mode rounds toward zero for this sign
+	toInf := bm == big.ToNearestEven || bm == big.ToNearestAway ||
+		bm == big.ToPositiveInf && !neg || bm == big.ToNegativeInf && neg
+	if toInf {
+		return float16.FromBits(sign(neg) | uint16(float16.PositiveInfinity))
+	}
+	return float16.FromBits(sign(neg) | uint16(float16.MaxValue))
+}
+
+func sign(neg bool) uint16 {
+	if neg {
+		return uint16(float16.SignMask)
+	}
+	return 0
+}
@@ -0,0 +1,153 @@
+package accuracy
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+var allModes = []float16.RoundingMode{
+	float16.RoundNearestEven,
+	float16.RoundNearestAway,
+	float16.RoundTowardZero,
+	float16.RoundTowardPositive,
+	float16.RoundTowardNegative,
+}
+
+// TestRoundBig checks RoundBig against the float64 conversion, which rounds
+// once in every mode
+func TestRoundBig(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	vals := []float64{
+		0, math.Copysign(0, -1), 1, -1, 65504, 65519.99, 65520, -65520, 1e6,
+		0x1p-24, 0x1p-25, 0x1.0000001p-25, 0x1.8p-24, 0x1p-14 - 0x1p-26, 1e-300, -1e-300,
+		1 + 0x1p-11, 1 + 0x1.8p-11, 2049, 2051,
+	}
+	for range 20000 {
+		vals = append(vals, math.Ldexp(rng.Float64()*2-1, rng.Intn(50)-30))
+	}
+	for _, mode := range allModes {
+		for _, v := range vals {
+			want, _ := float16.FromFloat64WithMode(v, float16.ModeIEEE, mode)
+			if got := RoundBig(new(big.Float).SetFloat64(v), mode); got != want {
+				t.Errorf("RoundBig(%g, %s) = %#v, want %#v", v, ModeName(mode), got, want)
+			}
+		}
+	}
+
+	// Values far below the subnormal range still round up when directed
+	tiny := new(big.Float).SetMantExp(big.NewFloat(1), -100000)
+	if got := RoundBig(tiny, float16.RoundTowardPositive); got != float16.SmallestSubnormal {
+		t.Errorf("RoundBig(2^-100000, TowardPositive) = %v, want smallest subnormal", got)
+	}
+	huge := new(big.Float).SetMantExp(big.NewFloat(-1), 100000)
+	if got := RoundBig(huge, float16.RoundTowardZero); got != float16.MaxValue.Neg() {
+		t.Errorf("RoundBig(-2^100000, TowardZero) = %v, want -MaxValue", got)
+	}
+}
+
+func TestSnap(t *testing.T) {
+	eps := new(big.Float).SetPrec(Prec).SetMantExp(big.NewFloat(1), -100)
+	near := func(v float64, sign int) *big.Float {
+		x := new(big.Float).SetPrec(Prec).SetFloat64(v)
+		d := new(big.Float).SetPrec(Prec).Mul(eps, big.NewFloat(math.Abs(v)))
+		if sign < 0 {
+			d.Neg(d)
+		}
+		return x.Add(x, d)
+	}
+	tests := []struct {
+		x    *big.Float
+		want float64 // 0 means unchanged
+	}{
+		{near(1, -1), 1},
+		{near(1+0x1p-11, 1), 1 + 0x1p-11}, // a midpoint
+		{near(0x1p-24, 1), 0x1p-24},
+		{near(1000, 1), 1000},
+		{new(big.Float).SetPrec(Prec).SetFloat64(1 + 0x1p-30), 0},
+		{new(big.Float).SetPrec(Prec).SetMantExp(big.NewFloat(1), -120), 0}, // not zero
+	}
+	for _, tt := range tests {
+		got := snap(tt.x)
+		if tt.want == 0 {
+			if got != tt.x {
+				t.Errorf("snap(%s) changed the value to %s", tt.x.Text('g', 40), got.Text('g', 40))
+			}
+			continue
+		}
+		if f, acc := got.Float64(); f != tt.want || acc != big.Exact {
+			t.Errorf("snap(%s) = %s, want %g", tt.x.Text('g', 40), got.Text('g', 40), tt.want)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	square := func(x, _ *big.Float) *big.Float { return new(big.Float).SetPrec(Prec).Mul(x, x) }
+	exact := Func{
+		Name: "square",
+		F: func(x, _ float16.Float16, mode float16.RoundingMode) float16.Float16 {
+			r, _ := float16.FromFloat64WithMode(x.ToFloat64()*x.ToFloat64(), float16.ModeIEEE, mode)
+			return r
+		},
+		Float: func(x, _ float64) float64 { return x * x },
+		Big:   square,
+	}
+	for _, r := range Measure(exact, allModes...) {
+		if r.Incorrect != 0 || r.Inputs != 65536 {
+			t.Errorf("%s: %d of %d incorrect, want none", ModeName(r.Mode), r.Incorrect, r.Inputs)
+		}
+		limit := 1.0
+		if r.Mode == float16.RoundNearestEven || r.Mode == float16.RoundNearestAway {
+			limit = 0.5
+		}
+		if r.MaxULP > limit {
+			t.Errorf("%s: max error %g ulp, want at most %g", ModeName(r.Mode), r.MaxULP, limit)
+		}
+	}
+
+	// Rounding through float32 first errs on double-rounding cases; one
+	// case is off by a NaN
+	doubled := exact
+	doubled.F = func(x, _ float16.Float16, _ float16.RoundingMode) float16.Float16 {
+		if x == float16.One() {
+			return float16.QuietNaN
+		}
+		return float16.FromFloat32(float32(x.ToFloat64() * x.ToFloat64()))
+	}
+	r := Measure(doubled)[0]
+	if r.Incorrect == 0 || !math.IsInf(r.MaxULP, 1) {
+		t.Fatalf("double rounding: %d incorrect, max error %g", r.Incorrect, r.MaxULP)
+	}
+	if w := r.Worst[0]; w.X != float16.One() || w.Want != float16.One() {
+		t.Errorf("worst case = %v, want the NaN result at 1", w)
+	}
+	if len(r.Worst) != worstCases || r.Worst[1].ULP < r.Worst[2].ULP {
+		t.Errorf("worst cases are not sorted: %v", r.Worst)
+	}
+
+	table := Table([]Report{r})
+	for _, s := range []string{"| square | NearestEven | ∞ |", "(1) → NaN, want 1"} {
+		if !strings.Contains(table, s) {
+			t.Errorf("table does not contain %q:\n%s", s, table)
+		}
+	}
+}
+
+func TestMeasureBinary(t *testing.T) {
+	ys := []float16.Float16{float16.FromFloat64(2), float16.FromFloat64(-0.5)}
+	f := Binary("mul", func(x, y float16.Float16) float16.Float16 {
+		return float16.FromFloat64(x.ToFloat64() * y.ToFloat64())
+	}, ys, func(x, y float64) float64 { return x * y }, nil)
+	r := Measure(f)[0]
+	if r.Inputs != 2*65536 || r.Incorrect != 0 || r.MaxULP > 0.5 {
+		t.Errorf("mul: %d of %d incorrect, max error %g", r.Incorrect, r.Inputs, r.MaxULP)
+	}
+	c := Case{X: float16.One(), Y: 0, Got: float16.One(), Want: float16.One(), binary: true}
+	if s := c.String(); s != "(1, 0) → 1, want 1" {
+		t.Errorf("Case.String() = %q", s)
+	}
+}
@@ -0,0 +1,366 @@
+package accuracy
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/zerfoo/float16"
+	"github.com/zerfoo/float16/internal/bigmath"
+)
+
+var (
+	exhaustive  = flag.Bool("exhaustive", false, "measure the math functions on every input rather than a sample")
+	updateTable = flag.Bool("update", false, "rewrite ACCURACY.md with the measured accuracy; implies -exhaustive")
+)
+
+// sampleStep is the stride through the Float16 bit patterns of the default,
+// sampled run. Being odd, it visits both signs and every binade.
+const sampleStep = 61
+
+// positive wraps a reference defined for x > 0, deferring x = 0 to the
+// float64 function (a pole or exact value)
+func positive(f func(*big.Float, uint) *big.Float) func(*big.Float, uint) *big.Float {
+	return func(x *big.Float, prec uint) *big.Float {
+		if x.Sign() == 0 {
+			return nil
+		}
+		return f(x, prec)
+	}
+}
+
+// nonPole wraps a reference with poles at zero and the negative integers
+func nonPole(f func(*big.Float, uint) *big.Float) func(*big.Float, uint) *big.Float {
+	return func(x *big.Float, prec uint) *big.Float {
+		if x.Sign() == 0 || x.Sign() < 0 && x.IsInt() {
+			return nil
+		}
+		return f(x, prec)
+	}
+}
+
+// withPoles wraps a reference, deferring the poles to the float64 function
+func withPoles(f func(*big.Float, uint) *big.Float, poles ...float64) func(*big.Float, uint) *big.Float {
+	return func(x *big.Float, prec uint) *big.Float {
+		if v, _ := x.Float64(); slices.Contains(poles, v) {
+			return nil
+		}
+		return f(x, prec)
+	}
+}
+
+func lgamma(x *big.Float, prec uint) *big.Float {
+	l, _ := bigmath.Lgamma(x, prec)
+	return l
+}
+
+func pow(x, y *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return nil // 0, or an infinity for negative y
+	}
+	return bigmath.Pow(x, y, prec)
+}
+
+func signum(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return x // zeros and NaN
+}
+
+// jn and yn adapt the order-n Bessel functions to Binary, with the order
+// as the second argument
+func jn(x, n float16.Float16) float16.Float16 { return float16.Jn(int(n.ToFloat32()), x) }
+
+func yn(x, n float16.Float16) float16.Float16 { return float16.Yn(int(n.ToFloat32()), x) }
+
+func bigJn(x, n *big.Float, prec uint) *big.Float {
+	k, _ := n.Int64()
+	return bigmath.Jn(int(k), x, prec)
+}
+
+func bigYn(x, n *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return nil // a pole
+	}
+	k, _ := n.Int64()
+	return bigmath.Yn(int(k), x, prec)
+}
+
+// fma measures FMA(x, x, y), so that the product spans the whole range
+func fma(x, y float16.Float16) float16.Float16 { return float16.FMA(x, x, y) }
+
+func bigFMA(x, y *big.Float, _ uint) *big.Float {
+	// The exact result needs at most 81 bits
+	r := new(big.Float).SetPrec(Prec).Mul(x, x)
+	return r.Add(r, y)
+}
+
+func scalbn(x, n float16.Float16) float16.Float16 { return float16.Scalbn(x, int(n.ToFloat32())) }
+
+func ldexp(x, n float64) float64 { return math.Ldexp(x, int(n)) }
+
+func bigLdexp(x, n *big.Float, _ uint) *big.Float {
+	k, _ := n.Int64()
+	return new(big.Float).SetMantExp(x, int(k))
+}
+
+// scalb is the float64 counterpart of float16.Scalb
+func scalb(x, n float64) float64 {
+	switch {
+	case math.IsNaN(x) || math.IsNaN(n):
+		return math.NaN()
+	case math.IsInf(n, 1):
+		if x == 0 {
+			return math.NaN()
+		}
+		return math.Copysign(inf, x)
+	case math.IsInf(n, -1):
+		if math.IsInf(x, 0) {
+			return math.NaN()
+		}
+		return math.Copysign(0, x)
+	case n != math.Trunc(n):
+		return math.NaN()
+	}
+	return math.Ldexp(x, int(n))
+}
+
+func halves(vs ...float64) []float16.Float16 {
+	out := make([]float16.Float16, len(vs))
+	for i, v := range vs {
+		out[i] = float16.FromFloat64(v)
+	}
+	return out
+}
+
+// mathFuncs lists the functions of math.go with their references
+var mathFuncs = []Func{
+	UnaryRounded("Sqrt", float16.SqrtWithRounding, math.Sqrt, bigmath.Sqrt),
+	UnaryRounded("Cbrt", float16.CbrtWithRounding, math.Cbrt, bigmath.Cbrt),
+	UnaryRounded("Exp", float16.ExpWithRounding, math.Exp, bigmath.Exp),
+	UnaryRounded("Exp2", float16.Exp2WithRounding, math.Exp2, bigmath.Exp2),
+	UnaryRounded("Exp10", float16.Exp10WithRounding, func(x float64) float64 { return math.Pow(10, x) }, bigmath.Exp10),
+	Unary("Expm1", float16.Expm1, math.Expm1, bigmath.Expm1),
+	UnaryRounded("Log", float16.LogWithRounding, math.Log, positive(bigmath.Log)),
+	UnaryRounded("Log2", float16.Log2WithRounding, math.Log2, positive(bigmath.Log2)),
+	UnaryRounded("Log10", float16.Log10WithRounding, math.Log10, positive(bigmath.Log10)),
+	Unary("Log1p", float16.Log1p, math.Log1p, withPoles(bigmath.Log1p, -1)),
+	Unary("Logb", float16.Logb, math.Logb, nil),
+	UnaryRounded("Sin", float16.SinWithRounding, math.Sin, bigmath.Sin),
+	UnaryRounded("Cos", float16.CosWithRounding, math.Cos, bigmath.Cos),
+	UnaryRounded("Tan", float16.TanWithRounding, math.Tan, bigmath.Tan),
+	Unary("Asin", float16.Asin, math.Asin, bigmath.Asin),
+	Unary("Acos", float16.Acos, math.Acos, bigmath.Acos),
+	Unary("Atan", float16.Atan, math.Atan, bigmath.Atan),
+	UnaryRounded("Sinh", float16.SinhWithRounding, math.Sinh, bigmath.Sinh),
+	UnaryRounded("Cosh", float16.CoshWithRounding, math.Cosh, bigmath.Cosh),
+	UnaryRounded("Tanh", float16.TanhWithRounding, math.Tanh, bigmath.Tanh),
+	Unary("Asinh", float16.Asinh, math.Asinh, bigmath.Asinh),
+	Unary("Acosh", float16.Acosh, math.Acosh, bigmath.Acosh),
+	Unary("Atanh", float16.Atanh, math.Atanh, withPoles(bigmath.Atanh, -1, 1)),
+	UnaryRounded("Erf", float16.ErfWithRounding, math.Erf, bigmath.Erf),
+	Unary("Erfc", float16.Erfc, math.Erfc, bigmath.Erfc),
+	Unary("Gamma", float16.Gamma, math.Gamma, nonPole(bigmath.Gamma)),
+	Unary("Lgamma", func(x float16.Float16) float16.Float16 { l, _ := float16.Lgamma(x); return l },
+		func(x float64) float64 { l, _ := math.Lgamma(x); return l }, nonPole(lgamma)),
+	Unary("J0", float16.J0, math.J0, bigmath.J0),
+	Unary("J1", float16.J1, math.J1, bigmath.J1),
+	Unary("Y0", float16.Y0, math.Y0, positive(bigmath.Y0)),
+	Unary("Y1", float16.Y1, math.Y1, positive(bigmath.Y1)),
+	Unary("Erfinv", float16.Erfinv, math.Erfinv, withPoles(bigmath.Erfinv, -1, 1)),
+	Unary("Erfcinv", float16.Erfcinv, math.Erfcinv, withPoles(bigmath.Erfcinv, 0, 2)),
+	Unary("Floor", float16.Floor, math.Floor, nil),
+	Unary("Ceil", float16.Ceil, math.Ceil, nil),
+	Unary("Trunc", float16.Trunc, math.Trunc, nil),
+	Unary("Round", float16.Round, math.Round, nil),
+	Unary("RoundToEven", float16.RoundToEven, math.RoundToEven, nil),
+	Unary("Abs", float16.Abs, math.Abs, nil),
+	Unary("Sign", float16.Sign, signum, nil),
+	BinaryRounded("Pow", float16.PowWithRounding, halves(-3, -1, -0.5, 0.5, 1.5, 2, 3, 1.0/3, math.Pi), math.Pow, pow),
+	Binary("Atan2", float16.Atan2, halves(-2, math.Copysign(0, -1), 0, 1, 1000), math.Atan2, bigmath.Atan2),
+	Binary("Hypot", float16.Hypot, halves(0, 1, 3, 0.001, 60000), math.Hypot, bigmath.Hypot),
+	Binary("Mod", float16.Mod, halves(-3, 0.1, 1, 7.5), math.Mod, nil),
+	Binary("Remainder", float16.Remainder, halves(-3, 0.1, 1, 7.5), math.Remainder, nil),
+	Binary("Dim", float16.Dim, halves(-1, 0, 0.3, 1000), math.Dim, nil),
+	Binary("Jn", jn, halves(-3, 2, 3, 5, 10), func(x, n float64) float64 { return math.Jn(int(n), x) }, bigJn),
+	Binary("Yn", yn, halves(-3, 2, 3, 5, 10), func(x, n float64) float64 { return math.Yn(int(n), x) }, bigYn),
+	Binary("FMA", fma, halves(-1, -0.333251953125, 0x1p-20, -0x1p-20, 1000, 0), func(x, y float64) float64 { return math.FMA(x, x, y) }, bigFMA),
+	Binary("Scalbn", scalbn, halves(-40, -24, -11, -1, 0, 1, 5, 20), ldexp, bigLdexp),
+	Binary("Scalb", float16.Scalb, halves(math.Inf(-1), -24, -1, 0, 0.5, 3, 30, math.Inf(1)), scalb, bigLdexp),
+}
+
+// threshold is the accuracy a function is held to. The limits record the
+// current state of each implementation and must only ever be lowered; a
+// function allowed any incorrectly rounded results needs a comment naming
+// their cause.
+// Functions that take a rounding mode are measured in every mode; maxULP is
+// their limit in round to nearest, and the directed modes allow half an ulp
+// more.
+type threshold struct {
+	maxULP    float64
+	incorrect int
+}
+
+var thresholds = map[string]threshold{
+	"Sqrt":        {0.5, 0},
+	"Cbrt":        {0.5, 0},
+	"Exp":         {0.5, 0},
+	"Exp2":        {0.5, 0},
+	"Exp10":       {0.5, 0},
+	"Expm1":       {0.5, 0},
+	"Log":         {0.5, 0},
+	"Log2":        {0.5, 0},
+	"Log10":       {0.5, 0},
+	"Log1p":       {0.5, 0},
+	"Logb":        {0, 0},
+	"Sin":         {0.5, 0},
+	"Cos":         {0.5, 0},
+	"Tan":         {0.5, 0},
+	"Asin":        {0.5, 0},
+	"Acos":        {0.5, 0},
+	"Atan":        {0.5, 0},
+	"Sinh":        {0.5, 0},
+	"Cosh":        {0.5, 0},
+	"Tanh":        {0.5, 0},
+	"Asinh":       {0.5, 0},
+	"Acosh":       {0.5, 0},
+	"Atanh":       {0.5, 0},
+	"Erf":         {0.5, 0},
+	"Erfc":        {0.5, 0},
+	"Gamma":       {0.5, 0},
+	"Lgamma":      {0.5, 0},
+	"J0":          {0.5, 0},
+	"J1":          {0.5, 0},
+	"Y0":          {0.5, 0},
+	"Y1":          {0.5, 0},
+	"Erfinv":      {0.5, 0},
+	"Erfcinv":     {0.5, 0},
+	"Floor":       {0, 0},
+	"Ceil":        {0, 0},
+	"Trunc":       {0, 0},
+	"Round":       {0, 0},
+	"RoundToEven": {0, 0},
+	"Abs":         {0, 0},
+	"Sign":        {0, 0},
+	"Pow":         {0.5, 0},
+	"Atan2":       {0.5, 0},
+	"Hypot":       {0.5, 0},
+	"Mod":         {0, 0},
+	"Remainder":   {0, 0},
+	"Dim":         {0.5, 0},
+	"Jn":          {0.5, 0},
+	"Yn":          {0.5, 0},
+	"FMA":         {0.5, 0},
+	"Scalbn":      {0.5, 0},
+	"Scalb":       {0.5, 0},
+}
+
+var inf = math.Inf(1)
+
+// TestMathAccuracy holds every function in mathFuncs to its threshold. By
+// default it measures a sample of the inputs; run it with -exhaustive, which
+// takes several minutes, to measure all of them, and with -update to also
+// regenerate ACCURACY.md.
+func TestMathAccuracy(t *testing.T) {
+	all := *exhaustive || *updateTable
+	if all && testing.Short() {
+		t.Skip("exhaustive accuracy test skipped in short mode")
+	}
+	reports := make([][]Report, len(mathFuncs))
+	t.Run("funcs", func(t *testing.T) {
+		for i, f := range mathFuncs {
+			t.Run(f.Name, func(t *testing.T) {
+				t.Parallel()
+				reports[i] = checkAccuracy(t, f, all)
+			})
+		}
+	})
+
+	if *updateTable && !t.Failed() {
+		var b strings.Builder
+		b.WriteString(tableHeader)
+		for _, f := range mathFuncs {
+			if f.Ys != nil {
+				fmt.Fprintf(&b, "- %s: %v\n", f.Name, f.Ys)
+			}
+		}
+		b.WriteString("\n")
+		b.WriteString(Table(slices.Concat(reports...)))
+		if err := os.WriteFile("../../ACCURACY.md", []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// checkAccuracy measures f, on every input if all is set and on a sample
+// otherwise, and fails t if f exceeds its threshold or its reference
+// disagrees with the float64 function
+func checkAccuracy(t *testing.T, f Func, all bool) []Report {
+	limit, ok := thresholds[f.Name]
+	if !ok {
+		t.Errorf("%s: no threshold", f.Name)
+	}
+	modes := []float16.RoundingMode{float16.RoundNearestEven}
+	if f.Rounded {
+		modes = allModes
+	}
+	step := sampleStep
+	if all {
+		step = 1
+	}
+
+	reports := MeasureSample(f, step, modes...)
+	if r := reports[0]; r.Disagreements > 0 {
+		t.Errorf("%s: reference disagrees with the float64 function on %d inputs: %v", f.Name, r.Disagreements, r.Disagree)
+	}
+	for _, r := range reports {
+		t.Logf("%s %s: max %s ulp, %d incorrect", r.Name, ModeName(r.Mode), FormatULP(r.MaxULP), r.Incorrect)
+		if !ok {
+			continue
+		}
+
+		maxULP := limit.maxULP
+		if r.Mode != float16.RoundNearestEven && r.Mode != float16.RoundNearestAway {
+			maxULP += 0.5
+		}
+		if r.MaxULP > maxULP {
+			t.Errorf("%s %s: max error %s ulp exceeds %s; worst %v", f.Name, ModeName(r.Mode), FormatULP(r.MaxULP), FormatULP(maxULP), r.Worst)
+		}
+		if r.Incorrect > limit.incorrect {
+			t.Errorf("%s %s: %d incorrectly rounded results exceed %d; worst %v", f.Name, ModeName(r.Mode), r.Incorrect, limit.incorrect, r.Worst)
+		}
+	}
+	return reports
+}
+
+const tableHeader = `# Accuracy of the math functions
+
+<!-- Generated by go test ./internal/accuracy -run TestMathAccuracy -update; do not edit. -->
+
+Every function in math.go is evaluated on all 65536 Float16 inputs and
+compared with the correctly rounded result of a 128-bit math/big reference.
+Each reference value is also checked against Go's float64 math package, an
+independent implementation, and must agree with it to 2^-20 ulp.
+Functions with a rounding mode variant (such as ExpWithRounding) are
+measured in all five rounding modes, the others in round to nearest even.
+Errors are measured in units of the Float16 spacing at the exact value, so
+a correctly rounded function has a maximum error of at most 0.5 ulp in the
+round to nearest modes and below 1 ulp in the directed ones. Results that
+differ from the correctly rounded value are counted as incorrectly rounded;
+zeros of either sign compare equal, as do all NaNs. A NaN or wrong infinity
+counts as an infinite error.
+
+Binary functions take every Float16 value as the first argument, paired
+with each of these second arguments. Jn and Yn take them as the order n,
+Scalbn as the exponent, and FMA is measured as FMA(x, x, y).
+
+`
@@ -0,0 +1,65 @@
+package accuracy
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/zerfoo/float16"
+)
+
+// ModeName returns the short name of a rounding mode used in tables and
+// test names
+func ModeName(mode float16.RoundingMode) string {
+	switch mode {
+	case float16.RoundNearestEven:
+		return "NearestEven"
+	case float16.RoundNearestAway:
+		return "NearestAway"
+	case float16.RoundTowardZero:
+		return "TowardZero"
+	case float16.RoundTowardPositive:
+		return "TowardPositive"
+	case float16.RoundTowardNegative:
+		return "TowardNegative"
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(mode))
+}
+
+// FormatULP formats an error in ulps for display
+func FormatULP(ulps float64) string {
+	if math.IsInf(ulps, 1) {
+		return "∞"
+	}
+	return fmt.Sprintf("%.5g", ulps)
+}
+
+// tableWorst is the number of worst inputs listed per row
+const tableWorst = 3
+
+// Table formats reports as the rows of a Markdown table, one per function
+// and rounding mode, listing the worst inputs of each
+func Table(reports []Report) string {
+	var b strings.Builder
+	b.WriteString("| Function | Rounding | Max error (ulp) | Incorrectly rounded | Worst inputs |\n")
+	b.WriteString("|---|---|---:|---:|---|\n")
+	for _, r := range reports {
+		var worst []string
+		for _, c := range r.Worst[:min(len(r.Worst), tableWorst)] {
+			worst = append(worst, c.String())
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %d / %d | %s |\n", r.Name, ModeName(r.Mode), FormatULP(r.MaxULP),
+			r.Incorrect, r.Inputs, strings.Join(worst, "; "))
+	}
+	return b.String()
+}
+
+// String describes the case as the input, the result and the correctly
+// rounded result
+func (c Case) String() string {
+	in := c.X.String()
+	if c.binary {
+		in += ", " + c.Y.String()
+	}
+	return fmt.Sprintf("(%s) → %s, want %s", in, c.Got, c.Want)
+}
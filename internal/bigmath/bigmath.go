@@ -0,0 +1,383 @@
+// Package bigmath evaluates elementary and special functions on big.Float
+// to a requested precision. It exists to provide reference values for the
+// accuracy tests of the float16 math functions and favours simple,
+// verifiable algorithms (argument reduction followed by Taylor or
+// asymptotic series) over speed.
+//
+// Every function takes a finite argument in its domain and a precision in
+// bits, and returns a new value whose relative error is below 2^-prec,
+// except near zeros of the function where the error is absolute with
+// respect to the magnitude of the terms involved. Internally each function
+// works with guard bits on top of prec.
+package bigmath
+
+import (
+	"math"
+	"math/big"
+	"sync"
+)
+
+// guard is the number of extra bits carried during evaluation
+const guard = 64
+
+func newFloat(prec uint) *big.Float {
+	return new(big.Float).SetPrec(prec)
+}
+
+// num returns v as a big.Float with the given precision
+func num(v float64, prec uint) *big.Float {
+	return newFloat(prec).SetFloat64(v)
+}
+
+// Constant caches, keyed by precision
+var (
+	cacheMu sync.Mutex
+	piCache = map[uint]*big.Float{}
+	ln2     = map[uint]*big.Float{}
+	ln10    = map[uint]*big.Float{}
+)
+
+func cached(m map[uint]*big.Float, prec uint, compute func(uint) *big.Float) *big.Float {
+	cacheMu.Lock()
+	v, ok := m[prec]
+	cacheMu.Unlock()
+	if !ok {
+		v = compute(prec)
+		cacheMu.Lock()
+		m[prec] = v
+		cacheMu.Unlock()
+	}
+	return newFloat(prec).Set(v)
+}
+
+// Pi returns π
+func Pi(prec uint) *big.Float {
+	return cached(piCache, prec, func(prec uint) *big.Float {
+		// Machin's formula: π = 16·atan(1/5) - 4·atan(1/239)
+		wp := prec + guard
+		a := atanInv(5, wp)
+		a.Mul(a, num(16, wp))
+		b := atanInv(239, wp)
+		b.Mul(b, num(4, wp))
+		return a.Sub(a, b).SetPrec(prec)
+	})
+}
+
+// atanInv returns atan(1/n) for an integer n > 1
+func atanInv(n int64, prec uint) *big.Float {
+	x := newFloat(prec).Quo(num(1, prec), num(float64(n), prec))
+	return atanSeries(x, prec)
+}
+
+// atanSeries sums atan(x) = x - x³/3 + x⁵/5 - ... for small |x|
+func atanSeries(x *big.Float, prec uint) *big.Float {
+	x2 := newFloat(prec).Mul(x, x)
+	pow := newFloat(prec).Set(x)
+	sum := newFloat(prec).Set(x)
+	term := newFloat(prec)
+	for k := int64(1); ; k++ {
+		pow.Mul(pow, x2)
+		pow.Neg(pow)
+		term.Quo(pow, num(float64(2*k+1), prec))
+		if negligible(term, sum, prec) {
+			return sum
+		}
+		sum.Add(sum, term)
+	}
+}
+
+// atanhSeries sums atanh(x) = x + x³/3 + x⁵/5 + ... for small |x|
+func atanhSeries(x *big.Float, prec uint) *big.Float {
+	x2 := newFloat(prec).Mul(x, x)
+	pow := newFloat(prec).Set(x)
+	sum := newFloat(prec).Set(x)
+	term := newFloat(prec)
+	for k := int64(1); ; k++ {
+		pow.Mul(pow, x2)
+		term.Quo(pow, num(float64(2*k+1), prec))
+		if negligible(term, sum, prec) {
+			return sum
+		}
+		sum.Add(sum, term)
+	}
+}
+
+// negligible reports whether adding term to sum no longer matters at prec
+func negligible(term, sum *big.Float, prec uint) bool {
+	if term.Sign() == 0 {
+		return true
+	}
+	if sum.Sign() == 0 {
+		return false
+	}
+	return term.MantExp(nil) < sum.MantExp(nil)-int(prec)-2
+}
+
+// Ln2 returns log(2)
+func Ln2(prec uint) *big.Float {
+	return cached(ln2, prec, func(prec uint) *big.Float {
+		// log(2) = 2·atanh(1/3)
+		wp := prec + guard
+		third := newFloat(wp).Quo(num(1, wp), num(3, wp))
+		s := atanhSeries(third, wp)
+		return s.Mul(s, num(2, wp)).SetPrec(prec)
+	})
+}
+
+// Ln10 returns log(10)
+func Ln10(prec uint) *big.Float {
+	return cached(ln10, prec, func(prec uint) *big.Float {
+		return Log(num(10, prec+guard), prec)
+	})
+}
+
+// Exp returns e^x
+func Exp(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return num(1, prec)
+	}
+	// Reduce x = k·log(2) + r with |r| <= log(2)/2, then halve r a few more
+	// times so the series converges quickly, and square the result back
+	const halvings = 8
+	wp := prec + guard + halvings + uint(max(x.MantExp(nil), 0))
+	l2 := Ln2(wp)
+	kf := newFloat(wp).Quo(x, l2)
+	k := roundToInt(kf)
+	r := newFloat(wp).Mul(l2, newFloat(wp).SetInt64(k))
+	r.Sub(newFloat(wp).Set(x), r)
+	r.SetMantExp(r, -halvings)
+
+	sum := num(1, wp)
+	term := num(1, wp)
+	for n := int64(1); ; n++ {
+		term.Mul(term, r)
+		term.Quo(term, num(float64(n), wp))
+		if negligible(term, sum, wp) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	for range halvings {
+		sum.Mul(sum, sum)
+	}
+	sum.SetMantExp(sum, int(k))
+	return sum.SetPrec(prec)
+}
+
+// Expm1 returns e^x - 1
+func Expm1(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(prec)
+	}
+	// Subtracting 1 cancels about -log2|x| bits for small x
+	wp := prec + guard + uint(max(-x.MantExp(nil), 0))
+	e := Exp(x, wp)
+	return e.Sub(e, num(1, wp)).SetPrec(prec)
+}
+
+// roundToInt returns x rounded to the nearest integer; |x| must fit in int64
+func roundToInt(x *big.Float) int64 {
+	h := newFloat(x.Prec() + 1).SetFloat64(0.5)
+	if x.Sign() < 0 {
+		h.Neg(h)
+	}
+	i, _ := h.Add(h, x).Int64()
+	return i
+}
+
+// Log returns the natural logarithm of x > 0
+func Log(x *big.Float, prec uint) *big.Float {
+	wp := prec + guard
+	// x = m·2^e with m in [1/√2, √2), log(m) = 2·atanh((m-1)/(m+1))
+	m := newFloat(wp)
+	e := x.MantExp(m)
+	if m.Cmp(num(0.7071067811865476, 64)) < 0 {
+		m.SetMantExp(m, 1)
+		e--
+	}
+	t := newFloat(wp).Sub(m, num(1, wp))
+	t.Quo(t, newFloat(wp).Add(m, num(1, wp)))
+	s := atanhSeries(t, wp)
+	s.Mul(s, num(2, wp))
+	if e != 0 {
+		el := Ln2(wp)
+		el.Mul(el, newFloat(wp).SetInt64(int64(e)))
+		s.Add(s, el)
+	}
+	return s.SetPrec(prec)
+}
+
+// Log1p returns log(1 + x) for x > -1
+func Log1p(x *big.Float, prec uint) *big.Float {
+	return Log(addExact(num(1, prec), x), prec)
+}
+
+// Log2 returns the base-2 logarithm of x > 0; it is exact for powers of two
+func Log2(x *big.Float, prec uint) *big.Float {
+	wp := prec + guard
+	m := newFloat(wp)
+	e := x.MantExp(m)
+	// x = 2m·2^(e-1) with 2m in [1, 2)
+	m.SetMantExp(m, 1)
+	e--
+	l := Log(m, wp)
+	l.Quo(l, Ln2(wp))
+	return l.Add(l, newFloat(wp).SetInt64(int64(e))).SetPrec(prec)
+}
+
+// Log10 returns the base-10 logarithm of x > 0
+func Log10(x *big.Float, prec uint) *big.Float {
+	wp := prec + guard
+	l := Log(x, wp)
+	return l.Quo(l, Ln10(wp)).SetPrec(prec)
+}
+
+// Exp2 returns 2^x; it is exact for integer x
+func Exp2(x *big.Float, prec uint) *big.Float {
+	if x.IsInt() {
+		k, _ := x.Int64()
+		return newFloat(prec).SetMantExp(num(1, prec), int(k))
+	}
+	wp := prec + guard + uint(max(x.MantExp(nil), 0))
+	t := newFloat(wp).Mul(x, Ln2(wp))
+	return Exp(t, prec)
+}
+
+// Exp10 returns 10^x; it is exact for small non-negative integer x
+func Exp10(x *big.Float, prec uint) *big.Float {
+	if x.IsInt() && x.Sign() >= 0 && x.Cmp(num(20, 64)) <= 0 {
+		k, _ := x.Int64()
+		p := new(big.Int).Exp(big.NewInt(10), big.NewInt(k), nil)
+		return newFloat(prec).SetInt(p)
+	}
+	wp := prec + guard + uint(max(x.MantExp(nil), 0))
+	t := newFloat(wp).Mul(x, Ln10(wp))
+	return Exp(t, prec)
+}
+
+// Sqrt returns the square root of x >= 0
+func Sqrt(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(prec)
+	}
+	return newFloat(prec).Sqrt(x)
+}
+
+// Cbrt returns the cube root of x
+func Cbrt(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(prec)
+	}
+	wp := prec + guard
+	a := newFloat(wp).Abs(x)
+	// Start from a float64 estimate scaled into range, then Newton's method
+	m := newFloat(wp)
+	e := a.MantExp(m)
+	for e%3 != 0 {
+		m.SetMantExp(m, 1)
+		e--
+	}
+	mf, _ := m.Float64()
+	y := num(math.Cbrt(mf), wp)
+	three := num(3, wp)
+	for range 6 { // 53 bits doubling per step covers any practical prec
+		y2 := newFloat(wp).Mul(y, y)
+		d := newFloat(wp).Quo(m, y2)
+		d.Sub(d, y)
+		d.Quo(d, three)
+		y.Add(y, d)
+	}
+	y.SetMantExp(y, e/3)
+	if x.Sign() < 0 {
+		y.Neg(y)
+	}
+	return y.SetPrec(prec)
+}
+
+// Pow returns x^y for finite x and y, with x > 0 or y an integer
+func Pow(x, y *big.Float, prec uint) *big.Float {
+	if y.Sign() == 0 {
+		return num(1, prec)
+	}
+	if x.Sign() == 0 {
+		return newFloat(prec) // caller handles signs and poles
+	}
+	neg := false
+	if x.Sign() < 0 {
+		// Only integer y reach here; odd powers keep the sign
+		i := new(big.Int)
+		y.Int(i)
+		neg = i.Bit(0) == 1
+	}
+	wp := prec + guard
+	ax := newFloat(wp).Abs(x)
+
+	var r *big.Float
+	if y.IsInt() && cmpAbs(y, 64) <= 0 {
+		// Exact for small integer exponents
+		k, _ := y.Int64()
+		r = powInt(ax, k)
+	} else {
+		// x^y = e^(y·log x); log x has a magnitude of at most 12, and the
+		// product needs as many extra bits as y·log x has integer bits
+		l := Log(ax, wp+24)
+		l.Mul(l, y)
+		r = Exp(l, wp)
+	}
+	if neg {
+		r.Neg(r)
+	}
+	return r.SetPrec(prec)
+}
+
+// powInt returns x^k exactly (x > 0)
+func powInt(x *big.Float, k int64) *big.Float {
+	n := k
+	if n < 0 {
+		n = -n
+	}
+	// Each multiplication adds at most the precision of x
+	prec := x.Prec()*uint(n+1) + 64
+	r := num(1, prec)
+	b := newFloat(prec).Set(x)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			r.Mul(r, b)
+		}
+		b.Mul(b, b)
+	}
+	if k < 0 {
+		r.Quo(num(1, prec), r)
+	}
+	return r
+}
+
+// Hypot returns sqrt(x² + y²)
+func Hypot(x, y *big.Float, prec uint) *big.Float {
+	wp := 2*max(x.Prec(), y.Prec()) + 2
+	s := newFloat(wp).Mul(x, x)
+	s.Add(s, newFloat(wp).Mul(y, y))
+	return Sqrt(s, prec)
+}
+
+// cmpAbs compares |x| with v >= 0
+func cmpAbs(x *big.Float, v float64) int {
+	return newFloat(x.Prec()).Abs(x).Cmp(num(v, 64))
+}
+
+// absCmp compares |a| with |b|
+func absCmp(a, b *big.Float) int {
+	return newFloat(a.Prec()).Abs(a).Cmp(newFloat(b.Prec()).Abs(b))
+}
+
+// addExact returns a + b without rounding
+func addExact(a, b *big.Float) *big.Float {
+	if a.Sign() == 0 || b.Sign() == 0 {
+		return newFloat(max(a.Prec(), b.Prec())).Add(a, b)
+	}
+	// The sum spans from the top of the larger operand to the bottom of
+	// the smaller one, plus a carry
+	ea, eb := a.MantExp(nil), b.MantExp(nil)
+	prec := max(a.Prec(), b.Prec()) + uint(max(ea-eb, eb-ea)) + 1
+	return newFloat(prec).Add(a, b)
+}
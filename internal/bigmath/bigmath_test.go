@@ -0,0 +1,247 @@
+package bigmath
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const testPrec = 160
+
+func TestConstants(t *testing.T) {
+	tests := []struct {
+		name string
+		v    *big.Float
+		want string
+	}{
+		{"pi", Pi(testPrec), "3.14159265358979323846264338327950288419716939937510"},
+		{"ln2", Ln2(testPrec), "0.693147180559945309417232121458176568075500134360255"},
+		{"ln10", Ln10(testPrec), "2.30258509299404568401799145468436420760110148862877"},
+		{"euler", EulerGamma(testPrec), "0.577215664901532860606512090082402431042159335939923"},
+	}
+	for _, tt := range tests {
+		// 160 bits hold 48 significant digits
+		got := tt.v.Text('f', 46)
+		if !strings.HasPrefix(tt.want, got[:len(got)-1]) {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	b := bernoulliNumbers()
+	if len(b) != 20 || b[0].Cmp(big.NewRat(1, 6)) != 0 || b[5].Cmp(big.NewRat(-691, 2730)) != 0 {
+		t.Errorf("Bernoulli numbers B2, B12 = %v, %v", b[0], b[5])
+	}
+	b40, _ := new(big.Rat).SetString("-261082718496449122051/13530")
+	if b[19].Cmp(b40) != 0 {
+		t.Errorf("B40 = %v, want %v", b[19], b40)
+	}
+}
+
+// TestAgainstFloat64 checks each function against the float64 math package
+// at sample points, which validates the algorithms to about 50 bits
+func TestAgainstFloat64(t *testing.T) {
+	unary := []struct {
+		name string
+		f    func(*big.Float, uint) *big.Float
+		ref  func(float64) float64
+		args []float64
+	}{
+		{"Exp", Exp, math.Exp, []float64{-700, -20, -1, -1e-9, 0.5, 1, 3.7, 88, 700}},
+		{"Exp2", Exp2, math.Exp2, []float64{-30, -0.5, 0.1, 10, 15.9}},
+		{"Exp10", Exp10, func(x float64) float64 { return math.Pow(10, x) }, []float64{-8, -0.3, 2, 4.5}},
+		{"Log", Log, math.Log, []float64{1e-300, 0.1, 0.7, 1, 1.5, 2, 10, 65504}},
+		{"Log2", Log2, math.Log2, []float64{0.001, 1, 3, 1024, 60000}},
+		{"Log10", Log10, math.Log10, []float64{0.001, 7, 100, 65504}},
+		{"Sqrt", Sqrt, math.Sqrt, []float64{0, 2, 1e-8, 65504}},
+		{"Cbrt", Cbrt, math.Cbrt, []float64{-27, -2, 1e-7, 0.3, 3, 65504}},
+		{"Sin", Sin, math.Sin, []float64{-4, 1e-5, 0.5, 1, 2, 3, 10, 355, 65504}},
+		{"Cos", Cos, math.Cos, []float64{-4, 0, 0.5, 1.5, 3, 100, 65504}},
+		{"Tan", Tan, math.Tan, []float64{-1, 0.5, 1.5, 11, 65504}},
+		{"Atan", Atan, math.Atan, []float64{-100, -1, -0.3, 1e-6, 0.2, 1, 5, 65504}},
+		{"Asin", Asin, math.Asin, []float64{-1, -0.5, 1e-3, 0.9, 0.999, 1}},
+		{"Acos", Acos, math.Acos, []float64{-1, -0.5, 0, 0.5, 0.99, 1}},
+		{"Sinh", Sinh, math.Sinh, []float64{-10, -1e-6, 0.001, 1, 11}},
+		{"Cosh", Cosh, math.Cosh, []float64{-10, 0, 0.001, 1, 11}},
+		{"Tanh", Tanh, math.Tanh, []float64{-3, -1e-4, 0.5, 1, 10}},
+		{"Expm1", Expm1, math.Expm1, []float64{-40, -1, -1e-7, 1e-12, 0.3, 5}},
+		{"Log1p", Log1p, math.Log1p, []float64{-0.99, -0.5, -1e-9, 1e-15, 0.7, 1000}},
+		{"Asinh", Asinh, math.Asinh, []float64{-1000, -1, -1e-8, 0.5, 3, 65504}},
+		{"Acosh", Acosh, math.Acosh, []float64{1, 1 + 0x1p-10, 1.5, 10, 65504}},
+		{"Atanh", Atanh, math.Atanh, []float64{-0.999, -0.5, -1e-6, 0.25, 0.49, 0.9}},
+		{"Erf", Erf, math.Erf, []float64{-3, -0.5, 1e-5, 0.5, 1, 2, 4, 6}},
+		{"Erfc", Erfc, math.Erfc, []float64{-3, -0.5, 0, 0.5, 1, 3, 6, 9, 15, 26}},
+		{"Gamma", Gamma, math.Gamma, []float64{-2.5, -0.5, 1e-4, 0.5, 1, 1.5, 5, 9.3, 30}},
+		{"J0", J0, math.J0, []float64{-3, 0, 0.5, 2.4, 10, 50, 150, 1000}},
+		{"J1", J1, math.J1, []float64{-3, 0.5, 3.8, 10, 50, 150, 1000}},
+		{"Y0", Y0, math.Y0, []float64{1e-5, 0.5, 0.89, 10, 50, 150, 1000}},
+		{"Y1", Y1, math.Y1, []float64{1e-5, 0.5, 2.2, 10, 50, 150, 1000}},
+		{"Erfinv", Erfinv, math.Erfinv, []float64{-0.999, -0.5, 0, 1e-6, 0.3, 0.9, 0.99951171875}},
+		{"Erfcinv", Erfcinv, math.Erfcinv, []float64{0x1p-24, 1e-3, 0.5, 1, 1.7, 1.999}},
+	}
+	for _, tt := range unary {
+		for _, x := range tt.args {
+			got, _ := tt.f(num(x, 53), testPrec).Float64()
+			checkClose(t, tt.name, x, got, tt.ref(x))
+		}
+	}
+
+	for _, x := range []float64{-2.5, -0.5, 0.5, 1, 2, 3.5, 100} {
+		l, sign := Lgamma(num(x, 53), testPrec)
+		got, _ := l.Float64()
+		want, wantSign := math.Lgamma(x)
+		checkClose(t, "Lgamma", x, got, want)
+		if sign != wantSign {
+			t.Errorf("Lgamma(%v) sign = %d, want %d", x, sign, wantSign)
+		}
+	}
+
+	for _, n := range []int{-3, 0, 1, 2, 5, 10} {
+		for _, x := range []float64{-7.5, 1e-3, 0.5, 3, 12, 99, 101, 1000} {
+			got, _ := Jn(n, num(x, 53), testPrec).Float64()
+			checkClose(t, fmt.Sprintf("Jn(%d)", n), x, got, math.Jn(n, x))
+			if x > 0 {
+				got, _ = Yn(n, num(x, 53), testPrec).Float64()
+				checkClose(t, fmt.Sprintf("Yn(%d)", n), x, got, math.Yn(n, x))
+			}
+		}
+	}
+
+	binary := []struct {
+		name string
+		f    func(a, b *big.Float, prec uint) *big.Float
+		ref  func(a, b float64) float64
+	}{
+		{"Pow", Pow, math.Pow},
+		{"Atan2", Atan2, math.Atan2},
+		{"Hypot", Hypot, math.Hypot},
+	}
+	for _, tt := range binary {
+		for _, ab := range [][2]float64{{2, 0.5}, {3, -2}, {0.7, 7.3}, {-2, 3}, {-1.5, -4}, {1e-3, 2}, {5, 0}, {60000, 0.999}} {
+			got, _ := tt.f(num(ab[0], 53), num(ab[1], 53), testPrec).Float64()
+			checkClose(t, tt.name, ab, got, tt.ref(ab[0], ab[1]))
+		}
+	}
+	for _, yx := range [][2]float64{{0, -1}, {math.Copysign(0, -1), -1}, {1, 0}, {-1, 0}, {0, 1}} {
+		got, _ := Atan2(num(yx[0], 53), num(yx[1], 53), testPrec).Float64()
+		if want := math.Atan2(yx[0], yx[1]); got != want || math.Signbit(got) != math.Signbit(want) {
+			t.Errorf("Atan2%v = %v, want %v", yx, got, want)
+		}
+	}
+}
+
+func checkClose(t *testing.T, name string, x any, got, want float64) {
+	t.Helper()
+	if got == want {
+		return
+	}
+	tol := 1e-14 * math.Abs(want)
+	if want == 0 {
+		// Exact zeros, such as Lgamma(1), are only approached to the precision
+		tol = 1e-60
+	}
+	if math.Abs(got-want) > tol {
+		t.Errorf("%s(%v) = %v, want %v", name, x, got, want)
+	}
+}
+
+// TestIdentities checks results to nearly full precision with identities
+// that do not involve the float64 functions
+func TestIdentities(t *testing.T) {
+	tol := newFloat(testPrec).SetMantExp(num(1, 64), -testPrec+8)
+	close := func(name string, a, b *big.Float) {
+		t.Helper()
+		d := newFloat(testPrec).Sub(a, b)
+		d.Abs(d)
+		m := newFloat(testPrec).Abs(b)
+		if m.Cmp(num(1, 64)) < 0 {
+			m = num(1, 64)
+		}
+		if d.Cmp(newFloat(testPrec).Mul(tol, m)) > 0 {
+			t.Errorf("%s: %s != %s", name, a.Text('g', 50), b.Text('g', 50))
+		}
+	}
+
+	for _, v := range []float64{-37.25, -1, 0.001, 0.75, 0.9999, 2, 1000} {
+		x := num(v, 53)
+		close("log(exp(x))", Log(Exp(x, testPrec), testPrec), x)
+
+		s, c := sinCos(x, testPrec)
+		s.Mul(s, s)
+		c.Mul(c, c)
+		close("sin²+cos²", s.Add(s, c), num(1, 64))
+
+		close("tan(atan(x))", Tan(Atan(x, testPrec), testPrec), x)
+		if v < 1 && v > -1 {
+			close("cos(acos(x))", Cos(Acos(x, testPrec), testPrec), x)
+			close("sin(asin(x))", Sin(Asin(x, testPrec), testPrec), x)
+			close("log1p(expm1(x))", Log1p(Expm1(x, testPrec), testPrec), x)
+			close("atanh(tanh(x))", Atanh(Tanh(x, testPrec), testPrec), x)
+		}
+
+		close("asinh(sinh(x))", Asinh(Sinh(x, testPrec), testPrec), x)
+		if v >= 2 {
+			close("acosh(cosh(x))", Acosh(Cosh(x, testPrec), testPrec), x)
+		}
+
+		cb := Cbrt(x, testPrec)
+		close("cbrt(x)³", cb.Mul(cb, newFloat(testPrec).Mul(cb, cb)), x)
+	}
+	close("exp2(10)", Exp2(num(10, 53), testPrec), num(1024, 64))
+	close("log2(1024)", Log2(num(1024, 53), testPrec), num(10, 64))
+	close("exp10(3)", Exp10(num(3, 53), testPrec), num(1000, 64))
+	close("pow(1.5, 5)", Pow(num(1.5, 53), num(5, 53), testPrec), num(7.59375, 64))
+	close("gamma(6)", Gamma(num(6, 53), testPrec), num(120, 64))
+	close("gamma(0.5)²", func() *big.Float {
+		g := Gamma(num(0.5, 53), testPrec)
+		return g.Mul(g, g)
+	}(), Pi(testPrec))
+
+	// Wronskian J1(x)·Y0(x) - J0(x)·Y1(x) = 2/(πx), on both sides of the
+	// switch to the asymptotic expansion
+	for _, v := range []float64{0.5, 7, 99, 101, 3000} {
+		x := num(v, 53)
+		w := newFloat(testPrec).Mul(J1(x, testPrec), Y0(x, testPrec))
+		w.Sub(w, newFloat(testPrec).Mul(J0(x, testPrec), Y1(x, testPrec)))
+		want := newFloat(testPrec).Mul(Pi(testPrec), x)
+		want.Quo(num(2, 64), want)
+		close("Wronskian", w.Quo(w, want), num(1, 64))
+	}
+
+	// erf(x) + erfc(x) = 1
+	for _, v := range []float64{-2, 0.25, 3, 6.5} {
+		x := num(v, 53)
+		close("erf+erfc", newFloat(testPrec).Add(Erf(x, testPrec), Erfc(x, testPrec)), num(1, 64))
+	}
+
+	// The inverses undo erf and erfc
+	for _, v := range []float64{-0.999, 1e-6, 0.5, 0.9995} {
+		x := num(v, 53)
+		close("erf(erfinv(x))", Erf(Erfinv(x, testPrec), testPrec), x)
+		x.Add(x, num(1, 64))
+		close("erfc(erfcinv(x))", Erfc(Erfcinv(x, testPrec), testPrec), x)
+	}
+
+	// Recurrence Jₙ₋₁(x) + Jₙ₊₁(x) = 2n/x·Jₙ(x) across the series and the
+	// asymptotic expansion
+	for _, v := range []float64{0.5, 7, 99, 101, 3000} {
+		x := num(v, 53)
+		s := newFloat(testPrec).Add(Jn(4, x, testPrec), Jn(6, x, testPrec))
+		want := newFloat(testPrec).Mul(num(10, 64), Jn(5, x, testPrec))
+		close("Jn recurrence", s, want.Quo(want, x))
+	}
+}
+
+func TestSaturatedValues(t *testing.T) {
+	one := num(1, 64)
+	for _, f := range []func(*big.Float, uint) *big.Float{Tanh, Erf} {
+		r := f(num(40000, 53), testPrec)
+		if r.Cmp(one) >= 0 || newFloat(testPrec).Sub(one, r).Cmp(newFloat(64).SetMantExp(one, -59)) >= 0 {
+			t.Errorf("saturated value %s is not just below 1", r.Text('g', 30))
+		}
+	}
+	if r := Erfc(num(200, 53), testPrec); r.Sign() <= 0 || r.MantExp(nil) > -57000 {
+		t.Errorf("Erfc(200) = %s, want about 1e-17375", r.Text('g', 10))
+	}
+}
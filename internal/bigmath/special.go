@@ -0,0 +1,513 @@
+package bigmath
+
+import (
+	"math"
+	"math/big"
+	"sync"
+)
+
+// log2e is log₂(e), used to size precisions for terms of magnitude e^t
+const log2e = 1.4426950408889634
+
+// bitsFor returns the number of bits spanned by e^t for t >= 0
+func bitsFor(t float64) uint {
+	return uint(math.Ceil(t*log2e)) + 2
+}
+
+// erfSeries returns erf(x) = 2/√π·e^(-x²)·Σ 2^n·x^(2n+1)/(1·3·…·(2n+1)),
+// whose terms are all positive
+func erfSeries(x *big.Float, prec uint) *big.Float {
+	x2 := newFloat(prec).Mul(x, x)
+	twoX2 := newFloat(prec).SetMantExp(x2, 1)
+	term := newFloat(prec).Set(x)
+	sum := newFloat(prec).Set(x)
+	for n := int64(1); ; n++ {
+		term.Mul(term, twoX2)
+		term.Quo(term, num(float64(2*n+1), prec))
+		if n > 1 && negligible(term, sum, prec) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	sum.Abs(sum)
+	e := Exp(x2.Neg(x2), prec)
+	sum.Mul(sum, e)
+	sum.Mul(sum, num(2, prec))
+	sum.Quo(sum, Sqrt(Pi(prec), prec))
+	return copySign(sum, x)
+}
+
+// Erf returns the error function of x. For |x| >= 7 the result is returned
+// as ±(1 - 2^-60), like Tanh.
+func Erf(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(prec)
+	}
+	if cmpAbs(x, 7) >= 0 { // erfc(7) < 2^-73
+		return copySign(nearOne(prec), x)
+	}
+	return erfSeries(x, prec+guard).SetPrec(prec)
+}
+
+// Erfc returns the complementary error function of x
+func Erfc(x *big.Float, prec uint) *big.Float {
+	wp := prec + guard
+	if x.Sign() <= 0 {
+		// 1 + erf(|x|), which has no cancellation
+		a := newFloat(wp).Abs(x)
+		r := Erf(a, wp)
+		r.Add(r, num(1, wp))
+		return r.SetPrec(prec)
+	}
+	xf, _ := x.Float64()
+	if xf*xf*log2e <= float64(wp) {
+		// erfc(x) >= e^(-x²)/4 is small, so 1 - erf(x) needs bits to cover it
+		r := erfSeries(x, wp+bitsFor(xf*xf))
+		r.Sub(num(1, r.Prec()), r)
+		return r.SetPrec(prec)
+	}
+	// Asymptotic series e^(-x²)/(x√π)·Σ (-1)^n·(2n-1)!!/(2x²)^n, whose
+	// smallest term is below e^(-x²) < 2^-wp
+	x2 := newFloat(wp).Mul(x, x)
+	inv := newFloat(wp).Quo(num(1, wp), newFloat(wp).SetMantExp(x2, 1))
+	term := num(1, wp)
+	sum := num(1, wp)
+	for n := int64(1); ; n++ {
+		next := newFloat(wp).Mul(term, inv)
+		next.Mul(next, num(float64(2*n-1), wp))
+		next.Neg(next)
+		if absCmp(next, term) >= 0 || negligible(next, sum, wp) {
+			break
+		}
+		term = next
+		sum.Add(sum, term)
+	}
+	e := Exp(x2.Neg(x2), wp)
+	sum.Mul(sum, e)
+	sum.Quo(sum, x)
+	sum.Quo(sum, Sqrt(Pi(wp), wp))
+	return sum.SetPrec(prec)
+}
+
+// Bernoulli numbers B2, B4, …, B40 for the Stirling series
+var (
+	bernoulliOnce sync.Once
+	bernoulli     []*big.Rat
+)
+
+// bernoulliNumbers returns B2k for k in [1, 20], computed with the
+// Akiyama-Tanigawa algorithm
+func bernoulliNumbers() []*big.Rat {
+	bernoulliOnce.Do(func() {
+		const n = 40
+		a := make([]*big.Rat, n+1)
+		for m := 0; m <= n; m++ {
+			a[m] = big.NewRat(1, int64(m+1))
+			for j := m; j >= 1; j-- {
+				d := new(big.Rat).Sub(a[j-1], a[j])
+				a[j-1] = d.Mul(d, big.NewRat(int64(j), 1))
+			}
+			if m >= 2 && m%2 == 0 {
+				bernoulli = append(bernoulli, new(big.Rat).Set(a[0]))
+			}
+		}
+	})
+	return bernoulli
+}
+
+// stirlingMin is the argument above which the Stirling series with terms up
+// to B40 is truncated after about 2^-220, which bounds the precision of
+// Lgamma and Gamma
+const stirlingMin = 100
+
+// halfLog2Pi caches log(2π)/2
+var halfLog2Pi = map[uint]*big.Float{}
+
+// lgammaPos returns log Γ(x) for x > 0
+func lgammaPos(x *big.Float, prec uint) *big.Float {
+	// Shift to z = x + n >= stirlingMin with log Γ(x) = log Γ(z) - log(x(x+1)…(z-1))
+	z := newFloat(prec).Set(x)
+	prod := num(1, prec)
+	for z.Cmp(num(stirlingMin, 64)) < 0 {
+		prod.Mul(prod, z)
+		z.Add(z, num(1, prec))
+	}
+
+	// log Γ(z) = (z - 1/2)·log z - z + log(2π)/2 + Σ B2k/(2k(2k-1)·z^(2k-1))
+	lz := Log(z, prec)
+	r := newFloat(prec).Sub(z, num(0.5, prec))
+	r.Mul(r, lz)
+	r.Sub(r, z)
+	r.Add(r, cached(halfLog2Pi, prec, func(prec uint) *big.Float {
+		twoPi := Pi(prec + guard)
+		twoPi.SetMantExp(twoPi, 1)
+		l := Log(twoPi, prec)
+		return l.SetMantExp(l, -1)
+	}))
+
+	zInv := newFloat(prec).Quo(num(1, prec), z)
+	z2Inv := newFloat(prec).Mul(zInv, zInv)
+	pow := zInv // z^-(2k-1)
+	for i, b := range bernoulliNumbers() {
+		k := int64(i + 1)
+		t := newFloat(prec).SetRat(b)
+		t.Quo(t, num(float64(2*k*(2*k-1)), prec))
+		t.Mul(t, pow)
+		r.Add(r, t)
+		pow.Mul(pow, z2Inv)
+	}
+
+	if prod.Cmp(num(1, 64)) != 0 {
+		r.Sub(r, Log(prod, prec))
+	}
+	return r
+}
+
+// sinPiReflect returns sin(πx) for x < 0, reducing x modulo 2 exactly first
+func sinPiReflect(x *big.Float, prec uint) *big.Float {
+	// f = x - 2·floor(x/2) is exact and lies in [0, 2)
+	h := newFloat(x.Prec()+64).SetMantExp(x, -1)
+	fl := new(big.Int)
+	h.Int(fl) // truncates toward zero
+	fh := newFloat(x.Prec() + 64).SetInt(fl)
+	if fh.Cmp(h) > 0 {
+		fh.Sub(fh, num(1, 64))
+	}
+	f := newFloat(x.Prec()+64).Sub(x, fh.SetMantExp(fh, 1))
+	t := Pi(prec)
+	t.Mul(t, f)
+	return Sin(t, prec)
+}
+
+// Lgamma returns log|Γ(x)| and the sign of Γ(x) for x not a non-positive
+// integer
+func Lgamma(x *big.Float, prec uint) (*big.Float, int) {
+	wp := prec + guard + 16
+	if x.Sign() > 0 {
+		return lgammaPos(x, wp).SetPrec(prec), 1
+	}
+	// Γ(x)·Γ(1-x) = π/sin(πx), and Γ(1-x) > 0
+	s := sinPiReflect(x, wp)
+	sign := s.Sign()
+	s.Abs(s)
+	r := Log(newFloat(wp).Quo(Pi(wp), s), wp)
+	r.Sub(r, lgammaPos(addExact(num(1, 64), newFloat(x.Prec()).Neg(x)), wp))
+	return r.SetPrec(prec), sign
+}
+
+// Gamma returns Γ(x) for x not a non-positive integer
+func Gamma(x *big.Float, prec uint) *big.Float {
+	// log Γ(x) has a magnitude of at most about 2^10 for arguments whose
+	// Γ is finite in any practical format, so e^l loses at most 10 bits
+	l, sign := Lgamma(x, prec+16)
+	r := Exp(l, prec+16)
+	if sign < 0 {
+		r.Neg(r)
+	}
+	return r.SetPrec(prec)
+}
+
+// Euler-Mascheroni constant cache
+var eulerCache = map[uint]*big.Float{}
+
+// EulerGamma returns the Euler-Mascheroni constant γ
+func EulerGamma(prec uint) *big.Float {
+	return cached(eulerCache, prec, func(prec uint) *big.Float {
+		// Brent-McMillan: with B_k = n^(2k)/k!², A_k = B_k·(H_k - log n),
+		// γ = Σ A_k / Σ B_k to within about e^(-4n)
+		wp := prec + guard
+		n := int64(float64(wp)/(4*log2e)) + 1
+		wp += bitsFor(2 * float64(n)) // the sums grow to about e^(2n)
+		n2 := num(float64(n*n), wp)
+		a := Log(num(float64(n), wp), wp)
+		a.Neg(a)
+		b := num(1, wp)
+		u := newFloat(wp).Set(a)
+		v := num(1, wp)
+		for k := int64(1); ; k++ {
+			kf := num(float64(k), wp)
+			b.Mul(b, n2)
+			b.Quo(b, kf)
+			b.Quo(b, kf)
+			a.Mul(a, n2)
+			a.Quo(a, kf)
+			a.Add(a, b)
+			a.Quo(a, kf)
+			if k > n && negligible(b, v, wp) && negligible(a, u, wp) {
+				break
+			}
+			u.Add(u, a)
+			v.Add(v, b)
+		}
+		return u.Quo(u, v).SetPrec(prec)
+	})
+}
+
+// besselSeriesMax is the argument up to which Bessel functions are summed
+// by their power series; above it the Hankel asymptotic expansion is
+// accurate to e^(-2x) < 2^-280
+const besselSeriesMax = 100
+
+// besselSeries returns Σ (-1)^k·(x/2)^(2k+n)/(k!·(k+n)!) for n >= 0, and
+// the same sum weighted by the harmonic-number coefficient h(k) when h is
+// not nil
+func besselSeries(x *big.Float, n int64, h func(k int64) *big.Float, prec uint) (j, weighted *big.Float) {
+	half := newFloat(prec).SetMantExp(x, -1)
+	q := newFloat(prec).Mul(half, half)
+	q.Neg(q)
+	term := num(1, prec)
+	for k := int64(1); k <= n; k++ {
+		term.Mul(term, half)
+		term.Quo(term, num(float64(k), prec))
+	}
+	j = newFloat(prec).Set(term)
+	weighted = newFloat(prec)
+	if h != nil {
+		weighted.Mul(term, h(0))
+	}
+	for k := int64(1); ; k++ {
+		term.Mul(term, q)
+		term.Quo(term, num(float64(k*(k+n)), prec))
+		if negligible(term, j, prec) && k > 2 {
+			break
+		}
+		j.Add(j, term)
+		if h != nil {
+			weighted.Add(weighted, newFloat(prec).Mul(term, h(k)))
+		}
+	}
+	return j, weighted
+}
+
+// besselPrec returns the working precision for the power series at x,
+// whose terms reach about e^x before cancelling
+func besselPrec(x *big.Float, prec uint) uint {
+	xf, _ := x.Float64()
+	return prec + guard + bitsFor(math.Abs(xf))
+}
+
+// harmonic returns a function of k computing the k-th harmonic number
+// plus offset, reusing the previous value
+func harmonic(prec uint, offset *big.Float) func(k int64) *big.Float {
+	hk := newFloat(prec)
+	last := int64(0)
+	return func(k int64) *big.Float {
+		for ; last < k; last++ {
+			hk.Add(hk, newFloat(prec).Quo(num(1, prec), num(float64(last+1), prec)))
+		}
+		return newFloat(prec).Add(hk, offset)
+	}
+}
+
+// hankel returns J_n(x) or Y_n(x) for large x > 0 from the asymptotic
+// expansion √(2/(πx))·(P·cos χ - Q·sin χ) and √(2/(πx))·(P·sin χ + Q·cos χ)
+// with χ = x - (n/2 + 1/4)·π
+func hankel(x *big.Float, n int64, y bool, prec uint) *big.Float {
+	wp := prec + guard
+	mu := float64(4 * n * n)
+	eightX := newFloat(wp).SetMantExp(x, 3)
+	p := num(1, wp)
+	q := newFloat(wp)
+	t := num(1, wp)
+	for k := int64(1); ; k++ {
+		next := newFloat(wp).Mul(t, num(mu-float64((2*k-1)*(2*k-1)), wp))
+		next.Quo(next, num(float64(k), wp))
+		next.Quo(next, eightX)
+		if next.Sign() == 0 || absCmp(next, t) >= 0 || negligible(next, p, wp) {
+			break
+		}
+		t = next
+		// P takes the even terms and Q the odd ones, with alternating signs
+		s := newFloat(wp).Set(t)
+		if (k/2)%2 == 1 {
+			s.Neg(s)
+		}
+		if k%2 == 0 {
+			p.Add(p, s)
+		} else {
+			q.Add(q, s)
+		}
+	}
+
+	// χ = x - (2n + 1)·π/4
+	chi := Pi(wp + uint(max(x.MantExp(nil), 0)))
+	chi.Mul(chi, num(float64(2*n+1), wp))
+	chi.SetMantExp(chi, -2)
+	chi.Sub(newFloat(chi.Prec()).Set(x), chi)
+	sin, cos := sinCos(chi, wp)
+
+	var r *big.Float
+	if y {
+		r = newFloat(wp).Mul(p, sin)
+		r.Add(r, newFloat(wp).Mul(q, cos))
+	} else {
+		r = newFloat(wp).Mul(p, cos)
+		r.Sub(r, newFloat(wp).Mul(q, sin))
+	}
+	amp := newFloat(wp).Mul(Pi(wp), x)
+	amp.Quo(num(2, wp), amp)
+	r.Mul(r, Sqrt(amp, wp))
+	return r.SetPrec(prec)
+}
+
+// J0 returns the order-zero Bessel function of the first kind
+func J0(x *big.Float, prec uint) *big.Float {
+	a := newFloat(x.Prec()).Abs(x)
+	if cmpAbs(a, besselSeriesMax) > 0 {
+		return hankel(a, 0, false, prec)
+	}
+	j, _ := besselSeries(a, 0, nil, besselPrec(a, prec))
+	return j.SetPrec(prec)
+}
+
+// J1 returns the order-one Bessel function of the first kind
+func J1(x *big.Float, prec uint) *big.Float {
+	a := newFloat(x.Prec()).Abs(x)
+	var r *big.Float
+	if cmpAbs(a, besselSeriesMax) > 0 {
+		r = hankel(a, 1, false, prec)
+	} else {
+		r, _ = besselSeries(a, 1, nil, besselPrec(a, prec))
+	}
+	if x.Sign() < 0 {
+		r.Neg(r)
+	}
+	return r.SetPrec(prec)
+}
+
+// Y0 returns the order-zero Bessel function of the second kind for x > 0
+func Y0(x *big.Float, prec uint) *big.Float {
+	if cmpAbs(x, besselSeriesMax) > 0 {
+		return hankel(x, 0, true, prec)
+	}
+	// Y0(x) = 2/π·((log(x/2) + γ)·J0(x) - Σ (-1)^k·H_k·(x²/4)^k/k!²)
+	wp := besselPrec(x, prec)
+	j, w := besselSeries(x, 0, harmonic(wp, newFloat(wp)), wp)
+	l := Log(newFloat(wp).SetMantExp(x, -1), wp)
+	l.Add(l, EulerGamma(wp))
+	l.Mul(l, j)
+	l.Sub(l, w)
+	l.Mul(l, num(2, wp))
+	return l.Quo(l, Pi(wp)).SetPrec(prec)
+}
+
+// Y1 returns the order-one Bessel function of the second kind for x > 0
+func Y1(x *big.Float, prec uint) *big.Float {
+	if cmpAbs(x, besselSeriesMax) > 0 {
+		return hankel(x, 1, true, prec)
+	}
+	// Y1(x) = -2/(πx) + 2/π·log(x/2)·J1(x)
+	//         - 1/π·Σ (-1)^k·(ψ(k+1) + ψ(k+2))·(x/2)^(2k+1)/(k!·(k+1)!)
+	// with ψ(k+1) + ψ(k+2) = 2·H_k + 1/(k+1) - 2γ
+	wp := besselPrec(x, prec)
+	g := EulerGamma(wp)
+	g.SetMantExp(g, 1)
+	g.Neg(g)
+	hk := harmonic(wp, newFloat(wp))
+	psi := func(k int64) *big.Float {
+		h := hk(k)
+		h.SetMantExp(h, 1)
+		h.Add(h, newFloat(wp).Quo(num(1, wp), num(float64(k+1), wp)))
+		return h.Add(h, g)
+	}
+	j, w := besselSeries(x, 1, psi, wp)
+
+	l := Log(newFloat(wp).SetMantExp(x, -1), wp)
+	l.Mul(l, j)
+	l.SetMantExp(l, 1)
+	l.Sub(l, w)
+	l.Sub(l, newFloat(wp).Quo(num(2, wp), x))
+	return l.Quo(l, Pi(wp)).SetPrec(prec)
+}
+
+// Jn returns the order-n Bessel function of the first kind
+func Jn(n int, x *big.Float, prec uint) *big.Float {
+	// J₋ₙ(x) = (-1)^n·Jₙ(x) and Jₙ(-x) = (-1)^n·Jₙ(x)
+	neg := n < 0 && n%2 != 0
+	if n < 0 {
+		n = -n
+	}
+	if x.Sign() < 0 && n%2 != 0 {
+		neg = !neg
+	}
+	a := newFloat(x.Prec()).Abs(x)
+	var r *big.Float
+	if cmpAbs(a, besselSeriesMax) > 0 {
+		r = hankel(a, int64(n), false, prec)
+	} else {
+		r, _ = besselSeries(a, int64(n), nil, besselPrec(a, prec))
+	}
+	if neg {
+		r.Neg(r)
+	}
+	return r.SetPrec(prec)
+}
+
+// Yn returns the order-n Bessel function of the second kind for x > 0,
+// by forward recurrence from Y0 and Y1, which is stable for Y
+func Yn(n int, x *big.Float, prec uint) *big.Float {
+	// Y₋ₙ(x) = (-1)^n·Yₙ(x)
+	neg := n < 0 && n%2 != 0
+	if n < 0 {
+		n = -n
+	}
+	wp := prec + guard
+	y0, y1 := Y0(x, wp), Y1(x, wp)
+	if n == 0 {
+		y1 = y0
+	}
+	// Yₖ₊₁(x) = 2k/x·Yₖ(x) - Yₖ₋₁(x)
+	for k := 1; k < n; k++ {
+		next := newFloat(wp).Mul(num(float64(2*k), wp), y1)
+		next.Quo(next, x)
+		y0, y1 = y1, next.Sub(next, y0)
+	}
+	if neg {
+		y1.Neg(y1)
+	}
+	return y1.SetPrec(prec)
+}
+
+// Erfinv returns the inverse error function of x for |x| < 1
+func Erfinv(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(prec)
+	}
+	xf, _ := x.Float64()
+	return erfNewton(Erf, x, math.Erfinv(xf), false, prec)
+}
+
+// Erfcinv returns the inverse complementary error function of x for
+// 0 < x < 2
+func Erfcinv(x *big.Float, prec uint) *big.Float {
+	if cmpAbs(x, 1) == 0 {
+		return newFloat(prec)
+	}
+	xf, _ := x.Float64()
+	return erfNewton(Erfc, x, math.Erfcinv(xf), true, prec)
+}
+
+// erfNewton solves f(y) = x by Newton's method from the float64 estimate
+// y, where f is erf or, if complement is set, erfc. The derivative is
+// ±2/√π·e^(-y²) and each step doubles the number of correct bits, up to
+// the rounding noise of f near wp bits.
+func erfNewton(f func(*big.Float, uint) *big.Float, x *big.Float, y float64, complement bool, prec uint) *big.Float {
+	wp := prec + guard
+	c := newFloat(wp).Quo(num(2, wp), Sqrt(Pi(wp), wp))
+	r := num(y, wp)
+	for {
+		d := f(r, wp)
+		d.Sub(d, x)
+		y2 := newFloat(wp).Mul(r, r)
+		slope := Exp(y2.Neg(y2), wp)
+		d.Quo(d, slope.Mul(slope, c))
+		if complement {
+			r.Add(r, d)
+		} else {
+			r.Sub(r, d)
+		}
+		if negligible(d, r, prec+guard/2) {
+			return r.SetPrec(prec)
+		}
+	}
+}
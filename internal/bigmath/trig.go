@@ -0,0 +1,277 @@
+package bigmath
+
+import "math/big"
+
+// reducePi2 returns r and the quadrant k mod 4 such that x = k·π/2 + r with
+// |r| <= π/4, computed with wp bits
+func reducePi2(x *big.Float, wp uint) (*big.Float, int) {
+	// π/2 needs as many extra bits as x has integer bits
+	wp += uint(max(x.MantExp(nil), 0))
+	halfPi := Pi(wp)
+	halfPi.SetMantExp(halfPi, -1)
+	q := newFloat(wp).Quo(x, halfPi)
+	k := roundToInt(q)
+	r := newFloat(wp).Mul(halfPi, newFloat(wp).SetInt64(k))
+	r.Sub(newFloat(wp).Set(x), r)
+	return r, int(((k % 4) + 4) % 4)
+}
+
+// sinCosSeries returns sin(r) and cos(r) by their Taylor series
+func sinCosSeries(r *big.Float, prec uint) (sin, cos *big.Float) {
+	r2 := newFloat(prec).Mul(r, r)
+	r2.Neg(r2)
+
+	sin = newFloat(prec).Set(r)
+	term := newFloat(prec).Set(r)
+	for n := int64(1); ; n++ {
+		term.Mul(term, r2)
+		term.Quo(term, num(float64((2*n)*(2*n+1)), prec))
+		if negligible(term, sin, prec) {
+			break
+		}
+		sin.Add(sin, term)
+	}
+
+	cos = num(1, prec)
+	term = num(1, prec)
+	for n := int64(1); ; n++ {
+		term.Mul(term, r2)
+		term.Quo(term, num(float64((2*n-1)*(2*n)), prec))
+		if negligible(term, cos, prec) {
+			break
+		}
+		cos.Add(cos, term)
+	}
+	return sin, cos
+}
+
+// sinCos returns sin(x) and cos(x)
+func sinCos(x *big.Float, prec uint) (sin, cos *big.Float) {
+	if x.Sign() == 0 {
+		return newFloat(prec), num(1, prec)
+	}
+	wp := prec + guard
+	r, k := reducePi2(x, wp)
+	s, c := sinCosSeries(r, wp)
+	switch k {
+	case 1:
+		s, c = c, s.Neg(s)
+	case 2:
+		s, c = s.Neg(s), c.Neg(c)
+	case 3:
+		s, c = c.Neg(c), s
+	}
+	return s.SetPrec(prec), c.SetPrec(prec)
+}
+
+// Sin returns the sine of x
+func Sin(x *big.Float, prec uint) *big.Float {
+	s, _ := sinCos(x, prec)
+	return s
+}
+
+// Cos returns the cosine of x
+func Cos(x *big.Float, prec uint) *big.Float {
+	_, c := sinCos(x, prec)
+	return c
+}
+
+// Tan returns the tangent of x
+func Tan(x *big.Float, prec uint) *big.Float {
+	s, c := sinCos(x, prec+guard)
+	return s.Quo(s, c).SetPrec(prec)
+}
+
+// Atan returns the arctangent of x
+func Atan(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(prec)
+	}
+	wp := prec + guard
+	a := newFloat(wp).Abs(x)
+	inverted := a.Cmp(num(1, wp)) > 0
+	if inverted {
+		a.Quo(num(1, wp), a)
+	}
+	// atan(a) = 2·atan(a / (1 + sqrt(1 + a²))); three halvings bring |a|
+	// below 0.1 so the series converges quickly
+	const halvings = 3
+	for range halvings {
+		d := newFloat(wp).Mul(a, a)
+		d.Add(d, num(1, wp))
+		d.Sqrt(d)
+		d.Add(d, num(1, wp))
+		a.Quo(a, d)
+	}
+	r := atanSeries(a, wp)
+	r.SetMantExp(r, halvings)
+	if inverted {
+		halfPi := Pi(wp)
+		halfPi.SetMantExp(halfPi, -1)
+		r.Sub(halfPi, r)
+	}
+	if x.Sign() < 0 {
+		r.Neg(r)
+	}
+	return r.SetPrec(prec)
+}
+
+// Asin returns the arcsine of x in [-1, 1]
+func Asin(x *big.Float, prec uint) *big.Float {
+	wp := prec + guard
+	if cmpAbs(x, 1) == 0 {
+		r := Pi(wp)
+		r.SetMantExp(r, -1)
+		if x.Sign() < 0 {
+			r.Neg(r)
+		}
+		return r.SetPrec(prec)
+	}
+	// asin(x) = atan(x / sqrt(1 - x²)), with 1 - x² formed exactly
+	d := newFloat(2*x.Prec()).Mul(x, x)
+	d = Sqrt(addExact(num(1, 64), d.Neg(d)), wp)
+	return Atan(d.Quo(x, d), prec)
+}
+
+// Acos returns the arccosine of x in [-1, 1]
+func Acos(x *big.Float, prec uint) *big.Float {
+	wp := prec + guard
+	if x.Cmp(num(-1, wp)) == 0 {
+		return Pi(prec)
+	}
+	// acos(x) = 2·atan(sqrt((1 - x) / (1 + x))), which is exactly zero at 1
+	// and avoids the cancellation of π/2 - asin(x)
+	n := addExact(num(1, 64), newFloat(x.Prec()).Neg(x))
+	q := newFloat(wp).Quo(n, addExact(num(1, 64), x))
+	r := Atan(Sqrt(q, wp), wp)
+	r.SetMantExp(r, 1)
+	return r.SetPrec(prec)
+}
+
+// Atan2 returns the angle of the point (x, y), in (-π, π]. Signed zeros
+// follow math.Atan2.
+func Atan2(y, x *big.Float, prec uint) *big.Float {
+	wp := prec + guard
+	if x.Sign() == 0 {
+		if y.Sign() == 0 {
+			if x.Signbit() {
+				return copySign(Pi(prec), y)
+			}
+			return newFloat(prec).Set(y)
+		}
+		r := Pi(prec)
+		r.SetMantExp(r, -1)
+		return copySign(r, y)
+	}
+	q := newFloat(wp).Quo(y, x)
+	r := Atan(q, wp)
+	if x.Sign() < 0 {
+		// Shift by π toward the sign of y
+		r.Add(r, copySign(Pi(wp), y))
+	}
+	return r.SetPrec(prec)
+}
+
+// copySign sets the sign of v to that of s and returns v
+func copySign(v, s *big.Float) *big.Float {
+	if v.Signbit() != s.Signbit() {
+		v.Neg(v)
+	}
+	return v
+}
+
+// Sinh returns the hyperbolic sine of x
+func Sinh(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(prec)
+	}
+	// (e^x - e^-x)/2 cancels for small x; the extra bits cover x down to
+	// 2^-guard relative to its magnitude
+	wp := prec + guard + uint(max(-x.MantExp(nil), 0))
+	e := Exp(x, wp)
+	inv := newFloat(wp).Quo(num(1, wp), e)
+	e.Sub(e, inv)
+	return e.SetMantExp(e, -1).SetPrec(prec)
+}
+
+// Cosh returns the hyperbolic cosine of x
+func Cosh(x *big.Float, prec uint) *big.Float {
+	wp := prec + guard
+	e := Exp(x, wp)
+	inv := newFloat(wp).Quo(num(1, wp), e)
+	e.Add(e, inv)
+	return e.SetMantExp(e, -1).SetPrec(prec)
+}
+
+// Tanh returns the hyperbolic tangent of x. For |x| >= 32 the result is
+// returned as ±(1 - 2^-60): the true value lies strictly between that and
+// ±1, and both round identically to any format with fewer than 60 bits.
+func Tanh(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(prec)
+	}
+	if cmpAbs(x, 32) >= 0 {
+		return copySign(nearOne(prec), x)
+	}
+	// tanh(x) = (e^2x - 1)/(e^2x + 1)
+	wp := prec + guard + uint(max(-x.MantExp(nil), 0))
+	t := newFloat(wp).SetMantExp(x, 1)
+	e := Exp(t, wp)
+	n := newFloat(wp).Sub(e, num(1, wp))
+	d := newFloat(wp).Add(e, num(1, wp))
+	return n.Quo(n, d).SetPrec(prec)
+}
+
+// Asinh returns the inverse hyperbolic sine of x
+func Asinh(x *big.Float, prec uint) *big.Float {
+	if x.Sign() == 0 {
+		return newFloat(prec)
+	}
+	// asinh|x| = log(|x| + sqrt(x² + 1)); the logarithm of a value near 1
+	// magnifies its relative error by about 1/|x|
+	wp := prec + guard + uint(max(-x.MantExp(nil), 0))
+	a := newFloat(wp).Abs(x)
+	r := newFloat(wp).Mul(a, a)
+	r.Add(r, num(1, wp))
+	r.Sqrt(r)
+	r.Add(r, a)
+	return copySign(Log(r, wp), x).SetPrec(prec)
+}
+
+// Acosh returns the inverse hyperbolic cosine of x >= 1
+func Acosh(x *big.Float, prec uint) *big.Float {
+	// acosh(x) = log(x + sqrt((x-1)(x+1))) with x - 1 and x + 1 exact
+	wp := prec + guard
+	xm := addExact(x, num(-1, 64))
+	if xm.Sign() == 0 {
+		return newFloat(prec)
+	}
+	// Near 1 the result behaves like sqrt(2(x-1)) and the logarithm
+	// magnifies errors by its inverse
+	wp += uint(max(-xm.MantExp(nil), 0))
+	r := newFloat(wp).Mul(xm, addExact(x, num(1, 64)))
+	r.Sqrt(r)
+	r.Add(r, x)
+	return Log(r, wp).SetPrec(prec)
+}
+
+// Atanh returns the inverse hyperbolic tangent of x, |x| < 1
+func Atanh(x *big.Float, prec uint) *big.Float {
+	wp := prec + guard
+	if cmpAbs(x, 0.5) < 0 {
+		return atanhSeries(newFloat(wp).Set(x), wp).SetPrec(prec)
+	}
+	// atanh(x) = log((1 + x)/(1 - x))/2 with 1 ± x exact
+	n := addExact(num(1, 64), x)
+	d := addExact(num(1, 64), newFloat(x.Prec()).Neg(x))
+	q := newFloat(wp).Quo(n, d)
+	l := Log(q, wp)
+	return l.SetMantExp(l, -1).SetPrec(prec)
+}
+
+// nearOne returns 1 - 2^-60, a stand-in for values in (1 - 2^-60, 1)
+func nearOne(prec uint) *big.Float {
+	prec = max(prec, 61)
+	r := num(1, prec)
+	return r.Sub(r, newFloat(prec).SetMantExp(num(1, prec), -60))
+}
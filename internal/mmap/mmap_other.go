@@ -0,0 +1,18 @@
+//go:build !unix
+
+// Package mmap maps files read-only into memory for the file format
+// subpackages.
+package mmap
+
+import "os"
+
+// Map returns the contents of the named file and a function that releases
+// them. Memory mapping is only used on Unix systems; elsewhere the file is
+// read into memory.
+func Map(name string) (data []byte, release func() error, err error) {
+	data, err = os.ReadFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
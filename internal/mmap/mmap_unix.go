@@ -0,0 +1,35 @@
+//go:build unix
+
+// Package mmap maps files read-only into memory for the file format
+// subpackages.
+package mmap
+
+import (
+	"os"
+	"syscall"
+)
+
+// Map returns the contents of the named file and a function that releases
+// them. On Unix systems the file is memory-mapped read-only, so the returned
+// slice must not be written to or used after release is called.
+func Map(name string) (data []byte, release func() error, err error) {
+	fd, err := os.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer fd.Close()
+
+	fi, err := fd.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if fi.Size() == 0 {
+		return []byte{}, func() error { return nil }, nil
+	}
+
+	data, err = syscall.Mmap(int(fd.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
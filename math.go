@@ -2,18 +2,41 @@ package float16
 
 import (
 	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/zerfoo/float16/internal/bigmath"
 )
 
 // Mathematical functions for Float16
-
-// Sqrt returns the square root of the Float16 value
+//
+// Sqrt, Cbrt, Pow, Exp, Exp2, Exp10, Log, Log2, Log10, Sin, Cos, Tan, Sinh,
+// Cosh, Tanh and Erf round their result once, with DefaultRounding, and
+// each has a WithRounding variant that takes the rounding mode instead. These are not named WithMode like AddWithMode or
+// FromFloat64WithMode: they have no arithmetic or conversion mode to choose
+// and no error to report, since a domain error gives NaN and overflow ±Inf
+// as in package math, so the rounding mode is their only parameter.
+//
+// The functions are designed to be correctly rounded. go test checks that
+// in every rounding mode on a sample of the inputs; ACCURACY.md records the
+// last run over all of them, made with the -exhaustive flag of
+// internal/accuracy.
+
+// Sqrt returns the square root of the Float16 value, rounded with
+// DefaultRounding
 func Sqrt(f Float16) Float16 {
+	return SqrtWithRounding(f, DefaultRounding)
+}
+
+// SqrtWithRounding returns the square root of f, rounded in the given rounding
+// mode
+func SqrtWithRounding(f Float16, rounding RoundingMode) Float16 {
 	// Handle special cases
 	if f.IsZero() {
 		return f // Preserve sign of zero
 	}
 	if f.IsNaN() {
-		return f
+		return f.Quiet()
 	}
 	if f.IsInf(1) {
 		return PositiveInfinity
@@ -23,25 +46,17 @@ func Sqrt(f Float16) Float16 {
 		return QuietNaN
 	}
 
-	// Use float32 for computation and convert back
-	f32 := f.ToFloat32()
-	result := float32(math.Sqrt(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Sqrt, bigmath.Sqrt)
 }
 
-// Cbrt returns the cube root of the Float16 value
+// Cbrt returns the cube root of the Float16 value, rounded with DefaultRounding
 func Cbrt(f Float16) Float16 {
-	switch f {
-	case 0x3C00: // 1.0
-		return 0x3C00 // 1.0
-	case 0x4800: // 8.0
-		return 0x4000 // 2.0
-	case 0x51C0: // 27.0
-		return 0x4240 // 3.0
-	case 0x5800: // 64.0
-		return 0x4400 // 4.0
-	}
+	return CbrtWithRounding(f, DefaultRounding)
+}
 
+// CbrtWithRounding returns the cube root of f, rounded in the given rounding
+// mode
+func CbrtWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() || f.IsNaN() {
 		return f
 	}
@@ -49,41 +64,39 @@ func Cbrt(f Float16) Float16 {
 		return f
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Cbrt(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Cbrt, bigmath.Cbrt)
 }
 
-// Pow returns f raised to the power of exp
+// Pow returns f raised to the power of exp, rounded with DefaultRounding
 func Pow(f, exp Float16) Float16 {
-	// Handle special cases according to IEEE 754
-	if exp.IsZero() {
-		return FromFloat32(1)
-	}
-	if f.IsZero() {
-		if exp.Signbit() {
-			return PositiveInfinity // 0^(-y) = +∞
-		}
-		return PositiveZero // 0^y = 0 for positive y
-	}
-	if f.IsNaN() || exp.IsNaN() {
+	return PowWithRounding(f, exp, DefaultRounding)
+}
+
+// PowWithRounding returns f raised to the power of exp, rounded in the given
+// rounding mode. Special cases are as for math.Pow.
+func PowWithRounding(f, exp Float16, rounding RoundingMode) Float16 {
+	x, y := f.ToFloat64(), exp.ToFloat64()
+	result := math.Pow(x, y)
+	switch {
+	case math.IsNaN(result):
 		return QuietNaN
-	}
-	if f.IsInf(0) {
-		if exp.Signbit() {
-			return PositiveZero // ∞^(-y) = 0
-		}
-		return PositiveInfinity // ∞^y = ∞
+	case f.IsZero() || exp.IsZero() || f.IsInf(0) || exp.IsInf(0) || f.IsNaN() || exp.IsNaN() || x == 1:
+		// Handle special cases according to IEEE 754; all are exact
+		return FromFloat64(result)
 	}
 
-	f32 := f.ToFloat32()
-	exp32 := exp.ToFloat32()
-	result := float32(math.Pow(float64(f32), float64(exp32)))
-	return FromFloat32(result)
+	return roundResult(result, rounding, func(prec uint) *big.Float {
+		return bigmath.Pow(big.NewFloat(x), big.NewFloat(y), prec)
+	})
 }
 
-// Exp returns e^f
+// Exp returns e^f, rounded with DefaultRounding
 func Exp(f Float16) Float16 {
+	return ExpWithRounding(f, DefaultRounding)
+}
+
+// ExpWithRounding returns e^f, rounded in the given rounding mode
+func ExpWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return FromFloat32(1)
 	}
@@ -97,13 +110,16 @@ func Exp(f Float16) Float16 {
 		return PositiveZero
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Exp(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Exp, bigmath.Exp)
 }
 
-// Exp2 returns 2^f
+// Exp2 returns 2^f, rounded with DefaultRounding
 func Exp2(f Float16) Float16 {
+	return Exp2WithRounding(f, DefaultRounding)
+}
+
+// Exp2WithRounding returns 2^f, rounded in the given rounding mode
+func Exp2WithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return FromFloat32(1)
 	}
@@ -117,18 +133,68 @@ func Exp2(f Float16) Float16 {
 		return PositiveZero
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Exp2(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Exp2, bigmath.Exp2)
 }
 
-// Exp10 returns 10^f
+// Exp10 returns 10^f, rounded with DefaultRounding
 func Exp10(f Float16) Float16 {
-	return FromFloat32(10)
+	return Exp10WithRounding(f, DefaultRounding)
 }
 
-// Log returns the natural logarithm of f
+// Exp10WithRounding returns 10^f, rounded in the given rounding mode
+func Exp10WithRounding(f Float16, rounding RoundingMode) Float16 {
+	if f.IsZero() {
+		return FromFloat32(1)
+	}
+	if f.IsNaN() {
+		return f
+	}
+	if f.IsInf(1) {
+		return PositiveInfinity
+	}
+	if f.IsInf(-1) {
+		return PositiveZero
+	}
+
+	return roundUnary(f, rounding, exp10, bigmath.Exp10)
+}
+
+// exp10 returns 10^x in float64
+func exp10(x float64) float64 {
+	return math.Pow(10, x)
+}
+
+// Expm1 returns e^f - 1, accurate even when f is near zero
+func Expm1(f Float16) Float16 {
+	if f.IsZero() {
+		return f // Preserve sign of zero
+	}
+	if f.IsNaN() {
+		return f
+	}
+	if f.IsInf(1) {
+		return PositiveInfinity
+	}
+	if f.IsInf(-1) {
+		return FromFloat32(-1)
+	}
+
+	return roundUnary(f, DefaultRounding, math.Expm1, bigmath.Expm1)
+}
+
+// Pow10 returns 10^n; it is 0 for n < -7 and +Inf for n > 4
+func Pow10(n int) Float16 {
+	return FromFloat64(math.Pow10(n))
+}
+
+// Log returns the natural logarithm of f, rounded with DefaultRounding
 func Log(f Float16) Float16 {
+	return LogWithRounding(f, DefaultRounding)
+}
+
+// LogWithRounding returns the natural logarithm of f, rounded in the given
+// rounding mode
+func LogWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return NegativeInfinity
 	}
@@ -142,13 +208,17 @@ func Log(f Float16) Float16 {
 		return QuietNaN // log of negative number
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Log(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Log, bigmath.Log)
 }
 
-// Log2 returns the base-2 logarithm of f
+// Log2 returns the base-2 logarithm of f, rounded with DefaultRounding
 func Log2(f Float16) Float16 {
+	return Log2WithRounding(f, DefaultRounding)
+}
+
+// Log2WithRounding returns the base-2 logarithm of f, rounded in the given
+// rounding mode
+func Log2WithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return NegativeInfinity
 	}
@@ -162,13 +232,17 @@ func Log2(f Float16) Float16 {
 		return QuietNaN
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Log2(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Log2, bigmath.Log2)
 }
 
-// Log10 returns the base-10 logarithm of f
+// Log10 returns the base-10 logarithm of f, rounded with DefaultRounding
 func Log10(f Float16) Float16 {
+	return Log10WithRounding(f, DefaultRounding)
+}
+
+// Log10WithRounding returns the base-10 logarithm of f, rounded in the given
+// rounding mode
+func Log10WithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return NegativeInfinity
 	}
@@ -182,15 +256,77 @@ func Log10(f Float16) Float16 {
 		return QuietNaN
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Log10(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Log10, bigmath.Log10)
+}
+
+// Log1p returns the natural logarithm of 1 + f, accurate even when f is
+// near zero
+func Log1p(f Float16) Float16 {
+	if f.IsZero() {
+		return f // Preserve sign of zero
+	}
+	if f.IsNaN() {
+		return f
+	}
+	if f.IsInf(1) {
+		return PositiveInfinity
+	}
+	if f == FromFloat32(-1) {
+		return NegativeInfinity
+	}
+	if f.ToFloat32() < -1 {
+		return QuietNaN
+	}
+
+	return roundUnary(f, DefaultRounding, math.Log1p, bigmath.Log1p)
+}
+
+// Logb returns the binary exponent of f
+func Logb(f Float16) Float16 {
+	if f.IsZero() {
+		return NegativeInfinity
+	}
+	if f.IsNaN() {
+		return f
+	}
+	if f.IsInf(0) {
+		return PositiveInfinity
+	}
+	return FromFloat32(float32(ilogb(f)))
+}
+
+// Ilogb returns the binary exponent of f as an integer. Ilogb(±Inf) and
+// Ilogb(NaN) are math.MaxInt32, and Ilogb(0) is math.MinInt32.
+func Ilogb(f Float16) int {
+	if f.IsZero() {
+		return math.MinInt32
+	}
+	if f.IsNaN() || f.IsInf(0) {
+		return math.MaxInt32
+	}
+	return ilogb(f)
+}
+
+// ilogb returns the binary exponent of a finite non-zero f
+func ilogb(f Float16) int {
+	exp := int(f&ExponentMask) >> MantissaLen
+	if exp == ExponentZero {
+		// Subnormal: the leading mantissa bit sets the exponent
+		return bits.Len16(uint16(f&MantissaMask)) - 1 - (ExponentBias - 1) - MantissaLen
+	}
+	return exp - ExponentBias
 }
 
 // Trigonometric functions
 
-// Sin returns the sine of f (in radians)
+// Sin returns the sine of f (in radians), rounded with DefaultRounding
 func Sin(f Float16) Float16 {
+	return SinWithRounding(f, DefaultRounding)
+}
+
+// SinWithRounding returns the sine of f (in radians), rounded in the given
+// rounding mode
+func SinWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return f // Preserve sign of zero
 	}
@@ -198,13 +334,17 @@ func Sin(f Float16) Float16 {
 		return QuietNaN
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Sin(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Sin, bigmath.Sin)
 }
 
-// Cos returns the cosine of f (in radians)
+// Cos returns the cosine of f (in radians), rounded with DefaultRounding
 func Cos(f Float16) Float16 {
+	return CosWithRounding(f, DefaultRounding)
+}
+
+// CosWithRounding returns the cosine of f (in radians), rounded in the given
+// rounding mode
+func CosWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return FromFloat32(1)
 	}
@@ -212,13 +352,17 @@ func Cos(f Float16) Float16 {
 		return QuietNaN
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Cos(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Cos, bigmath.Cos)
 }
 
-// Tan returns the tangent of f (in radians)
+// Tan returns the tangent of f (in radians), rounded with DefaultRounding
 func Tan(f Float16) Float16 {
+	return TanWithRounding(f, DefaultRounding)
+}
+
+// TanWithRounding returns the tangent of f (in radians), rounded in the given
+// rounding mode
+func TanWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return f // Preserve sign of zero
 	}
@@ -226,9 +370,12 @@ func Tan(f Float16) Float16 {
 		return QuietNaN
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Tan(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Tan, bigmath.Tan)
+}
+
+// Sincos returns Sin(f), Cos(f)
+func Sincos(f Float16) (sin, cos Float16) {
+	return Sin(f), Cos(f)
 }
 
 // Asin returns the arcsine of f
@@ -261,9 +408,7 @@ func Acos(f Float16) Float16 {
 		return QuietNaN
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Acos(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, DefaultRounding, math.Acos, bigmath.Acos)
 }
 
 // Atan returns the arctangent of f
@@ -281,9 +426,7 @@ func Atan(f Float16) Float16 {
 		return Div(Pi, FromFloat32(2)).Neg()
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Atan(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, DefaultRounding, math.Atan, bigmath.Atan)
 }
 
 // Atan2 returns the arctangent of y/x
@@ -292,16 +435,27 @@ func Atan2(y, x Float16) Float16 {
 		return QuietNaN
 	}
 
-	y32 := y.ToFloat32()
-	x32 := x.ToFloat32()
-	result := float32(math.Atan2(float64(y32), float64(x32)))
-	return FromFloat32(result)
+	yf, xf := y.ToFloat64(), x.ToFloat64()
+	result := math.Atan2(yf, xf)
+	// Zero y and infinite operands give exact multiples of π/4 or ±0
+	if y.IsZero() || !y.IsFinite() || !x.IsFinite() {
+		return FromFloat64(result)
+	}
+	return roundResult(result, DefaultRounding, func(prec uint) *big.Float {
+		return bigmath.Atan2(big.NewFloat(yf), big.NewFloat(xf), prec)
+	})
 }
 
 // Hyperbolic functions
 
-// Sinh returns the hyperbolic sine of f
+// Sinh returns the hyperbolic sine of f, rounded with DefaultRounding
 func Sinh(f Float16) Float16 {
+	return SinhWithRounding(f, DefaultRounding)
+}
+
+// SinhWithRounding returns the hyperbolic sine of f, rounded in the given
+// rounding mode
+func SinhWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return f
 	}
@@ -312,13 +466,17 @@ func Sinh(f Float16) Float16 {
 		return f
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Sinh(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Sinh, bigmath.Sinh)
 }
 
-// Cosh returns the hyperbolic cosine of f
+// Cosh returns the hyperbolic cosine of f, rounded with DefaultRounding
 func Cosh(f Float16) Float16 {
+	return CoshWithRounding(f, DefaultRounding)
+}
+
+// CoshWithRounding returns the hyperbolic cosine of f, rounded in the given
+// rounding mode
+func CoshWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return FromFloat32(1)
 	}
@@ -329,13 +487,17 @@ func Cosh(f Float16) Float16 {
 		return PositiveInfinity
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Cosh(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Cosh, bigmath.Cosh)
 }
 
-// Tanh returns the hyperbolic tangent of f
+// Tanh returns the hyperbolic tangent of f, rounded with DefaultRounding
 func Tanh(f Float16) Float16 {
+	return TanhWithRounding(f, DefaultRounding)
+}
+
+// TanhWithRounding returns the hyperbolic tangent of f, rounded in the given
+// rounding mode
+func TanhWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return f
 	}
@@ -349,9 +511,48 @@ func Tanh(f Float16) Float16 {
 		return FromFloat32(-1)
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Tanh(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Tanh, bigmath.Tanh)
+}
+
+// Asinh returns the inverse hyperbolic sine of f
+func Asinh(f Float16) Float16 {
+	if f.IsZero() || f.IsNaN() || f.IsInf(0) {
+		return f
+	}
+
+	return roundUnary(f, DefaultRounding, math.Asinh, bigmath.Asinh)
+}
+
+// Acosh returns the inverse hyperbolic cosine of f
+func Acosh(f Float16) Float16 {
+	if f.IsNaN() {
+		return f
+	}
+	if f.IsInf(1) {
+		return PositiveInfinity
+	}
+	if f.ToFloat32() < 1 {
+		return QuietNaN
+	}
+
+	return roundUnary(f, DefaultRounding, math.Acosh, bigmath.Acosh)
+}
+
+// Atanh returns the inverse hyperbolic tangent of f
+func Atanh(f Float16) Float16 {
+	if f.IsZero() || f.IsNaN() {
+		return f
+	}
+	switch x := f.ToFloat32(); {
+	case x == 1:
+		return PositiveInfinity
+	case x == -1:
+		return NegativeInfinity
+	case x > 1 || x < -1:
+		return QuietNaN
+	}
+
+	return roundUnary(f, DefaultRounding, math.Atanh, bigmath.Atanh)
 }
 
 // Rounding and truncation functions
@@ -413,15 +614,15 @@ func Trunc(f Float16) Float16 {
 
 // Mod returns the floating-point remainder of f/divisor
 func Mod(f, divisor Float16) Float16 {
+	if f.IsNaN() || divisor.IsNaN() {
+		return propagateNaN(f, divisor)
+	}
 	if divisor.IsZero() {
 		return QuietNaN
 	}
 	if f.IsZero() {
 		return f
 	}
-	if f.IsNaN() || divisor.IsNaN() {
-		return QuietNaN
-	}
 	if f.IsInf(0) || divisor.IsInf(0) {
 		return QuietNaN
 	}
@@ -434,15 +635,15 @@ func Mod(f, divisor Float16) Float16 {
 
 // Remainder returns the IEEE 754 floating-point remainder of f/divisor
 func Remainder(f, divisor Float16) Float16 {
+	if f.IsNaN() || divisor.IsNaN() {
+		return propagateNaN(f, divisor)
+	}
 	if divisor.IsZero() {
 		return QuietNaN
 	}
 	if f.IsZero() {
 		return f
 	}
-	if f.IsNaN() || divisor.IsNaN() {
-		return QuietNaN
-	}
 	if f.IsInf(0) {
 		return QuietNaN
 	}
@@ -458,7 +659,7 @@ func Remainder(f, divisor Float16) Float16 {
 
 // Mathematical constants as Float16 values
 var (
-		E       = FromFloat32(float32(math.E))       // Euler's number
+	E       = FromFloat32(float32(math.E))       // Euler's number
 	Pi      = FromFloat32(float32(math.Pi))      // Pi
 	Phi     = FromFloat32(float32(math.Phi))     // Golden ratio
 	Sqrt2   = FromFloat32(float32(math.Sqrt2))   // Square root of 2
@@ -544,10 +745,38 @@ func Hypot(f, g Float16) Float16 {
 		return QuietNaN
 	}
 
-	f32 := f.ToFloat32()
-	g32 := g.ToFloat32()
-	result := float32(math.Hypot(float64(f32), float64(g32)))
-	return FromFloat32(result)
+	ff, gf := f.ToFloat64(), g.ToFloat64()
+	return roundResult(math.Hypot(ff, gf), DefaultRounding, func(prec uint) *big.Float {
+		return bigmath.Hypot(big.NewFloat(ff), big.NewFloat(gf), prec)
+	})
+}
+
+// FMA returns x * y + z, computed with only one rounding. Infinities
+// propagate as in math.FMA, with Inf × 0 and Inf - Inf giving NaN, and a NaN
+// operand gives the first NaN, quieted.
+func FMA(x, y, z Float16) Float16 {
+	switch {
+	case x.IsNaN() || y.IsNaN():
+		return propagateNaN(x, y)
+	case z.IsNaN():
+		return z.Quiet()
+	}
+	xf, yf, zf := x.ToFloat64(), y.ToFloat64(), z.ToFloat64()
+	result := math.FMA(xf, yf, zf)
+	// The product of two Float16 values is exact in float64, so a zero result
+	// is exact, as are the results for non-finite operands
+	if result == 0 || !x.IsFinite() || !y.IsFinite() || !z.IsFinite() {
+		if result == 0 && DefaultRounding == RoundTowardNegative && math.Signbit(xf*yf) != math.Signbit(zf) {
+			return NegativeZero // an exact zero sum is -0 when rounding down
+		}
+		return FromFloat64(result)
+	}
+
+	return roundResult(result, DefaultRounding, func(prec uint) *big.Float {
+		// The exact sum spans at most 2^16 down to 2^-48
+		p := new(big.Float).SetPrec(prec).Mul(big.NewFloat(xf), big.NewFloat(yf))
+		return p.Add(p, big.NewFloat(zf))
+	})
 }
 
 // Gamma returns the Gamma function of f
@@ -562,9 +791,15 @@ func Gamma(f Float16) Float16 {
 		return PositiveInfinity
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Gamma(float64(f32)))
-	return FromFloat32(result)
+	x := f.ToFloat64()
+	result := math.Gamma(x)
+	// The poles at zero and the negative integers give ±Inf and NaN
+	if f.IsZero() || math.IsNaN(result) {
+		return FromFloat64(result)
+	}
+	return roundResult(result, DefaultRounding, func(prec uint) *big.Float {
+		return bigmath.Gamma(big.NewFloat(x), prec)
+	})
 }
 
 // Lgamma returns the natural logarithm and sign of Gamma(f)
@@ -573,9 +808,16 @@ func Lgamma(f Float16) (Float16, int) {
 		return f, 1
 	}
 
-	f32 := f.ToFloat32()
-	lgamma, sign := math.Lgamma(float64(f32))
-	return FromFloat32(float32(lgamma)), sign
+	x := f.ToFloat64()
+	lgamma, sign := math.Lgamma(x)
+	// Infinities, poles and the exact zeros at 1 and 2
+	if math.IsInf(lgamma, 0) || x == 1 || x == 2 {
+		return FromFloat64(lgamma), sign
+	}
+	return roundResult(lgamma, DefaultRounding, func(prec uint) *big.Float {
+		l, _ := bigmath.Lgamma(big.NewFloat(x), prec)
+		return l
+	}), sign
 }
 
 // J0 returns the order-zero Bessel function of the first kind
@@ -601,47 +843,67 @@ func J1(f Float16) Float16 {
 		return PositiveZero
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.J1(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, DefaultRounding, math.J1, bigmath.J1)
 }
 
 // Y0 returns the order-zero Bessel function of the second kind
 func Y0(f Float16) Float16 {
-	if f.IsNaN() || f.Signbit() {
-		return QuietNaN
-	}
+	// -0 is a zero, not a negative argument
 	if f.IsZero() {
 		return NegativeInfinity
 	}
+	if f.IsNaN() || f.Signbit() {
+		return QuietNaN
+	}
 	if f.IsInf(1) {
 		return PositiveZero
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Y0(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, DefaultRounding, math.Y0, bigmath.Y0)
 }
 
 // Y1 returns the order-one Bessel function of the second kind
 func Y1(f Float16) Float16 {
-	if f.IsNaN() || f.Signbit() {
-		return QuietNaN
-	}
 	if f.IsZero() {
 		return NegativeInfinity
 	}
+	if f.IsNaN() || f.Signbit() {
+		return QuietNaN
+	}
 	if f.IsInf(1) {
 		return PositiveZero
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Y1(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, DefaultRounding, math.Y1, bigmath.Y1)
+}
+
+// Jn returns the order-n Bessel function of the first kind
+func Jn(n int, f Float16) Float16 {
+	if f.IsNaN() {
+		return f
+	}
+
+	return FromFloat64(math.Jn(n, f.ToFloat64()))
 }
 
-// Erf returns the error function of f
+// Yn returns the order-n Bessel function of the second kind. Yn(n, 0) is
+// -Inf, or +Inf for negative odd n, and Yn(n, x < 0) is NaN.
+func Yn(n int, f Float16) Float16 {
+	if f.IsNaN() {
+		return f
+	}
+
+	return FromFloat64(math.Yn(n, f.ToFloat64()))
+}
+
+// Erf returns the error function of f, rounded with DefaultRounding
 func Erf(f Float16) Float16 {
+	return ErfWithRounding(f, DefaultRounding)
+}
+
+// ErfWithRounding returns the error function of f, rounded in the given
+// rounding mode
+func ErfWithRounding(f Float16, rounding RoundingMode) Float16 {
 	if f.IsZero() {
 		return f
 	}
@@ -655,9 +917,7 @@ func Erf(f Float16) Float16 {
 		return FromFloat32(-1)
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Erf(float64(f32)))
-	return FromFloat32(result)
+	return roundUnary(f, rounding, math.Erf, bigmath.Erf)
 }
 
 // Erfc returns the complementary error function of f
@@ -672,7 +932,28 @@ func Erfc(f Float16) Float16 {
 		return FromFloat32(2)
 	}
 
-	f32 := f.ToFloat32()
-	result := float32(math.Erfc(float64(f32)))
-	return FromFloat32(result)
+	if f.ToFloat32() > 10 {
+		// erfc(10) < 2^-150 is far below half the smallest subnormal
+		result, _, _ := roundFloat64(0x1p-60, DefaultRounding)
+		return result
+	}
+	return roundUnary(f, DefaultRounding, math.Erfc, bigmath.Erfc)
+}
+
+// Erfinv returns the inverse error function of f
+func Erfinv(f Float16) Float16 {
+	if f.IsNaN() {
+		return f
+	}
+
+	return FromFloat64(math.Erfinv(f.ToFloat64()))
+}
+
+// Erfcinv returns the inverse of Erfc(f)
+func Erfcinv(f Float16) Float16 {
+	if f.IsNaN() {
+		return f
+	}
+
+	return FromFloat64(math.Erfcinv(f.ToFloat64()))
 }
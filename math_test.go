@@ -14,8 +14,8 @@ func TestCbrtFunction(t *testing.T) {
 		// Basic test cases
 		{"Cbrt(1.0)", 0x3C00, 0x3C00},  // 1.0 -> 1.0
 		{"Cbrt(8.0)", 0x4800, 0x4000},  // 8.0 -> 2.0
-		{"Cbrt(27.0)", 0x51C0, 0x4240}, // 27.0 -> 3.0
-		{"Cbrt(64.0)", 0x5800, 0x4400}, // 64.0 -> 4.0
+		{"Cbrt(27.0)", 0x4EC0, 0x4200}, // 27.0 -> 3.0
+		{"Cbrt(64.0)", 0x5400, 0x4400}, // 64.0 -> 4.0
 
 		// Additional test cases for better coverage
 		{"Cbrt(0.0)", 0x0000, 0x0000},   // +0.0 -> +0.0
@@ -145,7 +145,7 @@ func TestBasicMathFunctions(t *testing.T) {
 		{"Sqrt(2.0)", Sqrt, 0x4000, 0x3DA8, 1e-3},  // 2.0 -> ~1.414 (approximate)
 
 		// Cbrt tests
-		{"Cbrt(27.0)", Cbrt, 0x51C0, 0x4240, 1e-5},  // 27.0 -> 3.0
+		{"Cbrt(27.0)", Cbrt, 0x4EC0, 0x4200, 1e-5},  // 27.0 -> 3.0
 		{"Cbrt(8.0)", Cbrt, 0x4800, 0x4000, 1e-5},   // 8.0 -> 2.0
 		{"Cbrt(1.0)", Cbrt, 0x3C00, 0x3C00, 1e-5},   // 1.0 -> 1.0
 		{"Cbrt(0.125)", Cbrt, 0x3000, 0x3800, 1e-3}, // 0.125 -> 0.5 (approximate)
@@ -293,6 +293,12 @@ func TestBesselFunctions(t *testing.T) {
 	}{
 		{"J0(0.0)", J0, 0x0000, 0x3C00}, // J0(0) = 1.0
 		{"J1(0.0)", J1, 0x0000, 0x0000}, // J1(0) = 0.0
+		{"Y0(0.0)", Y0, 0x0000, NegativeInfinity},
+		{"Y0(-0.0)", Y0, 0x8000, NegativeInfinity}, // as math.Y0, not NaN
+		{"Y0(-1.0)", Y0, 0xBC00, QuietNaN},
+		{"Y1(0.0)", Y1, 0x0000, NegativeInfinity},
+		{"Y1(-0.0)", Y1, 0x8000, NegativeInfinity},
+		{"Y1(-1.0)", Y1, 0xBC00, QuietNaN},
 	}
 
 	for _, tt := range tests {
@@ -332,12 +338,12 @@ func TestPow_Extra(t *testing.T) {
 		f, exp Float16
 		want   Float16
 	}{
-		{"1^x = 1", ToFloat16(1.0), ToFloat16(123.45), ToFloat16(1.0)},
-		{"x^1 = x", ToFloat16(123.45), ToFloat16(1.0), ToFloat16(123.45)},
-		{"-1^2 = 1", ToFloat16(-1.0), ToFloat16(2.0), ToFloat16(1.0)},
-		{"-1^3 = -1", ToFloat16(-1.0), ToFloat16(3.0), ToFloat16(-1.0)},
-		{"inf^2 = inf", PositiveInfinity, ToFloat16(2.0), PositiveInfinity},
-		{"inf^-2 = 0", PositiveInfinity, ToFloat16(-2.0), PositiveZero},
+		{"1^x = 1", FromFloat32(1.0), FromFloat32(123.45), FromFloat32(1.0)},
+		{"x^1 = x", FromFloat32(123.45), FromFloat32(1.0), FromFloat32(123.45)},
+		{"-1^2 = 1", FromFloat32(-1.0), FromFloat32(2.0), FromFloat32(1.0)},
+		{"-1^3 = -1", FromFloat32(-1.0), FromFloat32(3.0), FromFloat32(-1.0)},
+		{"inf^2 = inf", PositiveInfinity, FromFloat32(2.0), PositiveInfinity},
+		{"inf^-2 = 0", PositiveInfinity, FromFloat32(-2.0), PositiveZero},
 	}
 
 	for _, tt := range tests {
@@ -355,12 +361,12 @@ func TestMod_Extra(t *testing.T) {
 		f, d Float16
 		want Float16
 	}{
-		{"5.0 mod 3.0", ToFloat16(5.0), ToFloat16(3.0), ToFloat16(2.0)},
-		{"-5.0 mod 3.0", ToFloat16(-5.0), ToFloat16(3.0), ToFloat16(-2.0)},
-		{"5.0 mod -3.0", ToFloat16(5.0), ToFloat16(-3.0), ToFloat16(2.0)},
-		{"-5.0 mod -3.0", ToFloat16(-5.0), ToFloat16(-3.0), ToFloat16(-2.0)},
-		{"inf mod 1", PositiveInfinity, ToFloat16(1.0), QuietNaN},
-		{"1 mod inf", ToFloat16(1.0), PositiveInfinity, QuietNaN},
+		{"5.0 mod 3.0", FromFloat32(5.0), FromFloat32(3.0), FromFloat32(2.0)},
+		{"-5.0 mod 3.0", FromFloat32(-5.0), FromFloat32(3.0), FromFloat32(-2.0)},
+		{"5.0 mod -3.0", FromFloat32(5.0), FromFloat32(-3.0), FromFloat32(2.0)},
+		{"-5.0 mod -3.0", FromFloat32(-5.0), FromFloat32(-3.0), FromFloat32(-2.0)},
+		{"inf mod 1", PositiveInfinity, FromFloat32(1.0), QuietNaN},
+		{"1 mod inf", FromFloat32(1.0), PositiveInfinity, QuietNaN},
 	}
 
 	for _, tt := range tests {
@@ -382,3 +388,301 @@ func TestHypot_Inf(t *testing.T) {
 		t.Errorf("Hypot(inf, nan) = %v, want +Inf", got)
 	}
 }
+
+// sameFloat16 reports whether a and b have the same bits, or are both NaN
+func sameFloat16(a, b Float16) bool {
+	return a == b || a.IsNaN() && b.IsNaN()
+}
+
+func TestExpm1(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  Float16
+		want Float16
+	}{
+		{"Expm1(0)", PositiveZero, PositiveZero},
+		{"Expm1(-0)", NegativeZero, NegativeZero},
+		{"Expm1(inf)", PositiveInfinity, PositiveInfinity},
+		{"Expm1(-inf)", NegativeInfinity, FromFloat32(-1.0)},
+		{"Expm1(NaN)", QuietNaN, QuietNaN},
+		{"Expm1(tiny)", FromFloat32(1e-4), FromFloat64(math.Expm1(float64(FromFloat32(1e-4).ToFloat32())))},
+		{"Expm1(100)", FromFloat32(100), PositiveInfinity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Expm1(tt.arg); !sameFloat16(got, tt.want) {
+				t.Errorf("Expm1() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLog1p(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  Float16
+		want Float16
+	}{
+		{"Log1p(0)", PositiveZero, PositiveZero},
+		{"Log1p(-0)", NegativeZero, NegativeZero},
+		{"Log1p(-1)", FromFloat32(-1.0), NegativeInfinity},
+		{"Log1p(-2)", FromFloat32(-2.0), QuietNaN},
+		{"Log1p(inf)", PositiveInfinity, PositiveInfinity},
+		{"Log1p(-inf)", NegativeInfinity, QuietNaN},
+		{"Log1p(NaN)", QuietNaN, QuietNaN},
+		{"Log1p(min subnormal)", SmallestSubnormal, SmallestSubnormal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Log1p(tt.arg); !sameFloat16(got, tt.want) {
+				t.Errorf("Log1p() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAsinh(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  Float16
+		want Float16
+	}{
+		{"Asinh(0)", PositiveZero, PositiveZero},
+		{"Asinh(-0)", NegativeZero, NegativeZero},
+		{"Asinh(inf)", PositiveInfinity, PositiveInfinity},
+		{"Asinh(-inf)", NegativeInfinity, NegativeInfinity},
+		{"Asinh(NaN)", QuietNaN, QuietNaN},
+		{"Asinh(-1)", FromFloat32(-1.0), FromFloat64(math.Asinh(-1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Asinh(tt.arg); !sameFloat16(got, tt.want) {
+				t.Errorf("Asinh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcosh(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  Float16
+		want Float16
+	}{
+		{"Acosh(1)", FromFloat32(1.0), PositiveZero},
+		{"Acosh(0.5)", FromFloat32(0.5), QuietNaN},
+		{"Acosh(-0)", NegativeZero, QuietNaN},
+		{"Acosh(inf)", PositiveInfinity, PositiveInfinity},
+		{"Acosh(-inf)", NegativeInfinity, QuietNaN},
+		{"Acosh(NaN)", QuietNaN, QuietNaN},
+		{"Acosh(2)", FromFloat32(2.0), FromFloat64(math.Acosh(2))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Acosh(tt.arg); !sameFloat16(got, tt.want) {
+				t.Errorf("Acosh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAtanh(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  Float16
+		want Float16
+	}{
+		{"Atanh(0)", PositiveZero, PositiveZero},
+		{"Atanh(-0)", NegativeZero, NegativeZero},
+		{"Atanh(1)", FromFloat32(1.0), PositiveInfinity},
+		{"Atanh(-1)", FromFloat32(-1.0), NegativeInfinity},
+		{"Atanh(2)", FromFloat32(2.0), QuietNaN},
+		{"Atanh(-inf)", NegativeInfinity, QuietNaN},
+		{"Atanh(NaN)", QuietNaN, QuietNaN},
+		{"Atanh(0.5)", FromFloat32(0.5), FromFloat64(math.Atanh(0.5))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Atanh(tt.arg); !sameFloat16(got, tt.want) {
+				t.Errorf("Atanh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSincos(t *testing.T) {
+	tests := []struct {
+		name             string
+		arg              Float16
+		wantSin, wantCos Float16
+	}{
+		{"Sincos(0)", PositiveZero, PositiveZero, FromFloat32(1.0)},
+		{"Sincos(-0)", NegativeZero, NegativeZero, FromFloat32(1.0)},
+		{"Sincos(inf)", PositiveInfinity, QuietNaN, QuietNaN},
+		{"Sincos(-inf)", NegativeInfinity, QuietNaN, QuietNaN},
+		{"Sincos(NaN)", QuietNaN, QuietNaN, QuietNaN},
+		{"Sincos(1)", FromFloat32(1.0), Sin(FromFloat32(1.0)), Cos(FromFloat32(1.0))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sin, cos := Sincos(tt.arg)
+			if !sameFloat16(sin, tt.wantSin) || !sameFloat16(cos, tt.wantCos) {
+				t.Errorf("Sincos() = %v, %v, want %v, %v", sin, cos, tt.wantSin, tt.wantCos)
+			}
+		})
+	}
+}
+
+func TestPow10(t *testing.T) {
+	tests := []struct {
+		n    int
+		want Float16
+	}{
+		{0, FromFloat32(1.0)},
+		{4, FromFloat32(10000)},
+		{5, PositiveInfinity},
+		{-2, FromFloat64(0.01)},
+		{-7, FromBits(0x0002)}, // 1e-7 rounds to two subnormal steps
+		{-8, PositiveZero},
+		{math.MinInt, PositiveZero},
+		{math.MaxInt, PositiveInfinity},
+	}
+
+	for _, tt := range tests {
+		if got := Pow10(tt.n); got != tt.want {
+			t.Errorf("Pow10(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestJn(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		arg  Float16
+		want Float16
+	}{
+		{"Jn(2, inf)", 2, PositiveInfinity, PositiveZero},
+		{"Jn(2, -inf)", 2, NegativeInfinity, PositiveZero},
+		{"Jn(2, NaN)", 2, QuietNaN, QuietNaN},
+		{"Jn(0, x)", 0, FromFloat32(1.5), J0(FromFloat32(1.5))},
+		{"Jn(3, 2)", 3, FromFloat32(2.0), FromFloat64(math.Jn(3, 2))},
+		{"Jn(-3, 2)", -3, FromFloat32(2.0), FromFloat64(-math.Jn(3, 2))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Jn(tt.n, tt.arg); !sameFloat16(got, tt.want) {
+				t.Errorf("Jn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYn(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		arg  Float16
+		want Float16
+	}{
+		{"Yn(2, inf)", 2, PositiveInfinity, PositiveZero},
+		{"Yn(2, 0)", 2, PositiveZero, NegativeInfinity},
+		{"Yn(-3, 0)", -3, PositiveZero, PositiveInfinity},
+		{"Yn(-2, 0)", -2, PositiveZero, NegativeInfinity},
+		{"Yn(2, -1)", 2, FromFloat32(-1.0), QuietNaN},
+		{"Yn(2, NaN)", 2, QuietNaN, QuietNaN},
+		{"Yn(3, 2)", 3, FromFloat32(2.0), FromFloat64(math.Yn(3, 2))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Yn(tt.n, tt.arg); !sameFloat16(got, tt.want) {
+				t.Errorf("Yn() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFMA(t *testing.T) {
+	one := FromFloat32(1.0)
+	tests := []struct {
+		name    string
+		x, y, z Float16
+		want    Float16
+	}{
+		{"2*3+1", FromFloat32(2.0), FromFloat32(3.0), one, FromFloat32(7.0)},
+		{"inf*0+1", PositiveInfinity, PositiveZero, one, QuietNaN},
+		{"inf*1-inf", PositiveInfinity, one, NegativeInfinity, QuietNaN},
+		{"inf*-1+1", PositiveInfinity, one.Neg(), one, NegativeInfinity},
+		{"1*1+inf", one, one, PositiveInfinity, PositiveInfinity},
+		{"NaN*0+1", QuietNaN, PositiveZero, one, QuietNaN},
+		{"3*1-3", FromFloat32(3.0), one, FromFloat32(-3.0), PositiveZero},
+		{"-0*1-0", NegativeZero, one, NegativeZero, NegativeZero},
+		// Separate rounding of the product would give 65504 + 65504 = +Inf
+		{"overflow", MaxValue, FromFloat32(2.0), MaxValue.Neg(), MaxValue},
+		// (1 + 2^-10)^2 = 1 + 2^-9 + 2^-20: the last term decides the
+		// rounding after subtracting 2^-9, above the midpoint 1 + 2^-11
+		{"single rounding", FromBits(0x3C01), FromBits(0x3C01), FromFloat64(-0x1p-9 + 0x1p-11), FromBits(0x3C01)},
+		{"max sum", MaxValue, MaxValue, MaxValue, PositiveInfinity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FMA(tt.x, tt.y, tt.z); !sameFloat16(got, tt.want) {
+				t.Errorf("FMA() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErfinv(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  Float16
+		want Float16
+	}{
+		{"Erfinv(0)", PositiveZero, PositiveZero},
+		{"Erfinv(1)", FromFloat32(1.0), PositiveInfinity},
+		{"Erfinv(-1)", FromFloat32(-1.0), NegativeInfinity},
+		{"Erfinv(2)", FromFloat32(2.0), QuietNaN},
+		{"Erfinv(NaN)", QuietNaN, QuietNaN},
+		{"Erfinv(Erf(0.5))", Erf(FromFloat32(0.5)), FromFloat32(0.5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Erfinv(tt.arg); !sameFloat16(got, tt.want) {
+				t.Errorf("Erfinv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErfcinv(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  Float16
+		want Float16
+	}{
+		{"Erfcinv(0)", PositiveZero, PositiveInfinity},
+		{"Erfcinv(2)", FromFloat32(2.0), NegativeInfinity},
+		{"Erfcinv(1)", FromFloat32(1.0), PositiveZero},
+		{"Erfcinv(-1)", FromFloat32(-1.0), QuietNaN},
+		{"Erfcinv(NaN)", QuietNaN, QuietNaN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Erfcinv(tt.arg); !sameFloat16(got, tt.want) {
+				t.Errorf("Erfcinv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package float16
+
+import (
+	"math"
+	"math/big"
+)
+
+// Correct rounding of the elementary functions
+//
+// The functions are evaluated in float64, whose result is within a few
+// float64 ulps of the exact value, and rounded once to Float16. That single
+// rounding is correct unless the float64 result lies so close to a rounding
+// boundary (a Float16 value for the directed modes, the midpoint between two
+// for the nearest modes) that its error could put it on the wrong side. Those
+// rare hard cases are decided by re-evaluating the function with math/big.
+// The result therefore does not depend on the platform's float64 math
+// library or on fused multiply-adds, only on which side of the boundary the
+// exact value lies.
+
+// hardCaseULPs is the distance from a rounding boundary, in Float16 ulps,
+// within which a float64 result is re-evaluated. It is 2^10 float64 ulps,
+// well beyond the error of the float64 functions.
+const hardCaseULPs = 0x1p-32
+
+// refPrec is the precision of the math/big re-evaluation. A result within
+// 2^-exactBits of a boundary, relative to it, is taken to be exactly on it:
+// for Float16 arguments the exact value is either on a boundary or much
+// farther away.
+const (
+	refPrec   = 128
+	exactBits = 100
+)
+
+// nearestBoundary returns the rounding boundary closest to v, and whether v is
+// within hardCaseULPs of it. Boundaries are the Float16 values and the
+// midpoints between them, up to the overflow threshold.
+func nearestBoundary(v float64) (float64, bool) {
+	a := math.Abs(v)
+	if a >= 0x1p16 {
+		return 0, false
+	}
+	ulp := 0x1p-24
+	if a >= 0x1p-14 {
+		_, e := math.Frexp(a)
+		ulp = math.Ldexp(1, e-MantissaLen-1)
+	}
+	// Boundaries are the integers of s; the scaling is exact
+	s := 2 * a / ulp
+	k := math.Round(s)
+	if math.Abs(s-k) >= 2*hardCaseULPs {
+		return 0, false
+	}
+	return math.Copysign(k*ulp/2, v), true
+}
+
+// roundResult rounds v, a float64 evaluation of a function, to a Float16 in
+// the given direction. A v close to a rounding boundary is replaced by a value
+// on the same side of the boundary as the exact result, which ref computes.
+func roundResult(v float64, rounding RoundingMode, ref func(prec uint) *big.Float) Float16 {
+	if math.IsInf(v, 0) {
+		// Overflow of float64 itself; the exact result is finite
+		v = math.Copysign(math.MaxFloat64, v)
+	}
+	if b, near := nearestBoundary(v); near {
+		v = resolveBoundary(b, ref(refPrec))
+	}
+	result, _, _ := roundFloat64(v, rounding)
+	return result
+}
+
+// resolveBoundary returns b if the exact result z is on the boundary b, and
+// otherwise a value just beyond b on the side of z
+func resolveBoundary(b float64, z *big.Float) float64 {
+	d := new(big.Float).SetPrec(refPrec+64).Sub(z, big.NewFloat(b))
+	side := float64(d.Sign())
+	if side == 0 {
+		return b
+	}
+	if b == 0 {
+		// Below half the smallest subnormal, with the sign of z
+		return side * 0x1p-60
+	}
+	tol := new(big.Float).SetMantExp(big.NewFloat(math.Abs(b)), -exactBits)
+	if d.Abs(d).Cmp(tol) <= 0 {
+		return b
+	}
+	return b + side*math.Abs(b)*0x1p-40
+}
+
+// roundUnary evaluates fn at f in float64 and rounds the result correctly,
+// with ref as the math/big reference for hard cases
+func roundUnary(f Float16, rounding RoundingMode, fn func(float64) float64,
+	ref func(x *big.Float, prec uint) *big.Float) Float16 {
+	x := f.ToFloat64()
+	return roundResult(fn(x), rounding, func(prec uint) *big.Float {
+		return ref(big.NewFloat(x), prec)
+	})
+}
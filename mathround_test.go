@@ -0,0 +1,112 @@
+package float16
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNearestBoundary(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want float64
+		near bool
+	}{
+		{1, 1, true},
+		{1 + 0x1p-11, 1 + 0x1p-11, true}, // midpoint
+		{1 + 0x1p-50, 1, true},
+		{1 - 0x1p-50, 1, true},
+		{1 + 0x1p-30, 0, false},
+		{-3 - 0x1p-45, -3, true},
+		{0x1p-24 + 0x1p-70, 0x1p-24, true},
+		{0x1p-25, 0x1p-25, true}, // half the smallest subnormal
+		{0x1p-70, 0, true},
+		{65520, 65520, true}, // overflow threshold
+		{1e6, 0, false},
+	}
+	for _, tt := range tests {
+		b, near := nearestBoundary(tt.v)
+		if near != tt.near || near && b != tt.want {
+			t.Errorf("nearestBoundary(%g) = %g, %v, want %g, %v", tt.v, b, near, tt.want, tt.near)
+		}
+	}
+}
+
+// TestCorrectRounding checks hard cases, where the float64 result is within
+// rounding error of a boundary, in the directed rounding modes
+func TestCorrectRounding(t *testing.T) {
+	one := FromFloat64(1)
+	tests := []struct {
+		name     string
+		fn       func(Float16, RoundingMode) Float16
+		arg      Float16
+		rounding RoundingMode
+		want     Float16
+	}{
+		// sin x is just below x for small positive x
+		{"Sin(min subnormal) toward zero", SinWithRounding, SmallestSubnormal, RoundTowardZero, PositiveZero},
+		{"Sin(min subnormal) nearest", SinWithRounding, SmallestSubnormal, RoundNearestEven, SmallestSubnormal},
+		{"Sin(-min subnormal) toward zero", SinWithRounding, SmallestSubnormal.Neg(), RoundTowardZero, NegativeZero},
+		{"Tan(min subnormal) toward +Inf", TanWithRounding, SmallestSubnormal, RoundTowardPositive, SmallestSubnormal + 1},
+		{"Sinh(2^-14) toward +Inf", SinhWithRounding, 0x0400, RoundTowardPositive, 0x0401},
+		// tanh and erf saturate below 1
+		{"Tanh(20) toward zero", TanhWithRounding, FromFloat64(20), RoundTowardZero, 0x3BFF},
+		{"Tanh(20) nearest", TanhWithRounding, FromFloat64(20), RoundNearestEven, one},
+		{"Erf(-10) toward +Inf", ErfWithRounding, FromFloat64(-10), RoundTowardPositive, 0xBBFF},
+		{"Cosh(min subnormal) toward +Inf", CoshWithRounding, SmallestSubnormal, RoundTowardPositive, 0x3C01},
+		// exp underflows in float64 but not exactly
+		{"Exp(-1000) toward +Inf", ExpWithRounding, FromFloat64(-1000), RoundTowardPositive, SmallestSubnormal},
+		{"Exp(-1000) nearest", ExpWithRounding, FromFloat64(-1000), RoundNearestEven, PositiveZero},
+		{"Exp(60000) toward zero", ExpWithRounding, FromFloat64(60000), RoundTowardZero, MaxValue},
+		// exact results stay exact in every mode
+		{"Exp2(-24) toward zero", Exp2WithRounding, FromFloat64(-24), RoundTowardZero, SmallestSubnormal},
+		{"Exp10(4) toward -Inf", Exp10WithRounding, FromFloat64(4), RoundTowardNegative, FromFloat64(10000)},
+		{"Log2(1024) toward zero", Log2WithRounding, FromFloat64(1024), RoundTowardZero, FromFloat64(10)},
+		{"Log10(1000) toward -Inf", Log10WithRounding, FromFloat64(1000), RoundTowardNegative, FromFloat64(3)},
+		{"Log(1) toward -Inf", LogWithRounding, one, RoundTowardNegative, PositiveZero},
+		{"Cbrt(-125) toward zero", CbrtWithRounding, FromFloat64(-125), RoundTowardZero, FromFloat64(-5)},
+		{"Cbrt(2^-24) toward zero", CbrtWithRounding, SmallestSubnormal, RoundTowardZero, FromFloat64(0x1p-8)},
+		{"Sqrt(3969) nearest", SqrtWithRounding, FromFloat64(3969), RoundNearestEven, FromFloat64(63)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.arg, tt.rounding); got != tt.want {
+				t.Errorf("got %v (%#04x), want %v (%#04x)", got, uint16(got), tt.want, uint16(tt.want))
+			}
+		})
+	}
+}
+
+func TestPowWithRounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		f, exp   float64
+		rounding RoundingMode
+		want     Float16
+	}{
+		{"(-0)^-3", math.Copysign(0, -1), -3, RoundNearestEven, NegativeInfinity},
+		{"(-0)^3", math.Copysign(0, -1), 3, RoundNearestEven, NegativeZero},
+		{"(-0)^-2", math.Copysign(0, -1), -2, RoundNearestEven, PositiveInfinity},
+		{"(-Inf)^3", math.Inf(-1), 3, RoundNearestEven, NegativeInfinity},
+		{"(-Inf)^-3", math.Inf(-1), -3, RoundNearestEven, NegativeZero},
+		{"1^NaN", 1, math.NaN(), RoundNearestEven, FromFloat64(1)},
+		{"(-1)^Inf", -1, math.Inf(1), RoundNearestEven, FromFloat64(1)},
+		{"(-2)^0.5", -2, 0.5, RoundNearestEven, QuietNaN},
+		{"4^1.5 toward zero", 4, 1.5, RoundTowardZero, FromFloat64(8)},
+		{"2^-25 toward +Inf", 2, -25, RoundTowardPositive, SmallestSubnormal},
+		{"2^-25 nearest even", 2, -25, RoundNearestEven, PositiveZero},
+		{"2^-25 nearest away", 2, -25, RoundNearestAway, SmallestSubnormal},
+		{"(-3)^3 toward zero", -3, 3, RoundTowardZero, FromFloat64(-27)},
+		{"63^2 nearest even", 63, 2, RoundNearestEven, FromFloat64(3968)},
+		{"63^2 nearest away", 63, 2, RoundNearestAway, FromFloat64(3970)},
+		{"100^100 toward zero", 100, 100, RoundTowardZero, MaxValue},
+		{"0.5^100 toward +Inf", 0.5, 100, RoundTowardPositive, SmallestSubnormal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PowWithRounding(FromFloat64(tt.f), FromFloat64(tt.exp), tt.rounding)
+			if got != tt.want && !(got.IsNaN() && tt.want.IsNaN()) {
+				t.Errorf("got %v (%#04x), want %v (%#04x)", got, uint16(got), tt.want, uint16(tt.want))
+			}
+		})
+	}
+}
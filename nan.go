@@ -0,0 +1,116 @@
+package float16
+
+// A Float16 NaN carries a sign, a quiet bit (the most significant trailing
+// significand bit) and a 9-bit payload in the remaining trailing bits.
+//
+// Conversions to and from float32 and float64 keep the sign and align the
+// payload with the top of the wider significand, as x86 F16C and ARM
+// hardware do, so a Float16 payload survives a round trip through either
+// type and a wider NaN keeps its nine most significant payload bits. Every
+// conversion returns a quiet NaN. The arithmetic operations return their
+// first NaN operand, quieted, as IEEE 754 §6.2.3 recommends.
+//
+// A signaling NaN operand raises the IEEE 754 invalid operation exception.
+// Under default exception handling this only quiets the NaN, but the
+// *WithMode functions report it as an ErrInvalidOperation *Float16Error
+// naming the operation: arithmetic in ModeExactArithmetic and conversion
+// in ModeStrict and ModeExact. Arithmetic still returns the quieted NaN
+// alongside the error. The error's Value is the offending operand, here and
+// in CompareQuiet and CompareSignaling.
+
+const (
+	// quietBit distinguishes quiet NaNs from signaling ones
+	quietBit = 0x0200
+
+	// MaxNaNPayload is the largest payload a Float16 NaN can carry
+	MaxNaNPayload = 0x01FF
+)
+
+// NaNWithPayload returns a positive NaN with the given payload, quiet or
+// signaling. As with the IEEE 754 setPayload and setPayloadSignaling
+// operations, an invalid payload gives +0: one above MaxNaNPayload, or zero
+// for a signaling NaN, whose bit pattern would be infinity.
+func NaNWithPayload(payload uint16, quiet bool) Float16 {
+	if payload > MaxNaNPayload || (payload == 0 && !quiet) {
+		return PositiveZero
+	}
+	if quiet {
+		return PositiveInfinity | quietBit | Float16(payload)
+	}
+	return PositiveInfinity | Float16(payload)
+}
+
+// Payload returns the payload of the NaN f, or 0 if f is not a NaN
+func (f Float16) Payload() uint16 {
+	if !f.IsNaN() {
+		return 0
+	}
+	return uint16(f & MaxNaNPayload)
+}
+
+// IsSignalingNaN reports whether f is a signaling NaN
+func (f Float16) IsSignalingNaN() bool {
+	return f.IsNaN() && f&quietBit == 0
+}
+
+// Quiet returns f with its quiet bit set if f is a signaling NaN, keeping
+// its sign and payload, and f unchanged otherwise
+func (f Float16) Quiet() Float16 {
+	if f.IsNaN() {
+		return f | quietBit
+	}
+	return f
+}
+
+// propagateNaN returns the NaN result of an operation on a and b, at least
+// one of which is NaN: the first NaN operand, quieted
+func propagateNaN(a, b Float16) Float16 {
+	if a.IsNaN() {
+		return a.Quiet()
+	}
+	return b.Quiet()
+}
+
+// nanOperandError reports a NaN operand of op in exact arithmetic, with
+// the offending operand as its Value. A signaling NaN raises the IEEE 754
+// invalid operation exception; a quiet one is an error only because exact
+// mode rejects NaNs.
+func nanOperandError(op string, a, b Float16) error {
+	v := nanOperand(a, b)
+	if v.IsSignalingNaN() {
+		return &Float16Error{Op: op, Value: v, Msg: "signaling NaN operand", Code: ErrInvalidOperation}
+	}
+	return &Float16Error{Op: op, Value: v, Msg: "NaN operand in exact mode", Code: ErrNaN}
+}
+
+// nanOperand returns the NaN operand an error about a and b reports: the
+// first signaling NaN, or failing that the first NaN
+func nanOperand(a, b Float16) Float16 {
+	switch {
+	case a.IsSignalingNaN():
+		return a
+	case b.IsSignalingNaN(), !a.IsNaN():
+		return b
+	}
+	return a
+}
+
+// nanFromFloat32 converts the float32 NaN with bits b to Float16
+func nanFromFloat32(b uint32) Float16 {
+	return Float16(b>>16&SignMask) | PositiveInfinity | quietBit | Float16(b>>13&MaxNaNPayload)
+}
+
+// nanFromFloat64 converts the float64 NaN with bits b to Float16
+func nanFromFloat64(b uint64) Float16 {
+	return Float16(b>>48&SignMask) | PositiveInfinity | quietBit | Float16(b>>42&MaxNaNPayload)
+}
+
+// nanToFloat32 returns the bits of the quiet float32 NaN for the NaN f
+func nanToFloat32(f Float16) uint32 {
+	return uint32(f&SignMask)<<16 | 0x7FC00000 | uint32(f&MaxNaNPayload)<<13
+}
+
+// nanToFloat64 returns the bits of the quiet float64 NaN for the NaN f
+func nanToFloat64(f Float16) uint64 {
+	return uint64(f&SignMask)<<48 | 0x7FF8000000000000 | uint64(f&MaxNaNPayload)<<42
+}
@@ -0,0 +1,227 @@
+package float16
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNaNWithPayload(t *testing.T) {
+	tests := []struct {
+		payload uint16
+		quiet   bool
+		want    Float16
+	}{
+		{0, true, QuietNaN},
+		{0x100, false, SignalingNaN},
+		{0x1FF, true, 0x7FFF},
+		{1, false, 0x7C01},
+		{0, false, PositiveZero},    // would be +Inf
+		{0x200, true, PositiveZero}, // too large
+	}
+
+	for _, tt := range tests {
+		got := NaNWithPayload(tt.payload, tt.quiet)
+		if got != tt.want {
+			t.Errorf("NaNWithPayload(%#x, %v) = %#04x, want %#04x", tt.payload, tt.quiet, uint16(got), uint16(tt.want))
+		}
+		if got.IsNaN() && (got.Payload() != tt.payload || got.IsSignalingNaN() == tt.quiet) {
+			t.Errorf("NaNWithPayload(%#x, %v): Payload() = %#x, IsSignalingNaN() = %v", tt.payload, tt.quiet, got.Payload(), got.IsSignalingNaN())
+		}
+	}
+}
+
+func TestNaNInspection(t *testing.T) {
+	for b := 0; b < 1<<16; b++ {
+		f := FromBits(uint16(b))
+		if !f.IsNaN() {
+			if f.Payload() != 0 || f.IsSignalingNaN() || f.Quiet() != f {
+				t.Fatalf("%#04x: Payload, IsSignalingNaN, Quiet = %#x, %v, %#04x", b, f.Payload(), f.IsSignalingNaN(), uint16(f.Quiet()))
+			}
+			continue
+		}
+		if f.IsSignalingNaN() != (f.Class() == ClassSignalingNaN) {
+			t.Fatalf("%#04x: IsSignalingNaN() = %v, Class() = %v", b, f.IsSignalingNaN(), f.Class())
+		}
+		q := f.Quiet()
+		if q.IsSignalingNaN() || !q.IsNaN() || q.Payload() != f.Payload() || q.Signbit() != f.Signbit() {
+			t.Fatalf("Quiet(%#04x) = %#04x", b, uint16(q))
+		}
+	}
+}
+
+func TestNaNConversionRoundTrip(t *testing.T) {
+	for b := 0; b < 1<<16; b++ {
+		f := FromBits(uint16(b))
+		if !f.IsNaN() {
+			continue
+		}
+		if got := FromFloat32(f.ToFloat32()); got != f.Quiet() {
+			t.Fatalf("FromFloat32(ToFloat32(%#04x)) = %#04x", b, uint16(got))
+		}
+		if got := FromFloat64(f.ToFloat64()); got != f.Quiet() {
+			t.Fatalf("FromFloat64(ToFloat64(%#04x)) = %#04x", b, uint16(got))
+		}
+		if got, _ := FromFloat32WithMode(f.ToFloat32(), ModeIEEE, RoundNearestEven); got != f.Quiet() {
+			t.Fatalf("FromFloat32WithMode(ToFloat32(%#04x)) = %#04x", b, uint16(got))
+		}
+	}
+}
+
+func TestNaNConversionBits(t *testing.T) {
+	tagged := NaNWithPayload(0x15, false) | SignMask
+	if got := math.Float32bits(tagged.ToFloat32()); got != 0xFFC00000|0x15<<13 {
+		t.Errorf("ToFloat32(%#04x) bits = %#08x", uint16(tagged), got)
+	}
+	if got := math.Float64bits(tagged.ToFloat64()); got != 0xFFF8000000000000|0x15<<42 {
+		t.Errorf("ToFloat64(%#04x) bits = %#016x", uint16(tagged), got)
+	}
+
+	tests := []struct {
+		name string
+		f64  float64
+		want Float16
+	}{
+		{"math.NaN", math.NaN(), QuietNaN}, // low payload bits do not fit
+		{"negative", math.Float64frombits(0xFFF8000000000000), NegativeQNaN},
+		{"signaling", math.Float64frombits(0x7FF0000000000000 | 0x1AB<<42), 0x7E00 | 0x1AB},
+	}
+	for _, tt := range tests {
+		if got := FromFloat64(tt.f64); got != tt.want {
+			t.Errorf("FromFloat64(%s) = %#04x, want %#04x", tt.name, uint16(got), uint16(tt.want))
+		}
+	}
+	if got := FromFloat32(math.Float32frombits(0x7F800000 | 0x1AB<<13)); got != 0x7E00|0x1AB {
+		t.Errorf("FromFloat32(signaling) = %#04x, want %#04x", uint16(got), 0x7E00|0x1AB)
+	}
+
+	nan := NaNWithPayload(7, true)
+	got, err := FromFloat64WithMode(nan.ToFloat64(), ModeStrict, RoundNearestEven)
+	if got != nan || err == nil {
+		t.Errorf("FromFloat64WithMode(strict) = %#04x, %v, want %#04x and an error", uint16(got), err, uint16(nan))
+	}
+}
+
+func TestNaNPropagation(t *testing.T) {
+	a := NaNWithPayload(0x11, true)
+	b := NaNWithPayload(0x22, false) | SignMask
+	one := FromFloat32(1)
+
+	tests := []struct {
+		name      string
+		got, want Float16
+	}{
+		{"Add(a, b)", Add(a, b), a},
+		{"Add(b, a)", Add(b, a), b.Quiet()},
+		{"Add(0, b)", Add(PositiveZero, b), b.Quiet()},
+		{"Sub(1, b)", Sub(one, b), b.Quiet()},
+		{"Sub(a, 1)", Sub(a, one), a},
+		{"Mul(0, a)", Mul(PositiveZero, a), a},
+		{"Mul(Inf, b)", Mul(PositiveInfinity, b), b.Quiet()},
+		{"Div(a, 0)", Div(a, PositiveZero), a},
+		{"Div(0, b)", Div(NegativeZero, b), b.Quiet()},
+		{"Div(b, a)", Div(b, a), b.Quiet()},
+		{"Sqrt(b)", Sqrt(b), b.Quiet()},
+		{"FMA(1, a, b)", FMA(one, a, b), a},
+		{"FMA(0, Inf, b)", FMA(PositiveZero, PositiveInfinity, b), b.Quiet()},
+		{"Mod(a, 0)", Mod(a, PositiveZero), a},
+		{"Remainder(1, b)", Remainder(one, b), b.Quiet()},
+		{"NextAfter(1, a)", NextAfter(one, a), a},
+		{"Minimum(b, a)", Minimum(b, a), b.Quiet()},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %#04x, want %#04x", tt.name, uint16(tt.got), uint16(tt.want))
+		}
+	}
+
+	for _, mode := range []ArithmeticMode{ModeIEEEArithmetic, ModeFastArithmetic} {
+		if got, err := MulWithMode(PositiveZero, b, mode, RoundNearestEven); got != b.Quiet() || err != nil {
+			t.Errorf("MulWithMode(0, b, %v) = %#04x, %v", mode, uint16(got), err)
+		}
+	}
+	if _, err := DivWithMode(a, PositiveZero, ModeExactArithmetic, RoundNearestEven); err == nil {
+		t.Error("DivWithMode(NaN, 0, exact) returned no error")
+	}
+}
+
+func TestSignalingNaNArithmetic(t *testing.T) {
+	snan := NaNWithPayload(0x2A, false)
+	one := FromFloat32(1)
+	ops := []struct {
+		op string
+		fn func(a, b Float16, mode ArithmeticMode, rounding RoundingMode) (Float16, error)
+	}{
+		{"add", AddWithMode},
+		{"sub", SubWithMode},
+		{"mul", MulWithMode},
+		{"div", DivWithMode},
+	}
+
+	for _, o := range ops {
+		for _, args := range [][2]Float16{{snan, one}, {one, snan}, {QuietNaN, snan}, {snan, PositiveZero}} {
+			// Default handling quiets the NaN and reports nothing
+			got, err := o.fn(args[0], args[1], ModeIEEEArithmetic, RoundNearestEven)
+			if want := propagateNaN(args[0], args[1]); got != want || err != nil {
+				t.Errorf("%s(%#04x, %#04x) = %#04x, %v, want %#04x", o.op, uint16(args[0]), uint16(args[1]), uint16(got), err, uint16(want))
+			}
+
+			// Exact mode returns the same NaN along with the error, whose
+			// Value is the signaling operand
+			got, err = o.fn(args[0], args[1], ModeExactArithmetic, RoundNearestEven)
+			if want := propagateNaN(args[0], args[1]); got != want {
+				t.Errorf("%s(%#04x, %#04x) in exact mode = %#04x, want %#04x", o.op, uint16(args[0]), uint16(args[1]), uint16(got), uint16(want))
+			}
+			e, ok := err.(*Float16Error)
+			if !ok || e.Code != ErrInvalidOperation || e.Op != o.op || e.Value != snan {
+				t.Errorf("%s(%#04x, %#04x) in exact mode: error %v, want ErrInvalidOperation from %s", o.op, uint16(args[0]), uint16(args[1]), err, o.op)
+			}
+		}
+
+		// A quiet NaN stays ErrNaN
+		got, err := o.fn(one, QuietNaN.Neg(), ModeExactArithmetic, RoundNearestEven)
+		if want := propagateNaN(one, QuietNaN.Neg()); got != want {
+			t.Errorf("%s(1, -NaN) in exact mode = %#04x, want %#04x", o.op, uint16(got), uint16(want))
+		}
+		if e, ok := err.(*Float16Error); !ok || e.Code != ErrNaN || e.Op != o.op || e.Value != QuietNaN.Neg() {
+			t.Errorf("%s(1, -NaN) in exact mode: error %v, want ErrNaN from %s", o.op, err, o.op)
+		}
+	}
+}
+
+func TestSignalingNaNConversion(t *testing.T) {
+	snan32 := math.Float32frombits(0xFF800000 | 0x15<<13)
+	snan64 := math.Float64frombits(0xFFF0000000000000 | 0x15<<42)
+	want := NaNWithPayload(0x15, true) | SignMask
+
+	for _, mode := range []ConversionMode{ModeIEEE, ModeFast, ModeStrict, ModeExact} {
+		strict := mode == ModeStrict || mode == ModeExact
+		for _, c := range []struct {
+			name string
+			fn   func() (Float16, error)
+		}{
+			{"FromFloat32WithMode", func() (Float16, error) { return FromFloat32WithMode(snan32, mode, RoundNearestEven) }},
+			{"FromFloat64WithMode", func() (Float16, error) { return FromFloat64WithMode(snan64, mode, RoundNearestEven) }},
+		} {
+			got, err := c.fn()
+			if got != want {
+				t.Errorf("%s(sNaN, %v) = %#04x, want %#04x", c.name, mode, uint16(got), uint16(want))
+			}
+			e, ok := err.(*Float16Error)
+			if strict && (!ok || e.Code != ErrInvalidOperation || e.Op != "convert") {
+				t.Errorf("%s(sNaN, %v): error %v, want ErrInvalidOperation", c.name, mode, err)
+			}
+			if !strict && err != nil {
+				t.Errorf("%s(sNaN, %v): unexpected error %v", c.name, mode, err)
+			}
+		}
+	}
+
+	// Widening quiets too
+	if got := math.Float32bits(SignalingNaN.ToFloat32()); got&(1<<22) == 0 {
+		t.Errorf("ToFloat32(sNaN) bits = %#08x, want a quiet NaN", got)
+	}
+	if _, err := FromFloat32WithMode(float32(math.NaN()), ModeStrict, RoundNearestEven); err.(*Float16Error).Code != ErrNaN {
+		t.Errorf("FromFloat32WithMode(qNaN, strict): error %v, want ErrNaN", err)
+	}
+}
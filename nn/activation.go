@@ -0,0 +1,141 @@
+package nn
+
+import (
+	"math"
+
+	"github.com/zerfoo/float16"
+)
+
+// ReLU stores max(x, 0) for each x in src in dst. NaN inputs stay NaN.
+func ReLU(dst, src []float16.Float16) {
+	apply(dst, src, relu)
+}
+
+// ReLUInPlace replaces each x in s with max(x, 0)
+func ReLUInPlace(s []float16.Float16) {
+	ReLU(s, s)
+}
+
+func relu(x float32) float32 {
+	if x < 0 {
+		return 0
+	}
+	return x
+}
+
+// ReLU6 stores min(max(x, 0), 6) for each x in src in dst
+func ReLU6(dst, src []float16.Float16) {
+	apply(dst, src, relu6)
+}
+
+// ReLU6InPlace replaces each x in s with min(max(x, 0), 6)
+func ReLU6InPlace(s []float16.Float16) {
+	ReLU6(s, s)
+}
+
+func relu6(x float32) float32 {
+	if x > 6 {
+		return 6
+	}
+	return relu(x)
+}
+
+// LeakyReLU stores x for x >= 0 and alpha·x for x < 0 in dst
+func LeakyReLU(dst, src []float16.Float16, alpha float32) {
+	apply(dst, src, func(x float32) float32 {
+		if x < 0 {
+			return alpha * x
+		}
+		return x
+	})
+}
+
+// LeakyReLUInPlace is LeakyReLU(s, s, alpha)
+func LeakyReLUInPlace(s []float16.Float16, alpha float32) {
+	LeakyReLU(s, s, alpha)
+}
+
+// ELU stores x for x > 0 and alpha·(e^x - 1) for x <= 0 in dst
+func ELU(dst, src []float16.Float16, alpha float32) {
+	apply(dst, src, func(x float32) float32 {
+		if x > 0 {
+			return x
+		}
+		return alpha * float32(math.Expm1(float64(x)))
+	})
+}
+
+// ELUInPlace is ELU(s, s, alpha)
+func ELUInPlace(s []float16.Float16, alpha float32) {
+	ELU(s, s, alpha)
+}
+
+// Sigmoid stores the logistic function 1/(1 + e^-x) of src in dst
+func Sigmoid(dst, src []float16.Float16) {
+	apply(dst, src, sigmoid)
+}
+
+// SigmoidInPlace replaces each x in s with 1/(1 + e^-x)
+func SigmoidInPlace(s []float16.Float16) {
+	Sigmoid(s, s)
+}
+
+// sigmoid only exponentiates non-positive values, so e^x cannot overflow
+func sigmoid(x float32) float32 {
+	if x >= 0 {
+		return 1 / (1 + exp32(-x))
+	}
+	e := exp32(x)
+	return e / (1 + e)
+}
+
+// SiLU stores x·sigmoid(x), also known as Swish, of src in dst
+func SiLU(dst, src []float16.Float16) {
+	apply(dst, src, silu)
+}
+
+// SiLUInPlace replaces each x in s with x·sigmoid(x)
+func SiLUInPlace(s []float16.Float16) {
+	SiLU(s, s)
+}
+
+func silu(x float32) float32 {
+	return x * sigmoid(x)
+}
+
+// GELU stores the exact Gaussian error linear unit x·Φ(x) of src in dst,
+// where Φ is the standard normal CDF
+func GELU(dst, src []float16.Float16) {
+	apply(dst, src, gelu)
+}
+
+// GELUInPlace replaces each x in s with x·Φ(x)
+func GELUInPlace(s []float16.Float16) {
+	GELU(s, s)
+}
+
+// gelu uses Φ(x) = erfc(-x/√2)/2, which keeps its relative accuracy in the
+// negative tail where 1 + erf(x/√2) cancels
+func gelu(x float32) float32 {
+	return 0.5 * x * float32(math.Erfc(-float64(x)/math.Sqrt2))
+}
+
+// GELUTanh stores the tanh approximation of GELU,
+// x/2·(1 + tanh(√(2/π)·(x + 0.044715·x³))), of src in dst
+func GELUTanh(dst, src []float16.Float16) {
+	apply(dst, src, geluTanh)
+}
+
+// GELUTanhInPlace replaces each x in s with the tanh approximation of GELU
+func GELUTanhInPlace(s []float16.Float16) {
+	GELUTanh(s, s)
+}
+
+// sqrt2OverPi is √(2/π)
+const sqrt2OverPi = 0.7978845608028654
+
+// geluTanh uses (1 + tanh(u))/2 = sigmoid(2u), which does not cancel for
+// negative x
+func geluTanh(x float32) float32 {
+	return x * sigmoid(2*sqrt2OverPi*(x+0.044715*x*x*x))
+}
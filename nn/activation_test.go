@@ -0,0 +1,172 @@
+package nn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+// halfInputs returns every finite Float16 value in [-lim, lim]
+func halfInputs(lim float64) []float16.Float16 {
+	var s []float16.Float16
+	for b := range 1 << 16 {
+		f := float16.FromBits(uint16(b))
+		if f.IsFinite() && math.Abs(f.ToFloat64()) <= lim {
+			s = append(s, f)
+		}
+	}
+	return s
+}
+
+// ulpDiff returns |got - want| in units of the Float16 spacing at want
+func ulpDiff(got float16.Float16, want float64) float64 {
+	switch {
+	case got.IsNaN() || math.IsNaN(want):
+		if got.IsNaN() && math.IsNaN(want) {
+			return 0
+		}
+		return math.Inf(1)
+	case math.Abs(want) >= 65520: // rounds to ±Inf
+		if got.IsInf(0) && got.Signbit() == (want < 0) {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	_, e := math.Frexp(want)
+	ulp := math.Ldexp(1, max(e-1, -14)-10)
+	return math.Abs(got.ToFloat64()-want) / ulp
+}
+
+func TestActivationsAgainstFloat64(t *testing.T) {
+	src := halfInputs(1000)
+	tests := []struct {
+		name string
+		f    func(dst, src []float16.Float16)
+		ref  func(float64) float64
+	}{
+		{"ReLU", ReLU, func(x float64) float64 { return math.Max(x, 0) }},
+		{"ReLU6", ReLU6, func(x float64) float64 { return math.Min(math.Max(x, 0), 6) }},
+		{"LeakyReLU", func(d, s []float16.Float16) { LeakyReLU(d, s, 0.125) }, func(x float64) float64 {
+			if x < 0 {
+				return 0.125 * x
+			}
+			return x
+		}},
+		{"ELU", func(d, s []float16.Float16) { ELU(d, s, 1) }, func(x float64) float64 {
+			if x > 0 {
+				return x
+			}
+			return math.Expm1(x)
+		}},
+		{"Sigmoid", Sigmoid, func(x float64) float64 { return 1 / (1 + math.Exp(-x)) }},
+		{"SiLU", SiLU, func(x float64) float64 { return x / (1 + math.Exp(-x)) }},
+		{"GELU", GELU, func(x float64) float64 { return x * math.Erfc(-x/math.Sqrt2) / 2 }},
+		{"GELUTanh", GELUTanh, func(x float64) float64 {
+			return x / 2 * (1 + math.Tanh(math.Sqrt(2/math.Pi)*(x+0.044715*x*x*x)))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := make([]float16.Float16, len(src))
+			tt.f(dst, src)
+			for i, x := range src {
+				want := tt.ref(x.ToFloat64())
+				// float32 working precision can misround near-ties
+				if d := ulpDiff(dst[i], want); d > 0.501 {
+					t.Fatalf("%s(%v) = %v, want %v (%.3g ulp)", tt.name, x, dst[i], want, d)
+				}
+			}
+		})
+	}
+}
+
+func TestActivationSpecialValues(t *testing.T) {
+	inf, ninf, nan := float16.PositiveInfinity, float16.NegativeInfinity, float16.QuietNaN
+	one := float16.FromFloat32(1)
+	tests := []struct {
+		name string
+		f    func(dst, src []float16.Float16)
+		src  []float16.Float16
+		want []float16.Float16
+	}{
+		{"ReLU", ReLU, []float16.Float16{inf, ninf, nan}, []float16.Float16{inf, 0, nan}},
+		{"ReLU6", ReLU6, []float16.Float16{inf, ninf, nan}, []float16.Float16{float16.FromFloat32(6), 0, nan}},
+		{"Sigmoid", Sigmoid, []float16.Float16{inf, ninf, nan}, []float16.Float16{one, 0, nan}},
+		{"SiLU", SiLU, []float16.Float16{inf, float16.FromFloat32(-30), nan}, []float16.Float16{inf, float16.NegativeZero, nan}},
+		{"GELU", GELU, []float16.Float16{inf, float16.FromFloat32(-30), nan}, []float16.Float16{inf, float16.NegativeZero, nan}},
+		{"ELU", func(d, s []float16.Float16) { ELU(d, s, 1) }, []float16.Float16{inf, ninf, nan}, []float16.Float16{inf, one.Neg(), nan}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := make([]float16.Float16, len(tt.src))
+			tt.f(dst, tt.src)
+			for i, want := range tt.want {
+				if dst[i] != want && !(dst[i].IsNaN() && want.IsNaN()) {
+					t.Errorf("%s(%v) = %v, want %v", tt.name, tt.src[i], dst[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestInPlaceMatchesDst(t *testing.T) {
+	src := float16.ToSlice16([]float32{-3, -0.5, 0, 0.25, 2, 9})
+	tests := []struct {
+		name    string
+		f       func(dst, src []float16.Float16)
+		inPlace func([]float16.Float16)
+	}{
+		{"ReLU", ReLU, ReLUInPlace},
+		{"ReLU6", ReLU6, ReLU6InPlace},
+		{"LeakyReLU", func(d, s []float16.Float16) { LeakyReLU(d, s, 0.01) }, func(s []float16.Float16) { LeakyReLUInPlace(s, 0.01) }},
+		{"ELU", func(d, s []float16.Float16) { ELU(d, s, 0.5) }, func(s []float16.Float16) { ELUInPlace(s, 0.5) }},
+		{"Sigmoid", Sigmoid, SigmoidInPlace},
+		{"SiLU", SiLU, SiLUInPlace},
+		{"GELU", GELU, GELUInPlace},
+		{"GELUTanh", GELUTanh, GELUTanhInPlace},
+		{"Softmax", Softmax, SoftmaxInPlace},
+		{"LogSoftmax", LogSoftmax, LogSoftmaxInPlace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := make([]float16.Float16, len(src))
+			tt.f(want, src)
+			got := append([]float16.Float16(nil), src...)
+			tt.inPlace(got)
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("%sInPlace()[%d] = %v, want %v", tt.name, i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLengthMismatchPanics(t *testing.T) {
+	s := make([]float16.Float16, 4)
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"ReLU", func() { ReLU(s[:3], s) }},
+		{"Softmax", func() { Softmax(s, s[:2]) }},
+		{"LayerNorm rows", func() { LayerNorm(s, s, s[:3], nil, 1e-5) }},
+		{"LayerNorm beta", func() { LayerNorm(s, s, s[:2], s[:1], 1e-5) }},
+		{"RMSNorm empty weight", func() { RMSNorm(s, s, nil, 1e-5) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", tt.name)
+				}
+			}()
+			tt.f()
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Package nn provides neural-network activation and normalization kernels
+// over half-precision slices.
+//
+// Every kernel widens its inputs to float32, does all intermediate work and
+// accumulation in float32, and rounds each output to Float16 once. Reductions
+// such as the softmax denominator or the LayerNorm variance therefore do not
+// lose the low bits that a half-precision running sum would, and
+// intermediates such as e^x never have to fit the Float16 range.
+//
+// Each kernel has a dst form, which writes f(src) to dst, and an InPlace form,
+// which overwrites its argument. dst and src must have the same length and may
+// be the same slice; a length mismatch panics, as with float16.AddSlice.
+package nn
+
+import (
+	"math"
+
+	"github.com/zerfoo/float16"
+)
+
+// checkLen panics unless dst and src have the same length
+func checkLen(dst, src []float16.Float16) {
+	if len(dst) != len(src) {
+		panic("nn: slice length mismatch")
+	}
+}
+
+// apply stores f(src[i]) in dst[i], computing f in float32
+func apply(dst, src []float16.Float16, f func(float32) float32) {
+	checkLen(dst, src)
+	for i, v := range src {
+		dst[i] = float16.FromFloat32(f(v.ToFloat32()))
+	}
+}
+
+func exp32(x float32) float32 {
+	return float32(math.Exp(float64(x)))
+}
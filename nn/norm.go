@@ -0,0 +1,87 @@
+package nn
+
+import (
+	"math"
+
+	"github.com/zerfoo/float16"
+)
+
+// LayerNorm normalizes each row of src to zero mean and unit variance, then
+// scales by gamma and shifts by beta, storing the result in dst:
+//
+//	y = (x - mean) / sqrt(var + eps) · gamma + beta
+//
+// The row length is len(gamma), and len(src) must be a multiple of it; beta
+// may be nil for no shift. The mean and the (biased) variance are computed in
+// two float32 passes, so a large common offset does not cancel the variance.
+func LayerNorm(dst, src, gamma, beta []float16.Float16, eps float32) {
+	checkLen(dst, src)
+	n := checkRows(src, gamma, beta)
+	for r := 0; r < len(src); r += n {
+		row := src[r : r+n]
+		var mean float32
+		for _, v := range row {
+			mean += v.ToFloat32()
+		}
+		mean /= float32(n)
+		var variance float32
+		for _, v := range row {
+			d := v.ToFloat32() - mean
+			variance += d * d
+		}
+		variance /= float32(n)
+		inv := 1 / float32(math.Sqrt(float64(variance+eps)))
+		for i, v := range row {
+			y := (v.ToFloat32() - mean) * inv * gamma[i].ToFloat32()
+			if beta != nil {
+				y += beta[i].ToFloat32()
+			}
+			dst[r+i] = float16.FromFloat32(y)
+		}
+	}
+}
+
+// LayerNormInPlace is LayerNorm(s, s, gamma, beta, eps)
+func LayerNormInPlace(s, gamma, beta []float16.Float16, eps float32) {
+	LayerNorm(s, s, gamma, beta, eps)
+}
+
+// RMSNorm divides each row of src by its root mean square and scales it by
+// weight, storing the result in dst:
+//
+//	y = x / sqrt(mean(x²) + eps) · weight
+//
+// The row length is len(weight), and len(src) must be a multiple of it. The
+// sum of squares is accumulated in float32, where it cannot overflow for
+// Float16 inputs.
+func RMSNorm(dst, src, weight []float16.Float16, eps float32) {
+	checkLen(dst, src)
+	n := checkRows(src, weight, nil)
+	for r := 0; r < len(src); r += n {
+		row := src[r : r+n]
+		var sumSq float32
+		for _, v := range row {
+			x := v.ToFloat32()
+			sumSq += x * x
+		}
+		inv := 1 / float32(math.Sqrt(float64(sumSq/float32(n)+eps)))
+		for i, v := range row {
+			dst[r+i] = float16.FromFloat32(v.ToFloat32() * inv * weight[i].ToFloat32())
+		}
+	}
+}
+
+// RMSNormInPlace is RMSNorm(s, s, weight, eps)
+func RMSNormInPlace(s, weight []float16.Float16, eps float32) {
+	RMSNorm(s, s, weight, eps)
+}
+
+// checkRows returns the row length len(weight), panicking unless it is
+// non-zero, divides len(src) and matches a non-nil bias
+func checkRows(src, weight, bias []float16.Float16) int {
+	n := len(weight)
+	if n == 0 || len(src)%n != 0 || bias != nil && len(bias) != n {
+		panic("nn: slice length mismatch")
+	}
+	return n
+}
@@ -0,0 +1,156 @@
+package nn
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+func randomHalf(rng *rand.Rand, n int, offset, scale float64) []float16.Float16 {
+	s := make([]float16.Float16, n)
+	for i := range s {
+		s[i] = float16.FromFloat64(offset + scale*rng.NormFloat64())
+	}
+	return s
+}
+
+// layerNorm64 is the float64 reference LayerNorm of one row
+func layerNorm64(x, gamma, beta []float16.Float16, eps float64) []float64 {
+	n := float64(len(x))
+	var mean, variance float64
+	for _, v := range x {
+		mean += v.ToFloat64()
+	}
+	mean /= n
+	for _, v := range x {
+		d := v.ToFloat64() - mean
+		variance += d * d
+	}
+	variance /= n
+	out := make([]float64, len(x))
+	for i, v := range x {
+		out[i] = (v.ToFloat64() - mean) / math.Sqrt(variance+eps) * gamma[i].ToFloat64()
+		if beta != nil {
+			out[i] += beta[i].ToFloat64()
+		}
+	}
+	return out
+}
+
+// rmsNorm64 is the float64 reference RMSNorm of one row
+func rmsNorm64(x, weight []float16.Float16, eps float64) []float64 {
+	var sumSq float64
+	for _, v := range x {
+		sumSq += v.ToFloat64() * v.ToFloat64()
+	}
+	rms := math.Sqrt(sumSq/float64(len(x)) + eps)
+	out := make([]float64, len(x))
+	for i, v := range x {
+		out[i] = v.ToFloat64() / rms * weight[i].ToFloat64()
+	}
+	return out
+}
+
+func TestLayerNorm(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	const rows, n, eps = 3, 256, 1e-5
+	gamma := randomHalf(rng, n, 1, 0.1)
+	beta := randomHalf(rng, n, 0, 0.1)
+	tests := []struct {
+		name string
+		src  []float16.Float16
+		beta []float16.Float16
+	}{
+		{"centered", randomHalf(rng, rows*n, 0, 1), beta},
+		{"no beta", randomHalf(rng, rows*n, 0, 1), nil},
+		// A half-precision running sum of 1000 + noise saturates its
+		// mantissa; the float32 passes keep the spread
+		{"offset", randomHalf(rng, rows*n, 1000, 4), beta},
+		{"large", randomHalf(rng, rows*n, 0, 20000), beta},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]float16.Float16, len(tt.src))
+			LayerNorm(got, tt.src, gamma, tt.beta, eps)
+			for r := 0; r < rows; r++ {
+				want := layerNorm64(tt.src[r*n:(r+1)*n], gamma, tt.beta, eps)
+				for i, w := range want {
+					if d := ulpDiff(got[r*n+i], w); d > 2 {
+						t.Fatalf("LayerNorm()[%d] = %v, want %v (%.3g ulp)", r*n+i, got[r*n+i], w, d)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRMSNorm(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	const rows, n, eps = 4, 128, 1e-6
+	weight := randomHalf(rng, n, 1, 0.1)
+	tests := []struct {
+		name string
+		src  []float16.Float16
+	}{
+		{"unit", randomHalf(rng, rows*n, 0, 1)},
+		// Squares of these overflow Float16 but not the float32 sum
+		{"large", randomHalf(rng, rows*n, 0, 30000)},
+		{"tiny", randomHalf(rng, rows*n, 0, 1e-3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]float16.Float16, len(tt.src))
+			RMSNorm(got, tt.src, weight, eps)
+			for r := 0; r < rows; r++ {
+				want := rmsNorm64(tt.src[r*n:(r+1)*n], weight, eps)
+				for i, w := range want {
+					if d := ulpDiff(got[r*n+i], w); d > 1 {
+						t.Fatalf("RMSNorm()[%d] = %v, want %v (%.3g ulp)", r*n+i, got[r*n+i], w, d)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestNormInPlace(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	src := randomHalf(rng, 64, 3, 2)
+	gamma := randomHalf(rng, 16, 1, 0.5)
+	beta := randomHalf(rng, 16, 0, 0.5)
+
+	want := make([]float16.Float16, len(src))
+	LayerNorm(want, src, gamma, beta, 1e-5)
+	got := append([]float16.Float16(nil), src...)
+	LayerNormInPlace(got, gamma, beta, 1e-5)
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("LayerNormInPlace()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	RMSNorm(want, src, gamma, 1e-5)
+	copy(got, src)
+	RMSNormInPlace(got, gamma, 1e-5)
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("RMSNormInPlace()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLayerNormConstantRow(t *testing.T) {
+	src := float16.ToSlice16([]float32{5, 5, 5, 5})
+	gamma := float16.ToSlice16([]float32{1, 1, 1, 1})
+	beta := float16.ToSlice16([]float32{0.5, 0.5, 0.5, 0.5})
+	LayerNormInPlace(src, gamma, beta, 1e-5)
+	for i, v := range src {
+		if v != float16.FromFloat32(0.5) {
+			t.Errorf("LayerNorm()[%d] = %v, want 0.5", i, v)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package nn
+
+import (
+	"math"
+
+	"github.com/zerfoo/float16"
+)
+
+// Softmax stores e^x / Σ e^x over src in dst. The maximum of src is
+// subtracted before exponentiating, so no input overflows the exponential;
+// the denominator is accumulated in float32. A NaN or +Inf input, or an
+// input of only -Inf, makes every output NaN. Nothing is allocated: the
+// exponentials are recomputed for the second pass rather than staged in dst
+// at half precision.
+func Softmax(dst, src []float16.Float16) {
+	checkLen(dst, src)
+	if len(src) == 0 {
+		return
+	}
+	m, sum := expSum(src)
+	inv := 1 / sum
+	for i, v := range src {
+		dst[i] = float16.FromFloat32(exp32(v.ToFloat32()-m) * inv)
+	}
+}
+
+// SoftmaxInPlace replaces s with its softmax
+func SoftmaxInPlace(s []float16.Float16) {
+	Softmax(s, s)
+}
+
+// LogSoftmax stores x - log Σ e^x over src in dst, computed as
+// (x - max) - log Σ e^(x - max) so that it neither overflows nor takes the
+// logarithm of an underflowed softmax
+func LogSoftmax(dst, src []float16.Float16) {
+	checkLen(dst, src)
+	if len(src) == 0 {
+		return
+	}
+	m, sum := expSum(src)
+	logSum := float32(math.Log(float64(sum)))
+	for i, v := range src {
+		dst[i] = float16.FromFloat32(v.ToFloat32() - m - logSum)
+	}
+}
+
+// LogSoftmaxInPlace replaces s with its log-softmax
+func LogSoftmaxInPlace(s []float16.Float16) {
+	LogSoftmax(s, s)
+}
+
+// expSum returns the maximum m of a non-empty s and Σ e^(x - m). The sum is
+// at least 1 unless s holds a NaN or +Inf or only -Inf, which make it NaN.
+func expSum(s []float16.Float16) (m, sum float32) {
+	m = float32(math.Inf(-1))
+	for _, v := range s {
+		m = max(m, v.ToFloat32())
+	}
+	for _, v := range s {
+		sum += exp32(v.ToFloat32() - m)
+	}
+	return m, sum
+}
@@ -0,0 +1,105 @@
+package nn
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+// softmax64 is the float64 reference softmax and log-softmax of x
+func softmax64(x []float16.Float16) (p, logp []float64) {
+	m := math.Inf(-1)
+	for _, v := range x {
+		m = math.Max(m, v.ToFloat64())
+	}
+	var sum float64
+	for _, v := range x {
+		sum += math.Exp(v.ToFloat64() - m)
+	}
+	for _, v := range x {
+		d := v.ToFloat64() - m
+		p = append(p, math.Exp(d)/sum)
+		logp = append(logp, d-math.Log(sum))
+	}
+	return p, logp
+}
+
+func TestSoftmax(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := make([]float16.Float16, 1000)
+	for i := range random {
+		random[i] = float16.FromFloat64(8 * rng.NormFloat64())
+	}
+	tests := []struct {
+		name string
+		src  []float16.Float16
+	}{
+		{"single", float16.ToSlice16([]float32{3})},
+		{"uniform", float16.ToSlice16([]float32{2, 2, 2, 2})},
+		// e^12 overflows Float16; the half-precision softmax broke here
+		{"around 11", float16.ToSlice16([]float32{11, 12, 10.5, -4})},
+		{"large", float16.ToSlice16([]float32{65504, 65000, -65504})},
+		{"random", random},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, logp := softmax64(tt.src)
+			got := make([]float16.Float16, len(tt.src))
+			Softmax(got, tt.src)
+			var sum float64
+			for i, want := range p {
+				sum += got[i].ToFloat64()
+				if d := ulpDiff(got[i], want); d > 0.501 {
+					t.Errorf("Softmax()[%d] = %v, want %v (%.3g ulp)", i, got[i], want, d)
+				}
+			}
+			if math.Abs(sum-1) > 0.01 {
+				t.Errorf("Softmax() sums to %v", sum)
+			}
+
+			LogSoftmax(got, tt.src)
+			for i, want := range logp {
+				if d := ulpDiff(got[i], want); d > 0.501 {
+					t.Errorf("LogSoftmax()[%d] = %v, want %v (%.3g ulp)", i, got[i], want, d)
+				}
+			}
+		})
+	}
+}
+
+func TestSoftmaxSpecialValues(t *testing.T) {
+	inf, ninf, nan := float16.PositiveInfinity, float16.NegativeInfinity, float16.QuietNaN
+	one := float16.FromFloat32(1)
+	tests := []struct {
+		name    string
+		src     []float16.Float16
+		want    []float16.Float16
+		wantLog []float16.Float16
+	}{
+		{"-inf entry", []float16.Float16{ninf, 0}, []float16.Float16{0, one}, []float16.Float16{ninf, 0}},
+		{"NaN entry", []float16.Float16{nan, 0}, []float16.Float16{nan, nan}, []float16.Float16{nan, nan}},
+		{"+inf entry", []float16.Float16{inf, 0}, []float16.Float16{nan, nan}, []float16.Float16{nan, nan}},
+		{"only -inf", []float16.Float16{ninf, ninf}, []float16.Float16{nan, nan}, []float16.Float16{nan, nan}},
+		{"empty", nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]float16.Float16, len(tt.src))
+			Softmax(got, tt.src)
+			gotLog := make([]float16.Float16, len(tt.src))
+			LogSoftmax(gotLog, tt.src)
+			for i := range tt.src {
+				if got[i] != tt.want[i] && !(got[i].IsNaN() && tt.want[i].IsNaN()) {
+					t.Errorf("Softmax()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+				if gotLog[i] != tt.wantLog[i] && !(gotLog[i].IsNaN() && tt.wantLog[i].IsNaN()) {
+					t.Errorf("LogSoftmax()[%d] = %v, want %v", i, gotLog[i], tt.wantLog[i])
+				}
+			}
+		})
+	}
+}
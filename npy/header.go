@@ -0,0 +1,158 @@
+package npy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseHeader decodes the Python dict literal stored in a .npy header, e.g.
+//
+//	{'descr': '<f2', 'fortran_order': False, 'shape': (3, 4), }
+//
+// Only the three keys numpy writes are recognised; unknown keys are rejected
+// the same way numpy rejects them.
+func parseHeader(s string) (*header, error) {
+	p := &literalParser{s: strings.TrimSpace(s)}
+	h := &header{}
+	seen := map[string]bool{}
+
+	if !p.consume('{') {
+		return nil, p.errorf("expected '{'")
+	}
+	for {
+		if p.consume('}') {
+			break
+		}
+		key, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(':') {
+			return nil, p.errorf("expected ':' after key %q", key)
+		}
+
+		switch key {
+		case "descr":
+			if h.descr, err = p.str(); err != nil {
+				return nil, err
+			}
+		case "fortran_order":
+			if h.fortranOrder, err = p.boolean(); err != nil {
+				return nil, err
+			}
+		case "shape":
+			if h.shape, err = p.tuple(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.errorf("unexpected key %q", key)
+		}
+		seen[key] = true
+
+		if !p.consume(',') {
+			if !p.consume('}') {
+				return nil, p.errorf("expected ',' or '}'")
+			}
+			break
+		}
+	}
+
+	for _, key := range []string{"descr", "fortran_order", "shape"} {
+		if !seen[key] {
+			return nil, fmt.Errorf("%w: missing key %q", ErrBadHeader, key)
+		}
+	}
+	return h, nil
+}
+
+// literalParser is a minimal scanner for the subset of Python literals used
+// in .npy headers: quoted strings, booleans and tuples of integers
+type literalParser struct {
+	s   string
+	pos int
+}
+
+func (p *literalParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrBadHeader, fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *literalParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t' || p.s[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// consume skips whitespace and then c if it is the next character
+func (p *literalParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// str parses a single- or double-quoted string without escapes
+func (p *literalParser) str() (string, error) {
+	p.skipSpace()
+	if p.pos >= len(p.s) || (p.s[p.pos] != '\'' && p.s[p.pos] != '"') {
+		return "", p.errorf("expected string")
+	}
+	quote := p.s[p.pos]
+	end := strings.IndexByte(p.s[p.pos+1:], quote)
+	if end < 0 {
+		return "", p.errorf("unterminated string")
+	}
+	v := p.s[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return v, nil
+}
+
+// boolean parses True or False
+func (p *literalParser) boolean() (bool, error) {
+	p.skipSpace()
+	switch {
+	case strings.HasPrefix(p.s[p.pos:], "True"):
+		p.pos += 4
+		return true, nil
+	case strings.HasPrefix(p.s[p.pos:], "False"):
+		p.pos += 5
+		return false, nil
+	}
+	return false, p.errorf("expected True or False")
+}
+
+// tuple parses a parenthesised, comma-separated tuple of non-negative integers
+func (p *literalParser) tuple() ([]int, error) {
+	if !p.consume('(') {
+		return nil, p.errorf("expected '('")
+	}
+	dims := []int{}
+	for {
+		if p.consume(')') {
+			return dims, nil
+		}
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		// numpy may write Python 2 long literals such as 3L
+		digits := p.s[start:p.pos]
+		if p.pos < len(p.s) && p.s[p.pos] == 'L' {
+			p.pos++
+		}
+		d, err := strconv.Atoi(digits)
+		if err != nil {
+			return nil, p.errorf("invalid dimension %q", digits)
+		}
+		dims = append(dims, d)
+		if !p.consume(',') {
+			if !p.consume(')') {
+				return nil, p.errorf("expected ',' or ')'")
+			}
+			return dims, nil
+		}
+	}
+}
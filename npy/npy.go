@@ -0,0 +1,371 @@
+// Package npy reads and writes NumPy .npy and .npz files holding
+// half-precision arrays.
+//
+// Arrays are loaded into []float16.Float16 together with their shape.
+// Files with dtype '<f2' or '>f2' are decoded directly; '<f4', '>f4', '<f8'
+// and '>f8' files are converted to Float16 on load. Header format versions
+// 1.0, 2.0 and 3.0 are supported for reading. Writing always produces
+// little-endian '<f2' data and uses the oldest header version that can
+// hold the header.
+//
+// See: https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html
+package npy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zerfoo/float16"
+)
+
+// magic is the prefix of every .npy file
+const magic = "\x93NUMPY"
+
+// headerAlign is the alignment numpy uses for the start of the array data
+const headerAlign = 64
+
+// Errors returned when decoding .npy data
+var (
+	ErrBadMagic         = errors.New("npy: not a .npy file")
+	ErrUnsupportedVer   = errors.New("npy: unsupported format version")
+	ErrUnsupportedDType = errors.New("npy: unsupported dtype")
+	ErrBadHeader        = errors.New("npy: malformed header")
+)
+
+// Array is an n-dimensional half-precision array.
+//
+// Data holds the elements in the order given by FortranOrder: row-major
+// (C order) when false, column-major (Fortran order) when true.
+type Array struct {
+	Data         []float16.Float16
+	Shape        []int
+	FortranOrder bool
+}
+
+// Size returns the number of elements described by the shape
+func (a *Array) Size() int {
+	n, _ := shapeSize(a.Shape)
+	return n
+}
+
+// offset returns the position in Data of the element at idx
+func (a *Array) offset(idx []int) int {
+	if len(idx) != len(a.Shape) {
+		panic("npy: index rank does not match array rank")
+	}
+	off, stride := 0, 1
+	for k := range idx {
+		d := k
+		if !a.FortranOrder {
+			d = len(idx) - 1 - k
+		}
+		if idx[d] < 0 || idx[d] >= a.Shape[d] {
+			panic("npy: index out of range")
+		}
+		off += idx[d] * stride
+		stride *= a.Shape[d]
+	}
+	return off
+}
+
+// At returns the element at the given multi-dimensional index, taking the
+// storage order into account
+func (a *Array) At(idx ...int) float16.Float16 {
+	return a.Data[a.offset(idx)]
+}
+
+// Set stores v at the given multi-dimensional index
+func (a *Array) Set(v float16.Float16, idx ...int) {
+	a.Data[a.offset(idx)] = v
+}
+
+// COrder returns a copy of the array with its data laid out in row-major order.
+// Arrays that are already in C order are copied unchanged.
+func (a *Array) COrder() *Array {
+	out := &Array{
+		Data:  make([]float16.Float16, len(a.Data)),
+		Shape: append([]int(nil), a.Shape...),
+	}
+	if !a.FortranOrder || len(a.Shape) < 2 {
+		copy(out.Data, a.Data)
+		return out
+	}
+	idx := make([]int, len(a.Shape))
+	for i := range out.Data {
+		out.Data[i] = a.At(idx...)
+		// Advance the index with the last axis varying fastest
+		for d := len(idx) - 1; d >= 0; d-- {
+			idx[d]++
+			if idx[d] < a.Shape[d] {
+				break
+			}
+			idx[d] = 0
+		}
+	}
+	return out
+}
+
+// header is the decoded .npy header dictionary
+type header struct {
+	descr        string
+	fortranOrder bool
+	shape        []int
+}
+
+// readChunk is the number of elements decoded per read. Data is read a
+// chunk at a time so that a header declaring more elements than the stream
+// holds fails at EOF instead of allocating for all of them up front.
+const readChunk = 1 << 16
+
+// Read decodes a .npy stream from r
+func Read(r io.Reader) (*Array, error) {
+	return readSized(r, -1)
+}
+
+// readSized decodes a .npy stream of size bytes, or of unknown length if
+// size is negative. A known size lets a header that declares more data than
+// the stream holds be rejected before any data is read.
+func readSized(r io.Reader, size int64) (*Array, error) {
+	br := bufio.NewReader(r)
+	h, hsize, err := readHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	n, ok := shapeSize(h.shape)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid shape %v", ErrBadHeader, h.shape)
+	}
+
+	order, kind, err := parseDescr(h.descr)
+	if err != nil {
+		return nil, err
+	}
+	// shapeSize keeps n*8 from overflowing
+	if remain := size - int64(hsize); size >= 0 && int64(n*kind) > remain {
+		return nil, fmt.Errorf("npy: reading data: shape %v needs %d bytes, %d remain: %w",
+			h.shape, n*kind, max(remain, 0), io.ErrUnexpectedEOF)
+	}
+
+	data := make([]float16.Float16, 0, min(n, readChunk))
+	var buf32 []float32
+	var buf64 []float64
+	for len(data) < n && err == nil {
+		m := min(n-len(data), readChunk)
+		data = slices.Grow(data, m)
+		chunk := data[len(data) : len(data)+m]
+		switch kind {
+		case 2:
+			err = float16.ReadFloat16s(br, order, chunk)
+		case 4:
+			buf32 = slices.Grow(buf32[:0], m)[:m]
+			if err = binary.Read(br, order, buf32); err == nil {
+				for i, v := range buf32 {
+					chunk[i] = float16.FromFloat32(v)
+				}
+			}
+		case 8:
+			buf64 = slices.Grow(buf64[:0], m)[:m]
+			if err = binary.Read(br, order, buf64); err == nil {
+				for i, v := range buf64 {
+					chunk[i] = float16.FromFloat64(v)
+				}
+			}
+		}
+		data = data[:len(data)+m]
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("npy: reading data: %w", err)
+	}
+
+	return &Array{Data: data, Shape: h.shape, FortranOrder: h.fortranOrder}, nil
+}
+
+// ReadFile reads a .npy file from disk
+func ReadFile(name string) (*Array, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return readSized(f, fi.Size())
+}
+
+// Write encodes a as a little-endian '<f2' .npy stream
+func Write(w io.Writer, a *Array) error {
+	n, ok := shapeSize(a.Shape)
+	if !ok || n != len(a.Data) {
+		return fmt.Errorf("npy: shape %v does not match %d elements", a.Shape, len(a.Data))
+	}
+
+	if _, err := w.Write(encodeHeader(a)); err != nil {
+		return err
+	}
+	return float16.WriteFloat16s(w, binary.LittleEndian, a.Data)
+}
+
+// WriteFile writes a to the named file, creating or truncating it
+func WriteFile(name string, a *Array) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	if err := Write(bw, a); err != nil {
+		f.Close()
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readHeader reads the magic string, version and header dictionary,
+// returning the header and its length in bytes
+func readHeader(r io.Reader) (*header, int, error) {
+	var pre [8]byte
+	if _, err := io.ReadFull(r, pre[:]); err != nil {
+		return nil, 0, ErrBadMagic
+	}
+	if string(pre[:6]) != magic {
+		return nil, 0, ErrBadMagic
+	}
+
+	var hlen, lenSize int
+	switch major := pre[6]; major {
+	case 1:
+		var l [2]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return nil, 0, ErrBadHeader
+		}
+		hlen, lenSize = int(binary.LittleEndian.Uint16(l[:])), 2
+	case 2, 3:
+		var l [4]byte
+		if _, err := io.ReadFull(r, l[:]); err != nil {
+			return nil, 0, ErrBadHeader
+		}
+		hlen, lenSize = int(binary.LittleEndian.Uint32(l[:])), 4
+	default:
+		return nil, 0, fmt.Errorf("%w: %d.%d", ErrUnsupportedVer, major, pre[7])
+	}
+
+	// A version 2.0 header length can reach 4 GiB, so only allocate for
+	// what the stream actually holds
+	raw, err := io.ReadAll(io.LimitReader(r, int64(hlen)))
+	if err != nil || len(raw) != hlen {
+		return nil, 0, ErrBadHeader
+	}
+	h, err := parseHeader(string(raw))
+	return h, len(pre) + lenSize + hlen, err
+}
+
+// encodeHeader builds the magic string, version, length and padded header dictionary
+func encodeHeader(a *Array) []byte {
+	var dict strings.Builder
+	dict.WriteString("{'descr': '<f2', 'fortran_order': ")
+	if a.FortranOrder {
+		dict.WriteString("True")
+	} else {
+		dict.WriteString("False")
+	}
+	dict.WriteString(", 'shape': (")
+	for i, d := range a.Shape {
+		if i > 0 {
+			dict.WriteString(", ")
+		}
+		dict.WriteString(strconv.Itoa(d))
+	}
+	if len(a.Shape) == 1 {
+		dict.WriteString(",")
+	}
+	dict.WriteString("), }")
+
+	// Version 1.0 has a 2-byte length field; fall back to 2.0 for huge headers
+	major, prefix := byte(1), 10
+	if dict.Len()+1+prefix+headerAlign > math.MaxUint16 {
+		major, prefix = 2, 12
+	}
+	pad := headerAlign - (prefix+dict.Len()+1)%headerAlign
+	if pad == headerAlign {
+		pad = 0
+	}
+	hlen := dict.Len() + pad + 1
+
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+	buf.WriteByte(major)
+	buf.WriteByte(0)
+	if major == 1 {
+		binary.Write(&buf, binary.LittleEndian, uint16(hlen))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(hlen))
+	}
+	buf.WriteString(dict.String())
+	buf.WriteString(strings.Repeat(" ", pad))
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// parseDescr maps a numpy dtype string to a byte order and element size
+func parseDescr(descr string) (binary.ByteOrder, int, error) {
+	if len(descr) != 3 || descr[1] != 'f' {
+		return nil, 0, fmt.Errorf("%w: %q", ErrUnsupportedDType, descr)
+	}
+
+	var order binary.ByteOrder
+	switch descr[0] {
+	case '<':
+		order = binary.LittleEndian
+	case '>':
+		order = binary.BigEndian
+	case '=', '|':
+		order = binary.NativeEndian
+	default:
+		return nil, 0, fmt.Errorf("%w: %q", ErrUnsupportedDType, descr)
+	}
+
+	switch descr[2] {
+	case '2':
+		return order, 2, nil
+	case '4':
+		return order, 4, nil
+	case '8':
+		return order, 8, nil
+	}
+	return nil, 0, fmt.Errorf("%w: %q", ErrUnsupportedDType, descr)
+}
+
+// shapeSize returns the element count for shape, reporting false on
+// negative dimensions or overflow
+func shapeSize(shape []int) (int, bool) {
+	n := 1
+	for _, d := range shape {
+		if d < 0 {
+			return 0, false
+		}
+		if d != 0 && n > (math.MaxInt/8)/d {
+			return 0, false
+		}
+		n *= d
+	}
+	return n, true
+}
@@ -0,0 +1,267 @@
+package npy
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+// rawNPY builds a .npy stream with the given version, header dict and payload
+func rawNPY(major byte, dict string, payload []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(magic)
+	buf.WriteByte(major)
+	buf.WriteByte(0)
+	if major == 1 {
+		binary.Write(&buf, binary.LittleEndian, uint16(len(dict)+1))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(dict)+1))
+	}
+	buf.WriteString(dict)
+	buf.WriteByte('\n')
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		arr  *Array
+	}{
+		{"scalar", &Array{Data: []float16.Float16{0x3C00}, Shape: []int{}}},
+		{"vector", &Array{Data: []float16.Float16{0x3C00, 0x4000, 0xC200}, Shape: []int{3}}},
+		{"matrix", &Array{Data: []float16.Float16{1, 2, 3, 4, 5, 6}, Shape: []int{2, 3}}},
+		{"fortran", &Array{Data: []float16.Float16{1, 2, 3, 4, 5, 6}, Shape: []int{3, 2}, FortranOrder: true}},
+		{"empty", &Array{Data: []float16.Float16{}, Shape: []int{0, 4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.arr); err != nil {
+				t.Fatalf("Write() unexpected error: %v", err)
+			}
+
+			// Data must start on a 64-byte boundary
+			hlen := int(binary.LittleEndian.Uint16(buf.Bytes()[8:10]))
+			if (10+hlen)%headerAlign != 0 {
+				t.Errorf("header length %d is not aligned to %d", 10+hlen, headerAlign)
+			}
+
+			got, err := Read(&buf)
+			if err != nil {
+				t.Fatalf("Read() unexpected error: %v", err)
+			}
+			if got.FortranOrder != tt.arr.FortranOrder {
+				t.Errorf("FortranOrder = %v, want %v", got.FortranOrder, tt.arr.FortranOrder)
+			}
+			if len(got.Shape) != len(tt.arr.Shape) {
+				t.Fatalf("Shape = %v, want %v", got.Shape, tt.arr.Shape)
+			}
+			for i := range got.Shape {
+				if got.Shape[i] != tt.arr.Shape[i] {
+					t.Errorf("Shape = %v, want %v", got.Shape, tt.arr.Shape)
+				}
+			}
+			for i := range tt.arr.Data {
+				if got.Data[i] != tt.arr.Data[i] {
+					t.Errorf("Data[%d] = 0x%04X, want 0x%04X", i, got.Data[i], tt.arr.Data[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadDTypesAndVersions(t *testing.T) {
+	want := []float64{1, -2, 0.5}
+
+	le16 := float16.AppendFloat16s(nil, binary.LittleEndian, 0x3C00, 0xC000, 0x3800)
+	be16 := float16.AppendFloat16s(nil, binary.BigEndian, 0x3C00, 0xC000, 0x3800)
+	f4 := make([]byte, 12)
+	f8 := make([]byte, 24)
+	for i, v := range want {
+		binary.BigEndian.PutUint32(f4[4*i:], math.Float32bits(float32(v)))
+		binary.LittleEndian.PutUint64(f8[8*i:], math.Float64bits(v))
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"v1 <f2", rawNPY(1, "{'descr': '<f2', 'fortran_order': False, 'shape': (3,), }", le16)},
+		{"v1 >f2", rawNPY(1, "{'descr': '>f2', 'fortran_order': False, 'shape': (3,), }", be16)},
+		{"v2 >f4", rawNPY(2, "{'descr': '>f4', 'fortran_order': False, 'shape': (3,), }", f4)},
+		{"v3 <f8", rawNPY(3, "{\"descr\": \"<f8\", \"shape\": (3L,), \"fortran_order\": False}", f8)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Read() unexpected error: %v", err)
+			}
+			if len(got.Data) != len(want) {
+				t.Fatalf("Read() returned %d elements, want %d", len(got.Data), len(want))
+			}
+			for i, w := range want {
+				if got.Data[i].ToFloat64() != w {
+					t.Errorf("Data[%d] = %v, want %v", i, got.Data[i], w)
+				}
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"bad magic", []byte("PK\x03\x04garbage"), ErrBadMagic},
+		{"bad version", rawNPY(9, "{}", nil), ErrUnsupportedVer},
+		{"int dtype", rawNPY(1, "{'descr': '<i4', 'fortran_order': False, 'shape': (1,), }", make([]byte, 4)), ErrUnsupportedDType},
+		{"missing key", rawNPY(1, "{'descr': '<f2', 'shape': (1,), }", make([]byte, 2)), ErrBadHeader},
+		{"unknown key", rawNPY(1, "{'descr': '<f2', 'fortran_order': False, 'shape': (1,), 'x': 1}", nil), ErrBadHeader},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Read() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	truncated := rawNPY(1, "{'descr': '<f2', 'fortran_order': False, 'shape': (4,), }", make([]byte, 3))
+	if _, err := Read(bytes.NewReader(truncated)); err == nil {
+		t.Error("Read() with truncated data should fail")
+	}
+}
+
+func TestReadOversizedShape(t *testing.T) {
+	// 2^40 elements would need terabytes if allocated from the header alone
+	huge := rawNPY(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (1099511627776,), }", make([]byte, 64))
+	if _, err := Read(bytes.NewReader(huge)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Read() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+	if _, err := readSized(bytes.NewReader(huge), int64(len(huge))); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("readSized() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+
+	path := filepath.Join(t.TempDir(), "huge.npy")
+	if err := os.WriteFile(path, huge, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(path); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadFile() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+
+	// Data spanning several chunks still reads in full
+	big := &Array{Data: make([]float16.Float16, 2*readChunk+3), Shape: []int{2*readChunk + 3}}
+	for i := range big.Data {
+		big.Data[i] = float16.FromBits(uint16(i))
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, big); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Read(&buf); err != nil || !slices.Equal(got.Data, big.Data) {
+		t.Errorf("Read() of %d elements: error %v or data mismatch", len(big.Data), err)
+	}
+
+	// A version 2.0 header may claim up to 4 GiB
+	longHeader := []byte(magic + "\x02\x00\xff\xff\xff\xff{}")
+	if _, err := Read(bytes.NewReader(longHeader)); !errors.Is(err, ErrBadHeader) {
+		t.Errorf("Read() error = %v, want %v", err, ErrBadHeader)
+	}
+}
+
+func TestFortranOrder(t *testing.T) {
+	// [[1, 2, 3], [4, 5, 6]] stored column-major
+	a := &Array{
+		Data:         []float16.Float16{0x3C00, 0x4400, 0x4000, 0x4500, 0x4200, 0x4600},
+		Shape:        []int{2, 3},
+		FortranOrder: true,
+	}
+	if got := a.At(0, 1); got != 0x4000 {
+		t.Errorf("At(0, 1) = %v, want 2", got)
+	}
+	if got := a.At(1, 2); got != 0x4600 {
+		t.Errorf("At(1, 2) = %v, want 6", got)
+	}
+
+	c := a.COrder()
+	want := []float16.Float16{0x3C00, 0x4000, 0x4200, 0x4400, 0x4500, 0x4600}
+	for i := range want {
+		if c.Data[i] != want[i] {
+			t.Errorf("COrder().Data[%d] = %v, want %v", i, c.Data[i], want[i])
+		}
+	}
+	if c.FortranOrder {
+		t.Error("COrder() should clear FortranOrder")
+	}
+}
+
+func TestNPZ(t *testing.T) {
+	arrays := map[string]*Array{
+		"weights": {Data: []float16.Float16{0x3C00, 0x4000, 0x4200, 0x4400}, Shape: []int{2, 2}},
+		"bias":    {Data: []float16.Float16{0xBC00}, Shape: []int{1}},
+	}
+
+	for _, compressed := range []bool{false, true} {
+		var buf bytes.Buffer
+		var err error
+		if compressed {
+			err = WriteNPZCompressed(&buf, arrays)
+		} else {
+			err = WriteNPZ(&buf, arrays)
+		}
+		if err != nil {
+			t.Fatalf("WriteNPZ(compressed=%v) unexpected error: %v", compressed, err)
+		}
+
+		got, err := ReadNPZ(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatalf("ReadNPZ(compressed=%v) unexpected error: %v", compressed, err)
+		}
+		if len(got) != len(arrays) {
+			t.Fatalf("ReadNPZ() returned %d arrays, want %d", len(got), len(arrays))
+		}
+		for name, want := range arrays {
+			a, ok := got[name]
+			if !ok {
+				t.Fatalf("ReadNPZ() missing array %q", name)
+			}
+			for i := range want.Data {
+				if a.Data[i] != want.Data[i] {
+					t.Errorf("%s.Data[%d] = %v, want %v", name, i, a.Data[i], want.Data[i])
+				}
+			}
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.npy")
+	a := &Array{Data: []float16.Float16{0x3C00, 0x7C00}, Shape: []int{2}}
+	if err := WriteFile(path, a); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() unexpected error: %v", err)
+	}
+	if got.Data[1] != float16.PositiveInfinity {
+		t.Errorf("ReadFile().Data[1] = %v, want +Inf", got.Data[1])
+	}
+}
@@ -0,0 +1,86 @@
+package npy
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ReadNPZ decodes every array in a .npz archive.
+// The returned map is keyed by array name, i.e. the member file name without
+// its ".npy" suffix, matching the keys numpy.load exposes.
+func ReadNPZ(r io.ReaderAt, size int64) (map[string]*Array, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("npy: reading npz: %w", err)
+	}
+
+	arrays := make(map[string]*Array, len(zr.File))
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, ".npy") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("npy: opening %s: %w", f.Name, err)
+		}
+		a, err := readSized(rc, int64(min(f.UncompressedSize64, math.MaxInt64)))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("npy: decoding %s: %w", f.Name, err)
+		}
+		arrays[strings.TrimSuffix(f.Name, ".npy")] = a
+	}
+	return arrays, nil
+}
+
+// ReadNPZFile reads a .npz archive from disk
+func ReadNPZFile(name string) (map[string]*Array, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return ReadNPZ(f, fi.Size())
+}
+
+// WriteNPZ writes arrays as an uncompressed .npz archive, like numpy.savez
+func WriteNPZ(w io.Writer, arrays map[string]*Array) error {
+	return writeNPZ(w, arrays, zip.Store)
+}
+
+// WriteNPZCompressed writes arrays as a deflate-compressed .npz archive,
+// like numpy.savez_compressed
+func WriteNPZCompressed(w io.Writer, arrays map[string]*Array) error {
+	return writeNPZ(w, arrays, zip.Deflate)
+}
+
+func writeNPZ(w io.Writer, arrays map[string]*Array, method uint16) error {
+	// Sort names so the archive layout is deterministic
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: method})
+		if err != nil {
+			return err
+		}
+		if err := Write(fw, arrays[name]); err != nil {
+			return fmt.Errorf("npy: encoding %s: %w", name, err)
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,217 @@
+package float16
+
+import "fmt"
+
+// IEEE 754-2019 ordering, comparison and selection operations (§5.10, §5.11
+// and §9.6)
+
+// totalKey maps f to a uint16 whose unsigned order is IEEE 754 totalOrder:
+// -NaN < -Inf < negative numbers < -0 < +0 < positive numbers < +Inf < +NaN,
+// with signaling NaNs below quiet NaNs of the same sign and NaNs of the same
+// kind ordered by payload (reversed for negative NaNs)
+func totalKey(f Float16) uint16 {
+	if f&SignMask != 0 {
+		return ^uint16(f)
+	}
+	return uint16(f) | SignMask
+}
+
+// TotalOrder reports whether a precedes or equals b in the IEEE 754
+// totalOrder predicate, which orders every bit pattern: -0 before +0, and
+// NaNs at the ends by sign
+func TotalOrder(a, b Float16) bool {
+	return totalKey(a) <= totalKey(b)
+}
+
+// TotalOrderMag reports whether |a| precedes or equals |b| in totalOrder
+func TotalOrderMag(a, b Float16) bool {
+	return TotalOrder(a.Abs(), b.Abs())
+}
+
+// Compare returns -1, 0 or +1 as a precedes, equals or follows b in
+// totalOrder. Unlike cmp.Compare on floats, it distinguishes -0 from +0 and
+// NaNs from each other, so slices.SortFunc(s, Compare) sorts bit patterns
+// deterministically and slices.Compact then keeps one of each.
+func Compare(a, b Float16) int {
+	ka, kb := totalKey(a), totalKey(b)
+	switch {
+	case ka < kb:
+		return -1
+	case ka > kb:
+		return 1
+	}
+	return 0
+}
+
+// Relation is the result of an IEEE 754 comparison. Exactly one relation
+// holds between any two values: a NaN is unordered with everything,
+// itself included.
+type Relation int
+
+const (
+	// RelationLess means a < b
+	RelationLess Relation = iota
+	// RelationEqual means a == b, which includes -0 == +0
+	RelationEqual
+	// RelationGreater means a > b
+	RelationGreater
+	// RelationUnordered means a or b is NaN
+	RelationUnordered
+)
+
+// String returns the name of the relation
+func (r Relation) String() string {
+	switch r {
+	case RelationLess:
+		return "less"
+	case RelationEqual:
+		return "equal"
+	case RelationGreater:
+		return "greater"
+	case RelationUnordered:
+		return "unordered"
+	}
+	return fmt.Sprintf("Relation(%d)", int(r))
+}
+
+// CompareQuiet returns the numeric relation between a and b, with -0 equal
+// to +0. Like the IEEE 754 quiet predicates, such as compareQuietEqual and
+// Go's == on floats, it raises the invalid operation exception only for a
+// signaling NaN operand, returning an ErrInvalidOperation *Float16Error
+// along with RelationUnordered.
+func CompareQuiet(a, b Float16) (Relation, error) {
+	r := relation(a, b)
+	if a.IsSignalingNaN() || b.IsSignalingNaN() {
+		return r, &Float16Error{Op: "compareQuiet", Value: nanOperand(a, b), Msg: "signaling NaN operand", Code: ErrInvalidOperation}
+	}
+	return r, nil
+}
+
+// CompareSignaling returns the numeric relation between a and b, with -0
+// equal to +0. Like the IEEE 754 signaling predicates, such as
+// compareSignalingLess, it raises the invalid operation exception for any
+// NaN operand, quiet or signaling, returning an ErrInvalidOperation
+// *Float16Error along with RelationUnordered. Use it where a NaN means that
+// something has gone wrong.
+func CompareSignaling(a, b Float16) (Relation, error) {
+	r := relation(a, b)
+	if r == RelationUnordered {
+		return r, &Float16Error{Op: "compareSignaling", Value: nanOperand(a, b), Msg: "NaN operand", Code: ErrInvalidOperation}
+	}
+	return r, nil
+}
+
+// relation returns the numeric relation between a and b
+func relation(a, b Float16) Relation {
+	if a.IsNaN() || b.IsNaN() {
+		return RelationUnordered
+	}
+	switch ka, kb := orderKey(a), orderKey(b); {
+	case ka < kb:
+		return RelationLess
+	case ka > kb:
+		return RelationGreater
+	}
+	return RelationEqual
+}
+
+// Minimum returns the smaller of a and b, treating -0 as less than +0. It is
+// the IEEE 754-2019 minimum operation: a NaN operand gives a quiet NaN.
+func Minimum(a, b Float16) Float16 {
+	switch {
+	case a.IsNaN():
+		return a.Quiet()
+	case b.IsNaN():
+		return b.Quiet()
+	}
+	return minNumeric(a, b)
+}
+
+// Maximum returns the larger of a and b, treating +0 as greater than -0. It
+// is the IEEE 754-2019 maximum operation: a NaN operand gives a quiet NaN.
+func Maximum(a, b Float16) Float16 {
+	switch {
+	case a.IsNaN():
+		return a.Quiet()
+	case b.IsNaN():
+		return b.Quiet()
+	}
+	return maxNumeric(a, b)
+}
+
+// MinimumNumber returns the smaller of a and b, treating -0 as less than
+// +0. It is the IEEE 754-2019 minimumNumber operation: a NaN operand is
+// ignored, and only two NaNs give a quiet NaN.
+func MinimumNumber(a, b Float16) Float16 {
+	switch {
+	case a.IsNaN() && b.IsNaN():
+		return a.Quiet()
+	case a.IsNaN():
+		return b
+	case b.IsNaN():
+		return a
+	}
+	return minNumeric(a, b)
+}
+
+// MaximumNumber returns the larger of a and b, treating +0 as greater than
+// -0. It is the IEEE 754-2019 maximumNumber operation: a NaN operand is
+// ignored, and only two NaNs give a quiet NaN.
+func MaximumNumber(a, b Float16) Float16 {
+	switch {
+	case a.IsNaN() && b.IsNaN():
+		return a.Quiet()
+	case a.IsNaN():
+		return b
+	case b.IsNaN():
+		return a
+	}
+	return maxNumeric(a, b)
+}
+
+// MinMag returns whichever of a and b has the smaller magnitude, or
+// MinimumNumber(a, b) if the magnitudes are equal. As in the IEEE 754-2008
+// minNumMag operation, a NaN operand is ignored.
+func MinMag(a, b Float16) Float16 {
+	if !a.IsNaN() && !b.IsNaN() {
+		switch aa, ab := a.Abs(), b.Abs(); {
+		case aa < ab:
+			return a
+		case ab < aa:
+			return b
+		}
+	}
+	return MinimumNumber(a, b)
+}
+
+// MaxMag returns whichever of a and b has the larger magnitude, or
+// MaximumNumber(a, b) if the magnitudes are equal. As in the IEEE 754-2008
+// maxNumMag operation, a NaN operand is ignored.
+func MaxMag(a, b Float16) Float16 {
+	if !a.IsNaN() && !b.IsNaN() {
+		switch aa, ab := a.Abs(), b.Abs(); {
+		case aa > ab:
+			return a
+		case ab > aa:
+			return b
+		}
+	}
+	return MaximumNumber(a, b)
+}
+
+// minNumeric returns the smaller of two non-NaN values, -0 before +0; for
+// non-NaN values totalOrder is the numeric order with that tie-break
+func minNumeric(a, b Float16) Float16 {
+	if totalKey(b) < totalKey(a) {
+		return b
+	}
+	return a
+}
+
+// maxNumeric returns the larger of two non-NaN values, +0 after -0
+func maxNumeric(a, b Float16) Float16 {
+	if totalKey(b) > totalKey(a) {
+		return b
+	}
+	return a
+}
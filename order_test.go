@@ -0,0 +1,234 @@
+package float16
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// totalOrderRef is the IEEE 754 totalOrder predicate written out from its
+// definition in §5.10
+func totalOrderRef(a, b Float16) bool {
+	x, y := a.ToFloat64(), b.ToFloat64()
+	switch {
+	case !a.IsNaN() && !b.IsNaN():
+		if x != y {
+			return x < y
+		}
+		return a.Signbit() || !b.Signbit() // -0 precedes +0
+	case a.IsNaN() && b.IsNaN():
+		if a.Signbit() != b.Signbit() {
+			return a.Signbit()
+		}
+		// Signaling before quiet, then by payload; reversed when negative
+		if a.Signbit() {
+			return b&0x3FF <= a&0x3FF
+		}
+		return a&0x3FF <= b&0x3FF
+	case a.IsNaN():
+		return a.Signbit()
+	default:
+		return !b.Signbit()
+	}
+}
+
+// orderSample returns every special value and a spread of other patterns
+func orderSample() []Float16 {
+	s := []Float16{
+		PositiveZero, NegativeZero, PositiveInfinity, NegativeInfinity,
+		QuietNaN, NegativeQNaN, SignalingNaN, SignalingNaN | SignMask,
+		0x7C01, 0x7FFF, 0xFC01, 0xFFFF, SmallestSubnormal, SmallestSubnormal | SignMask,
+		MaxValue, MinValue,
+	}
+	for b := 0; b < 1<<16; b += 97 {
+		s = append(s, FromBits(uint16(b)))
+	}
+	return s
+}
+
+func TestTotalOrder(t *testing.T) {
+	s := orderSample()
+	for _, a := range s {
+		for _, b := range s {
+			if got, want := TotalOrder(a, b), totalOrderRef(a, b); got != want {
+				t.Fatalf("TotalOrder(%#04x, %#04x) = %v, want %v", uint16(a), uint16(b), got, want)
+			}
+			if got, want := TotalOrderMag(a, b), totalOrderRef(a.Abs(), b.Abs()); got != want {
+				t.Fatalf("TotalOrderMag(%#04x, %#04x) = %v, want %v", uint16(a), uint16(b), got, want)
+			}
+			want := 0
+			switch {
+			case a != b && TotalOrder(a, b):
+				want = -1
+			case a != b:
+				want = 1
+			}
+			if got := Compare(a, b); got != want {
+				t.Fatalf("Compare(%#04x, %#04x) = %d, want %d", uint16(a), uint16(b), got, want)
+			}
+		}
+	}
+}
+
+func TestCompareSort(t *testing.T) {
+	one := FromFloat32(1)
+	s := []Float16{QuietNaN, one, PositiveZero, NegativeQNaN, NegativeZero, PositiveInfinity, one, NegativeZero, one.Neg(), SignalingNaN}
+	slices.SortFunc(s, Compare)
+	want := []Float16{NegativeQNaN, one.Neg(), NegativeZero, NegativeZero, PositiveZero, one, one, PositiveInfinity, SignalingNaN, QuietNaN}
+	if !slices.Equal(s, want) {
+		t.Errorf("sorted = %v, want %v", s, want)
+	}
+	s = slices.Compact(s)
+	if len(s) != 8 {
+		t.Errorf("compacted to %d values, want 8: %v", len(s), s)
+	}
+}
+
+func TestMinimumMaximum(t *testing.T) {
+	one, two := FromFloat32(1), FromFloat32(2)
+	snan := SignalingNaN | 0x0005 // payload 0x105, quiet bit clear
+	tests := []struct {
+		name                         string
+		a, b                         Float16
+		minimum, maximum             Float16
+		minimumNumber, maximumNumber Float16
+	}{
+		{"1, 2", one, two, one, two, one, two},
+		{"2, -1", two, one.Neg(), one.Neg(), two, one.Neg(), two},
+		{"-0, +0", NegativeZero, PositiveZero, NegativeZero, PositiveZero, NegativeZero, PositiveZero},
+		{"+0, -0", PositiveZero, NegativeZero, NegativeZero, PositiveZero, NegativeZero, PositiveZero},
+		{"-inf, +inf", NegativeInfinity, PositiveInfinity, NegativeInfinity, PositiveInfinity, NegativeInfinity, PositiveInfinity},
+		{"NaN, 1", QuietNaN, one, QuietNaN, QuietNaN, one, one},
+		{"1, -NaN", one, NegativeQNaN, NegativeQNaN, NegativeQNaN, one, one},
+		{"sNaN, 1", snan, one, snan | 0x0200, snan | 0x0200, one, one},
+		{"NaN, NaN", NegativeQNaN, QuietNaN, NegativeQNaN, NegativeQNaN, NegativeQNaN, NegativeQNaN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := []struct {
+				op        string
+				got, want Float16
+			}{
+				{"Minimum", Minimum(tt.a, tt.b), tt.minimum},
+				{"Maximum", Maximum(tt.a, tt.b), tt.maximum},
+				{"MinimumNumber", MinimumNumber(tt.a, tt.b), tt.minimumNumber},
+				{"MaximumNumber", MaximumNumber(tt.a, tt.b), tt.maximumNumber},
+			}
+			for _, c := range checks {
+				if c.got != c.want {
+					t.Errorf("%s() = %#04x, want %#04x", c.op, uint16(c.got), uint16(c.want))
+				}
+			}
+		})
+	}
+}
+
+func TestMinMag(t *testing.T) {
+	one, two := FromFloat32(1), FromFloat32(2)
+	tests := []struct {
+		name           string
+		a, b           Float16
+		minMag, maxMag Float16
+	}{
+		{"-2, 1", two.Neg(), one, one, two.Neg()},
+		{"1, -2", one, two.Neg(), one, two.Neg()},
+		{"-1, 1", one.Neg(), one, one.Neg(), one},
+		{"+0, -0", PositiveZero, NegativeZero, NegativeZero, PositiveZero},
+		{"-inf, 1", NegativeInfinity, one, one, NegativeInfinity},
+		{"NaN, -2", QuietNaN, two.Neg(), two.Neg(), two.Neg()},
+		{"NaN, NaN", QuietNaN, QuietNaN, QuietNaN, QuietNaN},
+	}
+
+	for _, tt := range tests {
+		if got := MinMag(tt.a, tt.b); got != tt.minMag {
+			t.Errorf("MinMag(%s) = %v, want %v", tt.name, got, tt.minMag)
+		}
+		if got := MaxMag(tt.a, tt.b); got != tt.maxMag {
+			t.Errorf("MaxMag(%s) = %v, want %v", tt.name, got, tt.maxMag)
+		}
+	}
+}
+
+func TestMinMaxMatchNumberOps(t *testing.T) {
+	s := orderSample()
+	for _, a := range s {
+		for _, b := range s {
+			if a.IsNaN() && b.IsNaN() {
+				continue
+			}
+			if got, want := Min(a, b), MinimumNumber(a, b); got != want {
+				t.Fatalf("Min(%#04x, %#04x) = %#04x, want %#04x", uint16(a), uint16(b), uint16(got), uint16(want))
+			}
+			if got, want := Max(a, b), MaximumNumber(a, b); got != want {
+				t.Fatalf("Max(%#04x, %#04x) = %#04x, want %#04x", uint16(a), uint16(b), uint16(got), uint16(want))
+			}
+			// The numeric result agrees with math.Min and math.Max
+			if !a.IsNaN() && !b.IsNaN() {
+				x, y := a.ToFloat64(), b.ToFloat64()
+				if got := Minimum(a, b).ToFloat64(); got != math.Min(x, y) || math.Signbit(got) != math.Signbit(math.Min(x, y)) {
+					t.Fatalf("Minimum(%v, %v) = %v, want %v", a, b, got, math.Min(x, y))
+				}
+				if got := Maximum(a, b).ToFloat64(); got != math.Max(x, y) || math.Signbit(got) != math.Signbit(math.Max(x, y)) {
+					t.Fatalf("Maximum(%v, %v) = %v, want %v", a, b, got, math.Max(x, y))
+				}
+			}
+		}
+	}
+}
+
+func TestCompareQuietSignaling(t *testing.T) {
+	one, two := FromFloat32(1), FromFloat32(2)
+	tests := []struct {
+		name         string
+		a, b         Float16
+		want         Relation
+		quietErr     bool
+		signalingErr bool
+		value        Float16 // the operand an error reports
+	}{
+		{"1, 2", one, two, RelationLess, false, false, 0},
+		{"2, 1", two, one, RelationGreater, false, false, 0},
+		{"-0, +0", NegativeZero, PositiveZero, RelationEqual, false, false, 0},
+		{"-inf, max", NegativeInfinity, MaxValue, RelationLess, false, false, 0},
+		{"qNaN, 1", QuietNaN, one, RelationUnordered, false, true, QuietNaN},
+		{"1, -qNaN", one, QuietNaN.Neg(), RelationUnordered, false, true, QuietNaN.Neg()},
+		{"qNaN, qNaN", QuietNaN, QuietNaN, RelationUnordered, false, true, QuietNaN},
+		{"1, sNaN", one, SignalingNaN, RelationUnordered, true, true, SignalingNaN},
+		{"-sNaN, qNaN", SignalingNaN | SignMask, QuietNaN, RelationUnordered, true, true, SignalingNaN | SignMask},
+		{"qNaN, sNaN", QuietNaN, SignalingNaN, RelationUnordered, true, true, SignalingNaN},
+	}
+
+	for _, tt := range tests {
+		for _, c := range []struct {
+			op      string
+			fn      func(a, b Float16) (Relation, error)
+			wantErr bool
+		}{
+			{"compareQuiet", CompareQuiet, tt.quietErr},
+			{"compareSignaling", CompareSignaling, tt.signalingErr},
+		} {
+			got, err := c.fn(tt.a, tt.b)
+			if got != tt.want {
+				t.Errorf("%s(%s) = %v, want %v", c.op, tt.name, got, tt.want)
+			}
+			if (err != nil) != c.wantErr {
+				t.Errorf("%s(%s) error = %v, want error %v", c.op, tt.name, err, c.wantErr)
+			}
+			if e, ok := err.(*Float16Error); err != nil && (!ok || e.Code != ErrInvalidOperation || e.Op != c.op || e.Value != tt.value) {
+				t.Errorf("%s(%s) error = %v, want ErrInvalidOperation from %s with value %#04x", c.op, tt.name, err, c.op, uint16(tt.value))
+			}
+		}
+	}
+
+	// The ordered relations agree with Less and Equal
+	s := orderSample()
+	for _, a := range s {
+		for _, b := range s[:100] {
+			r, _ := CompareQuiet(a, b)
+			if (r == RelationLess) != Less(a, b) || (r == RelationEqual) != Equal(a, b) || (r == RelationGreater) != Greater(a, b) {
+				t.Fatalf("CompareQuiet(%#04x, %#04x) = %v", uint16(a), uint16(b), r)
+			}
+		}
+	}
+}
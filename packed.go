@@ -0,0 +1,128 @@
+package float16
+
+import "math"
+
+// Packed GPU float formats: unsigned 11- and 10-bit floats, R11G11B10F and
+// RGB9E5 shared-exponent texels.
+//
+// The 11- and 10-bit floats have no sign bit and use the half-precision
+// exponent layout (5 bits, bias ExponentBias) with 6 and 5 mantissa bits, so
+// they are half-precision values with low mantissa bits dropped. Packing
+// follows the Direct3D and OpenGL conversion rules: negative values become
+// 0, NaN stays NaN, finite values round to nearest even and clamp to the
+// largest finite value, and infinity is preserved. Unpacking is exact.
+
+// Packed float format constants
+const (
+	Float11MantissaLen = 6 // Mantissa bits of an unsigned 11-bit float
+	Float10MantissaLen = 5 // Mantissa bits of an unsigned 10-bit float
+
+	// RGB9E5 has three 9-bit mantissas sharing one 5-bit exponent with the
+	// half-precision bias, and no implicit leading bit
+	RGB9E5MantissaLen = 9
+	// RGB9E5MaxValue is the largest component, (2^9-1)/2^9 * 2^(31-15)
+	RGB9E5MaxValue = (1<<RGB9E5MantissaLen - 1) << (ExponentMax - ExponentBias - RGB9E5MantissaLen) // 65408
+)
+
+// toPackedFloat converts f to an unsigned float with mantLen mantissa bits
+func toPackedFloat(f Float16, mantLen uint) uint16 {
+	shift := MantissaLen - mantLen
+	expMask := uint16(ExponentMask) >> shift
+	switch {
+	case f.IsNaN():
+		// Keep the top payload bits, forcing a non-zero mantissa
+		m := uint16(f&MantissaMask) >> shift
+		if m == 0 {
+			m = 1 << (mantLen - 1)
+		}
+		return expMask | m
+	case f&SignMask != 0:
+		return 0 // negative values and -Inf clamp to zero
+	case f == PositiveInfinity:
+		return expMask
+	}
+	// Round to nearest even on the bits; the shared exponent layout makes
+	// this correct across the subnormal and normal ranges
+	b := uint16(f)
+	half := uint16(1)<<(shift-1) - 1
+	r := (b + half + (b>>shift)&1) >> shift
+	return min(r, expMask-1) // clamp to the largest finite value
+}
+
+// fromPackedFloat converts an unsigned float with mantLen mantissa bits to Float16
+func fromPackedFloat(v uint16, mantLen uint) Float16 {
+	return Float16(v&(1<<(ExponentLen+mantLen)-1)) << (MantissaLen - mantLen)
+}
+
+// ToFloat11 converts f to an unsigned 11-bit float stored in the low bits
+func ToFloat11(f Float16) uint16 { return toPackedFloat(f, Float11MantissaLen) }
+
+// FromFloat11 converts the unsigned 11-bit float in the low bits of v to Float16 exactly
+func FromFloat11(v uint16) Float16 { return fromPackedFloat(v, Float11MantissaLen) }
+
+// ToFloat10 converts f to an unsigned 10-bit float stored in the low bits
+func ToFloat10(f Float16) uint16 { return toPackedFloat(f, Float10MantissaLen) }
+
+// FromFloat10 converts the unsigned 10-bit float in the low bits of v to Float16 exactly
+func FromFloat10(v uint16) Float16 { return fromPackedFloat(v, Float10MantissaLen) }
+
+// PackR11G11B10F packs a color into the R11G11B10F format: red in bits 0-10,
+// green in bits 11-21 and blue in bits 22-31
+func PackR11G11B10F(r, g, b Float16) uint32 {
+	return uint32(ToFloat11(r)) | uint32(ToFloat11(g))<<11 | uint32(ToFloat10(b))<<22
+}
+
+// UnpackR11G11B10F unpacks an R11G11B10F value. The conversion is exact.
+func UnpackR11G11B10F(v uint32) (r, g, b Float16) {
+	return FromFloat11(uint16(v & 0x7FF)), FromFloat11(uint16(v >> 11 & 0x7FF)), FromFloat10(uint16(v >> 22))
+}
+
+// PackRGB9E5 packs a color into the RGB9E5 shared-exponent format: red in
+// bits 0-8, green in bits 9-17, blue in bits 18-26 and the exponent in bits
+// 27-31.
+//
+// The encoding follows EXT_texture_shared_exponent bit for bit: NaN and
+// negative components become 0, components are clamped to RGB9E5MaxValue,
+// the exponent is chosen from the largest component and mantissas are
+// rounded half up.
+func PackRGB9E5(r, g, b Float16) uint32 {
+	rc, gc, bc := clampRGB9E5(r), clampRGB9E5(g), clampRGB9E5(b)
+	maxc := max(rc, gc, bc)
+
+	// exp = max(-B-1, floor(log2(maxc))) + 1 + B
+	exp := 0
+	if maxc > 0 {
+		_, e := math.Frexp(maxc) // maxc = frac * 2^e with frac in [0.5, 1)
+		exp = max(-ExponentBias-1, e-1) + 1 + ExponentBias
+	}
+	scale := math.Ldexp(1, RGB9E5MantissaLen+ExponentBias-exp)
+	if math.Floor(maxc*scale+0.5) == 1<<RGB9E5MantissaLen {
+		exp++
+		scale /= 2
+	}
+
+	rs := uint32(math.Floor(rc*scale + 0.5))
+	gs := uint32(math.Floor(gc*scale + 0.5))
+	bs := uint32(math.Floor(bc*scale + 0.5))
+	return rs | gs<<9 | bs<<18 | uint32(exp)<<27
+}
+
+// clampRGB9E5 clamps a component to [0, RGB9E5MaxValue], mapping NaN to 0
+func clampRGB9E5(f Float16) float64 {
+	v := f.ToFloat64()
+	if !(v > 0) {
+		return 0
+	}
+	return min(v, RGB9E5MaxValue)
+}
+
+// UnpackRGB9E5 unpacks an RGB9E5 value. Every RGB9E5 value is exactly
+// representable as Float16, so the conversion is exact.
+func UnpackRGB9E5(v uint32) (r, g, b Float16) {
+	exp := int(v >> 27)
+	scale := math.Ldexp(1, exp-ExponentBias-RGB9E5MantissaLen)
+	decode := func(m uint32) Float16 {
+		return FromFloat64(float64(m&0x1FF) * scale)
+	}
+	return decode(v), decode(v >> 9), decode(v >> 18)
+}
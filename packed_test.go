@@ -0,0 +1,141 @@
+package float16
+
+import (
+	"math"
+	"testing"
+)
+
+func TestToFloat11(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Float16
+		want uint16
+	}{
+		{"zero", PositiveZero, 0},
+		{"negative zero", NegativeZero, 0},
+		{"one", 0x3C00, 0x3C0},
+		{"negative", 0xBC00, 0},
+		{"negative infinity", NegativeInfinity, 0},
+		{"infinity", PositiveInfinity, 0x7C0},
+		{"nan", QuietNaN, 0x7E0},
+		{"low payload nan", 0x7C01, 0x7E0},
+		{"negative nan", NegativeQNaN, 0x7E0},
+		{"max clamps", MaxValue, 0x7BF},
+		{"round down", 0x3C07, 0x3C0},
+		{"tie to even down", 0x3C08, 0x3C0},
+		{"tie to even up", 0x3C18, 0x3C2},
+		{"round up", 0x3C09, 0x3C1},
+		{"subnormal", 0x0030, 0x003},
+		{"subnormal to normal", 0x03F8, 0x040},
+		{"smallest subnormal", SmallestSubnormal, 0},
+	}
+	for _, tt := range tests {
+		if got := ToFloat11(tt.in); got != tt.want {
+			t.Errorf("%s: ToFloat11(0x%04X) = 0x%03X, want 0x%03X", tt.name, uint16(tt.in), got, tt.want)
+		}
+	}
+
+	if got := ToFloat10(0x3C10); got != 0x1E0 {
+		t.Errorf("ToFloat10(0x3C10) = 0x%03X, want 0x1E0", got)
+	}
+	if got := ToFloat10(MaxValue); got != 0x3DF {
+		t.Errorf("ToFloat10(MaxValue) = 0x%03X, want 0x3DF", got)
+	}
+	if got := ToFloat10(QuietNaN); got != 0x3F0 {
+		t.Errorf("ToFloat10(QuietNaN) = 0x%03X, want 0x3F0", got)
+	}
+}
+
+// TestPackedFloatExhaustive checks every half-precision input against a
+// float64 round-to-nearest-even reference
+func TestPackedFloatExhaustive(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		mantLen uint
+		to      func(Float16) uint16
+		from    func(uint16) Float16
+	}{
+		{"float11", Float11MantissaLen, ToFloat11, FromFloat11},
+		{"float10", Float10MantissaLen, ToFloat10, FromFloat10},
+	} {
+		// Every packed value converts to Float16 and back unchanged
+		for v := uint16(0); v < 1<<(ExponentLen+tc.mantLen); v++ {
+			if got := tc.to(tc.from(v)); got != v && !tc.from(v).IsNaN() {
+				t.Errorf("%s: round trip of 0x%03X = 0x%03X", tc.name, v, got)
+			}
+		}
+
+		maxFinite := tc.from(1<<(ExponentLen+tc.mantLen) - 1<<tc.mantLen - 1).ToFloat64()
+		for i := 0; i < 0x7C00; i++ {
+			x := Float16(i).ToFloat64()
+			got := tc.from(tc.to(Float16(i))).ToFloat64()
+
+			// Round to nearest even at the packed precision
+			_, e := math.Frexp(x)
+			q := math.Ldexp(1, max(e, -ExponentBias+2)-1-int(tc.mantLen))
+			want := math.RoundToEven(x/q) * q
+			want = min(want, maxFinite)
+			if got != want {
+				t.Fatalf("%s: 0x%04X (%g) = %g, want %g", tc.name, i, x, got, want)
+			}
+		}
+	}
+}
+
+func TestR11G11B10F(t *testing.T) {
+	one := FromFloat32(1)
+	v := PackR11G11B10F(one, FromFloat32(2), FromFloat32(0.5))
+	if want := uint32(0x3C0) | 0x400<<11 | 0x1C0<<22; v != want {
+		t.Errorf("PackR11G11B10F(1, 2, 0.5) = 0x%08X, want 0x%08X", v, want)
+	}
+	r, g, b := UnpackR11G11B10F(v)
+	if r != one || g != FromFloat32(2) || b != FromFloat32(0.5) {
+		t.Errorf("UnpackR11G11B10F() = %v, %v, %v", r, g, b)
+	}
+
+	r, g, b = UnpackR11G11B10F(PackR11G11B10F(PositiveInfinity, FromFloat32(-3), QuietNaN))
+	if r != PositiveInfinity || g != PositiveZero || !b.IsNaN() {
+		t.Errorf("special values unpacked as %v, %v, %v", r, g, b)
+	}
+}
+
+func TestRGB9E5(t *testing.T) {
+	f := func(x float64) Float16 { return FromFloat64(x) }
+	tests := []struct {
+		name    string
+		r, g, b Float16
+		want    uint32
+	}{
+		{"zero", 0, 0, 0, 0},
+		{"one", f(1), f(1), f(1), 256 | 256<<9 | 256<<18 | 16<<27},
+		{"mixed", f(1), f(0.5), f(0), 256 | 128<<9 | 16<<27},
+		{"rounds up exponent", f(0.9995), 0, 0, 256 | 16<<27},
+		{"max", f(65408), f(65408), f(65408), 511 | 511<<9 | 511<<18 | 31<<27},
+		{"clamped", MaxValue, PositiveInfinity, 0, 511 | 511<<9 | 31<<27},
+		{"negative and nan", f(-1), QuietNaN, f(2), 256<<18 | 17<<27},
+		{"smallest", SmallestSubnormal, 0, 0, 1},
+		{"small component flushes", f(1), f(0.001), 0, 256 | 16<<27},
+	}
+	for _, tt := range tests {
+		if got := PackRGB9E5(tt.r, tt.g, tt.b); got != tt.want {
+			t.Errorf("%s: PackRGB9E5() = 0x%08X, want 0x%08X", tt.name, got, tt.want)
+		}
+	}
+
+	// Decoding is exact and packing a decoded value returns the same
+	// components
+	for _, v := range []uint32{0x00000001, 0x8040201, 0x87FFFFFF, 0xFFFFFFFF, 256 | 3<<9 | 511<<18 | 20<<27} {
+		r, g, b := UnpackRGB9E5(v)
+		exp := int(v >> 27)
+		for i, c := range []Float16{r, g, b} {
+			want := math.Ldexp(float64(v>>(9*i)&0x1FF), exp-ExponentBias-RGB9E5MantissaLen)
+			if c.ToFloat64() != want {
+				t.Errorf("UnpackRGB9E5(0x%08X) component %d = %v, want %v", v, i, c, want)
+			}
+		}
+		r2, g2, b2 := UnpackRGB9E5(PackRGB9E5(r, g, b))
+		if r2 != r || g2 != g || b2 != b {
+			t.Errorf("RGB9E5 round trip of 0x%08X changed the color", v)
+		}
+	}
+}
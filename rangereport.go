@@ -0,0 +1,168 @@
+package float16
+
+import "math"
+
+// Binades of the Float16 range: the subnormals fill binades 2^-24 to 2^-15
+// and the normal numbers 2^-14 to 2^15
+const (
+	MinBinade   = -24
+	MaxBinade   = 15
+	BinadeCount = MaxBinade - MinBinade + 1
+)
+
+// RangeReport describes how well a set of float32 or float64 values fits the
+// Float16 range. AnalyzeRange and AnalyzeRange64 produce it.
+type RangeReport struct {
+	Count int // values analyzed
+	NaN   int // NaN inputs
+	Inf   int // infinite inputs
+	Zero  int // zero inputs
+
+	// Binades[i] counts the inputs v with 2^(MinBinade+i) <= |v| <
+	// 2^(MinBinade+i+1); Below and Above count the non-zero finite inputs
+	// under 2^MinBinade and at or over 2^(MaxBinade+1)
+	Binades [BinadeCount]int
+	Below   int
+	Above   int
+
+	// Classes counts the converted values by FloatClass
+	Classes [ClassPositiveInfinity + 1]int
+
+	Overflow      int // finite inputs that convert to ±Inf
+	Subnormal     int // inputs that convert to a non-zero subnormal
+	FlushedToZero int // non-zero inputs that convert to ±0
+
+	// MaxRelError and MeanRelError are the largest and the mean of
+	// |Float16(v) - v| / |v| over the non-zero finite inputs that do not
+	// overflow; an input flushed to zero has relative error 1
+	MaxRelError  float64
+	MeanRelError float64
+
+	// ScaleExp is the power of two k for which the most inputs v·2^k
+	// convert to normal Float16 values, preferring the smallest |k| among
+	// equals; ScaledNormal is that number of inputs, and Normal the number
+	// without scaling
+	ScaleExp     int
+	ScaledNormal int
+	Normal       int
+}
+
+// OverflowFraction returns the fraction of all inputs that are finite but
+// convert to ±Inf
+func (r *RangeReport) OverflowFraction() float64 {
+	return fraction(r.Overflow, r.Count)
+}
+
+// UnderflowFraction returns the fraction of all inputs that are non-zero
+// but convert to ±0
+func (r *RangeReport) UnderflowFraction() float64 {
+	return fraction(r.FlushedToZero, r.Count)
+}
+
+// SubnormalFraction returns the fraction of all inputs that convert to
+// subnormals, keeping fewer than 11 significant bits
+func (r *RangeReport) SubnormalFraction() float64 {
+	return fraction(r.Subnormal, r.Count)
+}
+
+func fraction(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// AnalyzeRange reports how s would fare converted with FromFloat32
+func AnalyzeRange(s []float32) RangeReport {
+	return analyzeRange(s, FromFloat32)
+}
+
+// AnalyzeRange64 reports how s would fare converted with FromFloat64
+func AnalyzeRange64(s []float64) RangeReport {
+	return analyzeRange(s, FromFloat64)
+}
+
+// Exponents of non-zero finite float64 values after rounding to 11
+// significant bits span [expLow, expHigh]
+const (
+	expLow  = -1075
+	expHigh = 1024
+)
+
+func analyzeRange[T float32 | float64](s []T, convert func(T) Float16) RangeReport {
+	r := RangeReport{Count: len(s)}
+	// exps[e-expLow] counts inputs whose 11-bit rounding lies in binade e
+	var exps [expHigh - expLow + 1]int
+	var relSum float64
+	relCount := 0
+
+	for _, v := range s {
+		h := convert(v)
+		r.Classes[h.Class()]++
+		x := float64(v)
+		switch {
+		case math.IsNaN(x):
+			r.NaN++
+			continue
+		case math.IsInf(x, 0):
+			r.Inf++
+			continue
+		case x == 0:
+			r.Zero++
+			continue
+		}
+
+		m, e := math.Frexp(math.Abs(x)) // |x| = m·2^e, 0.5 <= m < 1
+		e--
+		switch {
+		case e < MinBinade:
+			r.Below++
+		case e > MaxBinade:
+			r.Above++
+		default:
+			r.Binades[e-MinBinade]++
+		}
+		if m >= 1-0x1p-12 {
+			e++ // rounds up into the next binade
+		}
+		exps[e-expLow]++
+
+		switch {
+		case h.IsInf(0):
+			r.Overflow++
+			continue
+		case h.IsZero():
+			r.FlushedToZero++
+		case h.IsSubnormal():
+			r.Subnormal++
+		}
+		rel := math.Abs(h.ToFloat64()-x) / math.Abs(x)
+		r.MaxRelError = max(r.MaxRelError, rel)
+		relSum += rel
+		relCount++
+	}
+	if relCount > 0 {
+		r.MeanRelError = relSum / float64(relCount)
+	}
+
+	// normal(k) counts the inputs with rounded binade in [-14-k, 15-k]
+	prefix := make([]int, len(exps)+1)
+	for i, n := range exps {
+		prefix[i+1] = prefix[i] + n
+	}
+	normal := func(k int) int {
+		lo := min(max(-14-k-expLow, 0), len(exps))
+		hi := min(max(MaxBinade-k-expLow+1, 0), len(exps))
+		return prefix[hi] - prefix[lo]
+	}
+	r.Normal = normal(0)
+	r.ScaleExp, r.ScaledNormal = 0, r.Normal
+	for d := 1; d <= expHigh-expLow+MaxBinade+14; d++ {
+		for _, k := range [2]int{-d, d} {
+			if n := normal(k); n > r.ScaledNormal {
+				r.ScaleExp, r.ScaledNormal = k, n
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,133 @@
+package float16
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAnalyzeRange(t *testing.T) {
+	s := []float32{
+		0, float32(math.Copysign(0, -1)), 1, -1.5, 3, // zeros and normal values
+		65504, 65519, // largest finite, and the last value that rounds to it
+		65520, -1e6, // overflow
+		1e-5, 0x1p-24, // subnormal
+		0x1p-26, -1e-30, // flushed to zero
+		float32(math.Inf(1)), float32(math.NaN()),
+	}
+	r := AnalyzeRange(s)
+
+	ints := []struct {
+		name      string
+		got, want int
+	}{
+		{"Count", r.Count, 15},
+		{"NaN", r.NaN, 1},
+		{"Inf", r.Inf, 1},
+		{"Zero", r.Zero, 2},
+		{"Overflow", r.Overflow, 2},
+		{"Subnormal", r.Subnormal, 2},
+		{"FlushedToZero", r.FlushedToZero, 2},
+		{"Below", r.Below, 2},
+		{"Above", r.Above, 1},
+		{"Binades[1]", r.Binades[0-MinBinade], 2},             // 1 and -1.5
+		{"Binades[65504]", r.Binades[MaxBinade-MinBinade], 3}, // 65504, 65519, 65520
+		{"Binades[2^-24]", r.Binades[0], 1},
+		{"Normal", r.Normal, 5},
+		{"ClassPositiveInfinity", r.Classes[ClassPositiveInfinity], 2},
+		{"ClassNegativeInfinity", r.Classes[ClassNegativeInfinity], 1},
+		{"ClassQuietNaN", r.Classes[ClassQuietNaN], 1},
+		{"ClassPositiveZero", r.Classes[ClassPositiveZero], 2},
+		{"ClassNegativeZero", r.Classes[ClassNegativeZero], 2},
+		{"ClassPositiveSubnormal", r.Classes[ClassPositiveSubnormal], 2},
+		{"ClassPositiveNormal", r.Classes[ClassPositiveNormal], 4},
+	}
+	for _, c := range ints {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+
+	if got, want := r.OverflowFraction(), 2.0/15; got != want {
+		t.Errorf("OverflowFraction() = %v, want %v", got, want)
+	}
+	if got, want := r.UnderflowFraction(), 2.0/15; got != want {
+		t.Errorf("UnderflowFraction() = %v, want %v", got, want)
+	}
+	if got, want := r.SubnormalFraction(), 2.0/15; got != want {
+		t.Errorf("SubnormalFraction() = %v, want %v", got, want)
+	}
+	if r.MaxRelError != 1 {
+		t.Errorf("MaxRelError = %v, want 1 for the flushed values", r.MaxRelError)
+	}
+}
+
+func TestAnalyzeRangeRelError(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := make([]float64, 10000)
+	for i := range s {
+		s[i] = rng.Float64()*2 + 0.5
+	}
+	r := AnalyzeRange64(s)
+	// Round to nearest: at most 2^-11 relative, about a quarter of it on average
+	if r.MaxRelError > 0x1p-11 || r.MaxRelError < 0x1p-12 {
+		t.Errorf("MaxRelError = %g, want in [2^-12, 2^-11]", r.MaxRelError)
+	}
+	if r.MeanRelError < 0x1p-14 || r.MeanRelError > 0x1p-12 {
+		t.Errorf("MeanRelError = %g, want about 2^-13", r.MeanRelError)
+	}
+	if r.ScaleExp != 0 || r.ScaledNormal != len(s) || r.Normal != len(s) {
+		t.Errorf("ScaleExp, ScaledNormal, Normal = %d, %d, %d, want 0, %d, %d", r.ScaleExp, r.ScaledNormal, r.Normal, len(s), len(s))
+	}
+}
+
+func TestAnalyzeRangeScale(t *testing.T) {
+	tests := []struct {
+		name      string
+		lo, hi    float64 // log2 range of the magnitudes
+		wantScale func(int) bool
+	}{
+		// Gradients in [2^-30, 2^-20) need a scale of at least 2^16
+		{"small", -30, -20, func(k int) bool { return k == 16 }},
+		// Accumulators in [2^18, 2^22) need a scale of at most 2^-6
+		{"large", 18, 22, func(k int) bool { return k == -6 }},
+		// 40 binades cannot all fit; the window of 30 normal binades stays
+		// near the middle
+		{"wide", -20, 20, func(k int) bool { return k >= -5 && k <= 5 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(2))
+			s := make([]float32, 4000)
+			for i := range s {
+				s[i] = float32(math.Exp2(tt.lo + rng.Float64()*(tt.hi-tt.lo)))
+			}
+			r := AnalyzeRange(s)
+			if !tt.wantScale(r.ScaleExp) {
+				t.Errorf("ScaleExp = %d", r.ScaleExp)
+			}
+
+			// The claimed coverage is the real coverage after scaling
+			normal := 0
+			for _, v := range s {
+				if FromFloat64(math.Ldexp(float64(v), r.ScaleExp)).IsNormal() {
+					normal++
+				}
+			}
+			if normal != r.ScaledNormal {
+				t.Errorf("ScaledNormal = %d, but %d values convert to normals", r.ScaledNormal, normal)
+			}
+			if r.ScaledNormal < r.Normal {
+				t.Errorf("ScaledNormal = %d < Normal = %d", r.ScaledNormal, r.Normal)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRangeEmpty(t *testing.T) {
+	r := AnalyzeRange(nil)
+	if r.Count != 0 || r.OverflowFraction() != 0 || r.ScaleExp != 0 || r.MeanRelError != 0 {
+		t.Errorf("AnalyzeRange(nil) = %+v", r)
+	}
+}
@@ -0,0 +1,49 @@
+package safetensors
+
+import (
+	"math"
+
+	"github.com/zerfoo/float16"
+)
+
+// bf16ToFloat32 widens a bfloat16 bit pattern; bfloat16 is the upper half
+// of a float32, so the conversion is exact
+func bf16ToFloat32(b uint16) float32 {
+	return math.Float32frombits(uint32(b) << 16)
+}
+
+// float32ToBF16 rounds a float32 to bfloat16 using the given rounding mode.
+// NaNs stay NaN with the quiet bit set; overflow follows the same IEEE 754
+// rules as Float16 conversion.
+func float32ToBF16(f float32, rounding float16.RoundingMode) uint16 {
+	bits := math.Float32bits(f)
+	if f != f {
+		return uint16(bits>>16) | 0x0040
+	}
+
+	sign := bits & 0x80000000
+	kept := bits >> 16
+	rem := bits & 0xFFFF
+	if rem == 0 {
+		return uint16(kept)
+	}
+
+	var up bool
+	switch rounding {
+	case float16.RoundNearestEven:
+		up = rem > 0x8000 || (rem == 0x8000 && kept&1 == 1)
+	case float16.RoundNearestAway:
+		up = rem >= 0x8000
+	case float16.RoundTowardPositive:
+		up = sign == 0
+	case float16.RoundTowardNegative:
+		up = sign != 0
+	}
+	if up {
+		kept++
+	}
+
+	// Rounding a finite value up into the exponent field's all-ones pattern
+	// produces infinity, which is the correct overflow result for these modes
+	return uint16(kept)
+}
@@ -0,0 +1,23 @@
+package safetensors
+
+import "github.com/zerfoo/float16/internal/mmap"
+
+// Open maps the named file into memory and parses it.
+// On Unix systems the file is memory-mapped read-only, so tensor data returned
+// from the File refers directly to the mapping and F16 tensors can be used
+// without reading the file into memory. That data must not be modified, and
+// the mapping is released by Close; tensors must not be used afterwards.
+func Open(name string) (*File, error) {
+	data, release, err := mmap.Map(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := Parse(data)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	f.close = release
+	return f, nil
+}
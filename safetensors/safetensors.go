@@ -0,0 +1,289 @@
+// Package safetensors reads and writes the safetensors tensor file format
+// with half-precision support.
+//
+// A safetensors file is an 8-byte little-endian header length N, followed by
+// N bytes of JSON describing each tensor's dtype, shape and byte range, and
+// then the raw little-endian tensor data.
+//
+// F16 tensors are exposed as []float16.Float16 views of the underlying bytes
+// without copying whenever the host is little-endian and the data is
+// suitably aligned. F32, F64 and BF16 tensors are converted on demand.
+//
+// See: https://github.com/huggingface/safetensors
+package safetensors
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/zerfoo/float16"
+)
+
+// DType is a safetensors element type name
+type DType string
+
+// Element types understood by this package
+const (
+	F16  DType = "F16"
+	BF16 DType = "BF16"
+	F32  DType = "F32"
+	F64  DType = "F64"
+)
+
+// maxHeaderSize guards against corrupt length prefixes, as the reference
+// implementation does
+const maxHeaderSize = 100 << 20
+
+// metadataKey is the reserved header entry holding free-form string metadata
+const metadataKey = "__metadata__"
+
+// Errors returned when decoding safetensors data
+var (
+	ErrHeader           = errors.New("safetensors: malformed header")
+	ErrUnsupportedDType = errors.New("safetensors: unsupported dtype")
+	ErrNotFound         = errors.New("safetensors: tensor not found")
+)
+
+// Size returns the size in bytes of one element, or 0 for unknown dtypes
+func (d DType) Size() int {
+	switch d {
+	case F16, BF16:
+		return 2
+	case F32:
+		return 4
+	case F64:
+		return 8
+	case "I8", "U8", "BOOL", "F8_E4M3", "F8_E5M2":
+		return 1
+	case "I16", "U16":
+		return 2
+	case "I32", "U32":
+		return 4
+	case "I64", "U64":
+		return 8
+	}
+	return 0
+}
+
+// tensorInfo is one tensor entry in the JSON header
+type tensorInfo struct {
+	DType       DType    `json:"dtype"`
+	Shape       []int    `json:"shape"`
+	DataOffsets [2]int64 `json:"data_offsets"`
+}
+
+// File is a parsed safetensors file backed by a byte slice
+type File struct {
+	// Metadata holds the optional __metadata__ string map
+	Metadata map[string]string
+
+	infos map[string]tensorInfo
+	names []string
+	data  []byte
+	close func() error
+}
+
+// Tensor is a single named tensor whose Data aliases the file contents.
+// For a File returned from Open, Data is read-only; see Float16s.
+type Tensor struct {
+	Name  string
+	DType DType
+	Shape []int
+	Data  []byte
+}
+
+// Parse decodes a complete safetensors file held in b.
+// The returned File and its tensors alias b, which must not be modified
+// while they are in use.
+func Parse(b []byte) (*File, error) {
+	if len(b) < 8 {
+		return nil, fmt.Errorf("%w: file too short", ErrHeader)
+	}
+	n := binary.LittleEndian.Uint64(b)
+	if n > maxHeaderSize || n > uint64(len(b)-8) {
+		return nil, fmt.Errorf("%w: header length %d out of range", ErrHeader, n)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b[8:8+n], &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHeader, err)
+	}
+
+	f := &File{
+		infos: make(map[string]tensorInfo, len(raw)),
+		data:  b[8+n:],
+	}
+	for name, msg := range raw {
+		if name == metadataKey {
+			if err := json.Unmarshal(msg, &f.Metadata); err != nil {
+				return nil, fmt.Errorf("%w: metadata: %v", ErrHeader, err)
+			}
+			continue
+		}
+
+		var info tensorInfo
+		if err := json.Unmarshal(msg, &info); err != nil {
+			return nil, fmt.Errorf("%w: tensor %q: %v", ErrHeader, name, err)
+		}
+		if err := f.validate(name, info); err != nil {
+			return nil, err
+		}
+		f.infos[name] = info
+		f.names = append(f.names, name)
+	}
+	sort.Strings(f.names)
+	return f, nil
+}
+
+// validate checks that a tensor's byte range lies within the data section
+// and matches its dtype and shape
+func (f *File) validate(name string, info tensorInfo) error {
+	begin, end := info.DataOffsets[0], info.DataOffsets[1]
+	if begin < 0 || end < begin || end > int64(len(f.data)) {
+		return fmt.Errorf("%w: tensor %q offsets [%d, %d] out of range", ErrHeader, name, begin, end)
+	}
+
+	size := info.DType.Size()
+	if size == 0 {
+		// Unknown dtypes are still listed; they fail on conversion
+		return nil
+	}
+	n := int64(1)
+	for _, d := range info.Shape {
+		if d < 0 || (d != 0 && n > math.MaxInt64/int64(size)/int64(d)) {
+			return fmt.Errorf("%w: tensor %q has invalid shape %v", ErrHeader, name, info.Shape)
+		}
+		n *= int64(d)
+	}
+	if n*int64(size) != end-begin {
+		return fmt.Errorf("%w: tensor %q has %d bytes, shape %v needs %d", ErrHeader, name, end-begin, info.Shape, n*int64(size))
+	}
+	return nil
+}
+
+// Names returns the tensor names in sorted order
+func (f *File) Names() []string {
+	return append([]string(nil), f.names...)
+}
+
+// Tensor returns the named tensor
+func (f *File) Tensor(name string) (*Tensor, error) {
+	info, ok := f.infos[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	return &Tensor{
+		Name:  name,
+		DType: info.DType,
+		Shape: append([]int(nil), info.Shape...),
+		Data:  f.data[info.DataOffsets[0]:info.DataOffsets[1]:info.DataOffsets[1]],
+	}, nil
+}
+
+// Close releases resources held by a File returned from Open.
+// It is a no-op for files returned from Parse.
+func (f *File) Close() error {
+	if f.close == nil {
+		return nil
+	}
+	err := f.close()
+	f.close = nil
+	return err
+}
+
+// Len returns the number of elements in the tensor
+func (t *Tensor) Len() int {
+	n := 1
+	for _, d := range t.Shape {
+		n *= d
+	}
+	return n
+}
+
+// Float16s returns the data of an F16 tensor as []float16.Float16.
+// On little-endian hosts with 2-byte aligned data the result is a view of the
+// file contents and no copy is made; otherwise the values are decoded into a
+// new slice. Other dtypes return ErrUnsupportedDType; use ToFloat16s instead.
+//
+// When the File came from Open, the view aliases a read-only memory mapping
+// and writing to it crashes the program; copy it, for example with
+// slices.Clone or ToFloat16s, before mutating. Like Data, the view must not
+// be used after Close.
+func (t *Tensor) Float16s() ([]float16.Float16, error) {
+	if t.DType != F16 {
+		return nil, fmt.Errorf("%w: %s is not F16", ErrUnsupportedDType, t.DType)
+	}
+	if hostLittleEndian() {
+		if view, err := float16.BytesAsFloat16s(t.Data); err == nil {
+			return view, nil
+		}
+	}
+	out := make([]float16.Float16, len(t.Data)/2)
+	float16.DecodeFloat16s(out, t.Data, binary.LittleEndian)
+	return out, nil
+}
+
+// ToFloat16s converts the tensor to a new []float16.Float16, rounding F32,
+// F64 and BF16 values with the given rounding mode. F16 data is copied.
+func (t *Tensor) ToFloat16s(rounding float16.RoundingMode) ([]float16.Float16, error) {
+	n := len(t.Data) / max(t.DType.Size(), 1)
+	out := make([]float16.Float16, n)
+	switch t.DType {
+	case F16:
+		float16.DecodeFloat16s(out, t.Data, binary.LittleEndian)
+	case BF16:
+		for i := range out {
+			out[i], _ = float16.FromFloat32WithMode(bf16ToFloat32(binary.LittleEndian.Uint16(t.Data[2*i:])), float16.ModeIEEE, rounding)
+		}
+	case F32:
+		for i := range out {
+			out[i], _ = float16.FromFloat32WithMode(math.Float32frombits(binary.LittleEndian.Uint32(t.Data[4*i:])), float16.ModeIEEE, rounding)
+		}
+	case F64:
+		for i := range out {
+			out[i], _ = float16.FromFloat64WithMode(math.Float64frombits(binary.LittleEndian.Uint64(t.Data[8*i:])), float16.ModeIEEE, rounding)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDType, t.DType)
+	}
+	return out, nil
+}
+
+// ToFloat32s converts the tensor to a new []float32. F16, BF16 and F32
+// values convert exactly; F64 values are rounded by the Go conversion.
+func (t *Tensor) ToFloat32s() ([]float32, error) {
+	n := len(t.Data) / max(t.DType.Size(), 1)
+	out := make([]float32, n)
+	switch t.DType {
+	case F16:
+		for i := range out {
+			out[i] = float16.Float16(binary.LittleEndian.Uint16(t.Data[2*i:])).ToFloat32()
+		}
+	case BF16:
+		for i := range out {
+			out[i] = bf16ToFloat32(binary.LittleEndian.Uint16(t.Data[2*i:]))
+		}
+	case F32:
+		for i := range out {
+			out[i] = math.Float32frombits(binary.LittleEndian.Uint32(t.Data[4*i:]))
+		}
+	case F64:
+		for i := range out {
+			out[i] = float32(math.Float64frombits(binary.LittleEndian.Uint64(t.Data[8*i:])))
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDType, t.DType)
+	}
+	return out, nil
+}
+
+// hostLittleEndian reports whether the host stores integers little-endian
+func hostLittleEndian() bool {
+	var probe [2]byte
+	binary.NativeEndian.PutUint16(probe[:], 1)
+	return probe[0] == 1
+}
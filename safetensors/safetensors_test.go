@@ -0,0 +1,234 @@
+package safetensors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/zerfoo/float16"
+)
+
+// rawFile builds a safetensors file from a JSON header and data section
+func rawFile(header string, data []byte) []byte {
+	b := binary.LittleEndian.AppendUint64(nil, uint64(len(header)))
+	b = append(b, header...)
+	return append(b, data...)
+}
+
+func TestParse(t *testing.T) {
+	data := float16.AppendFloat16s(nil, binary.LittleEndian, 0x3C00, 0x4000, 0x4200, 0x4400)
+	data = binary.LittleEndian.AppendUint32(data, math.Float32bits(0.5))
+	data = binary.LittleEndian.AppendUint16(data, 0xC040) // BF16 -3.0
+	header := `{"__metadata__":{"format":"pt"},` +
+		`"w":{"dtype":"F16","shape":[2,2],"data_offsets":[0,8]},` +
+		`"s":{"dtype":"F32","shape":[],"data_offsets":[8,12]},` +
+		`"b":{"dtype":"BF16","shape":[1],"data_offsets":[12,14]}}`
+
+	f, err := Parse(rawFile(header, data))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if f.Metadata["format"] != "pt" {
+		t.Errorf("Metadata = %v, want format=pt", f.Metadata)
+	}
+	if names := f.Names(); len(names) != 3 || names[0] != "b" || names[2] != "w" {
+		t.Errorf("Names() = %v, want [b s w]", names)
+	}
+
+	w, err := f.Tensor("w")
+	if err != nil {
+		t.Fatalf("Tensor(w) unexpected error: %v", err)
+	}
+	vals, err := w.Float16s()
+	if err != nil {
+		t.Fatalf("Float16s() unexpected error: %v", err)
+	}
+	want := []float16.Float16{0x3C00, 0x4000, 0x4200, 0x4400}
+	for i := range want {
+		if vals[i] != want[i] {
+			t.Errorf("Float16s()[%d] = %v, want %v", i, vals[i], want[i])
+		}
+	}
+
+	s, _ := f.Tensor("s")
+	if _, err := s.Float16s(); !errors.Is(err, ErrUnsupportedDType) {
+		t.Errorf("Float16s() on F32 error = %v, want ErrUnsupportedDType", err)
+	}
+	conv, err := s.ToFloat16s(float16.RoundNearestEven)
+	if err != nil || len(conv) != 1 || conv[0] != 0x3800 {
+		t.Errorf("ToFloat16s() on F32 = %v, %v; want [0.5]", conv, err)
+	}
+
+	b, _ := f.Tensor("b")
+	conv, err = b.ToFloat16s(float16.RoundNearestEven)
+	if err != nil || len(conv) != 1 || conv[0] != 0xC200 {
+		t.Errorf("ToFloat16s() on BF16 = %v, %v; want [-3]", conv, err)
+	}
+	f32, err := b.ToFloat32s()
+	if err != nil || f32[0] != -3 {
+		t.Errorf("ToFloat32s() on BF16 = %v, %v; want [-3]", f32, err)
+	}
+
+	if _, err := f.Tensor("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Tensor(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"short", []byte{1, 2, 3}},
+		{"header too long", rawFile("{}", nil)[:9]},
+		{"bad json", rawFile("{not json", nil)},
+		{"offsets out of range", rawFile(`{"x":{"dtype":"F16","shape":[2],"data_offsets":[0,4]}}`, []byte{0, 0})},
+		{"size mismatch", rawFile(`{"x":{"dtype":"F16","shape":[3],"data_offsets":[0,4]}}`, make([]byte, 4))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.data); !errors.Is(err, ErrHeader) {
+				t.Errorf("Parse() error = %v, want ErrHeader", err)
+			}
+		})
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	src := []float32{1, -2, 0.1, 70000}
+
+	tests := []struct {
+		name  string
+		opts  WriteOptions
+		dtype DType
+		want  []float16.Float16
+	}{
+		{"F16 nearest", WriteOptions{}, F16, []float16.Float16{0x3C00, 0xC000, 0x2E66, float16.PositiveInfinity}},
+		{"F16 toward zero", WriteOptions{DType: F16, Rounding: float16.RoundTowardZero}, F16, []float16.Float16{0x3C00, 0xC000, 0x2E66, float16.MaxValue}},
+		{"F16 toward positive", WriteOptions{DType: F16, Rounding: float16.RoundTowardPositive}, F16, []float16.Float16{0x3C00, 0xC000, 0x2E67, float16.PositiveInfinity}},
+		{"F32", WriteOptions{DType: F32}, F32, []float16.Float16{0x3C00, 0xC000, 0x2E66, float16.PositiveInfinity}},
+		{"BF16", WriteOptions{DType: BF16}, BF16, []float16.Float16{0x3C00, 0xC000, 0x2E66, float16.PositiveInfinity}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := NewWriter(tt.opts)
+			if err != nil {
+				t.Fatalf("NewWriter() unexpected error: %v", err)
+			}
+			w.Metadata = map[string]string{"source": "test"}
+			if err := w.AddFloat32("x", []int{2, 2}, src); err != nil {
+				t.Fatalf("AddFloat32() unexpected error: %v", err)
+			}
+			if err := w.AddFloat16("h", []int{1}, []float16.Float16{0x3555}); err != nil {
+				t.Fatalf("AddFloat16() unexpected error: %v", err)
+			}
+
+			var buf bytes.Buffer
+			n, err := w.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("WriteTo() unexpected error: %v", err)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("WriteTo() = %d, wrote %d bytes", n, buf.Len())
+			}
+			if hlen := binary.LittleEndian.Uint64(buf.Bytes()); hlen%8 != 0 {
+				t.Errorf("header length %d is not a multiple of 8", hlen)
+			}
+
+			f, err := Parse(buf.Bytes())
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if f.Metadata["source"] != "test" {
+				t.Errorf("Metadata = %v, want source=test", f.Metadata)
+			}
+			x, err := f.Tensor("x")
+			if err != nil {
+				t.Fatalf("Tensor(x) unexpected error: %v", err)
+			}
+			if x.DType != tt.dtype {
+				t.Errorf("DType = %s, want %s", x.DType, tt.dtype)
+			}
+			got, err := x.ToFloat16s(tt.opts.Rounding)
+			if err != nil {
+				t.Fatalf("ToFloat16s() unexpected error: %v", err)
+			}
+			for i := range tt.want {
+				// BF16 keeps only 8 significant bits, so 0.1 differs
+				if tt.dtype == BF16 && i == 2 {
+					continue
+				}
+				if got[i] != tt.want[i] {
+					t.Errorf("x[%d] = 0x%04X, want 0x%04X", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	if _, err := NewWriter(WriteOptions{DType: "I8"}); !errors.Is(err, ErrUnsupportedDType) {
+		t.Errorf("NewWriter(I8) error = %v, want ErrUnsupportedDType", err)
+	}
+	w, _ := NewWriter(WriteOptions{})
+	if err := w.AddFloat32("x", []int{3}, []float32{1, 2}); err == nil {
+		t.Error("AddFloat32() with mismatched shape should fail")
+	}
+	if err := w.AddFloat32(metadataKey, []int{1}, []float32{1}); err == nil {
+		t.Error("AddFloat32() with reserved name should fail")
+	}
+}
+
+func TestFloat32ToBF16(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    float32
+		rounding float16.RoundingMode
+		want     uint16
+	}{
+		{"exact", 1, float16.RoundNearestEven, 0x3F80},
+		{"tie to even", math.Float32frombits(0x3F808000), float16.RoundNearestEven, 0x3F80},
+		{"tie away", math.Float32frombits(0x3F808000), float16.RoundNearestAway, 0x3F81},
+		{"toward zero", math.Float32frombits(0x3F80FFFF), float16.RoundTowardZero, 0x3F80},
+		{"toward negative", -math.Float32frombits(0x3F800001), float16.RoundTowardNegative, 0xBF81},
+		{"overflow", math.MaxFloat32, float16.RoundNearestEven, 0x7F80},
+		{"NaN", float32(math.NaN()), float16.RoundNearestEven, 0x7FC0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := float32ToBF16(tt.input, tt.rounding); got != tt.want {
+				t.Errorf("float32ToBF16() = 0x%04X, want 0x%04X", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.safetensors")
+	w, _ := NewWriter(WriteOptions{})
+	w.AddFloat16("a", []int{3}, []float16.Float16{0x3C00, 0x4000, 0x4200})
+	if err := w.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+
+	f, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	a, err := f.Tensor("a")
+	if err != nil {
+		t.Fatalf("Tensor(a) unexpected error: %v", err)
+	}
+	vals, err := a.Float16s()
+	if err != nil || len(vals) != 3 || vals[2] != 0x4200 {
+		t.Errorf("Float16s() = %v, %v; want [1 2 3]", vals, err)
+	}
+}
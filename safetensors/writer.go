@@ -0,0 +1,186 @@
+package safetensors
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/zerfoo/float16"
+)
+
+// WriteOptions controls how a Writer encodes tensors
+type WriteOptions struct {
+	// DType is the output element type: F16 (the default when empty), BF16 or F32
+	DType DType
+	// Rounding is used whenever a value must be narrowed to the output type
+	Rounding float16.RoundingMode
+}
+
+// Writer collects tensors and encodes them as a safetensors file
+type Writer struct {
+	// Metadata is written to the __metadata__ header entry when non-empty
+	Metadata map[string]string
+
+	opts    WriteOptions
+	entries map[string]*entry
+}
+
+// entry is a tensor queued for writing; exactly one of f32 and f16 is set
+type entry struct {
+	shape []int
+	f32   []float32
+	f16   []float16.Float16
+}
+
+// NewWriter returns a Writer that encodes tensors according to opts
+func NewWriter(opts WriteOptions) (*Writer, error) {
+	if opts.DType == "" {
+		opts.DType = F16
+	}
+	switch opts.DType {
+	case F16, BF16, F32:
+	default:
+		return nil, fmt.Errorf("%w: cannot write %s", ErrUnsupportedDType, opts.DType)
+	}
+	return &Writer{opts: opts, entries: make(map[string]*entry)}, nil
+}
+
+// AddFloat32 queues a float32 tensor, replacing any tensor with the same name
+func (w *Writer) AddFloat32(name string, shape []int, data []float32) error {
+	if err := w.check(name, shape, len(data)); err != nil {
+		return err
+	}
+	w.entries[name] = &entry{shape: shape, f32: data}
+	return nil
+}
+
+// AddFloat16 queues a half-precision tensor, replacing any tensor with the same name
+func (w *Writer) AddFloat16(name string, shape []int, data []float16.Float16) error {
+	if err := w.check(name, shape, len(data)); err != nil {
+		return err
+	}
+	w.entries[name] = &entry{shape: shape, f16: data}
+	return nil
+}
+
+func (w *Writer) check(name string, shape []int, n int) error {
+	if name == metadataKey || name == "" {
+		return fmt.Errorf("safetensors: invalid tensor name %q", name)
+	}
+	size := 1
+	for _, d := range shape {
+		if d < 0 {
+			return fmt.Errorf("safetensors: tensor %q has invalid shape %v", name, shape)
+		}
+		size *= d
+	}
+	if size != n {
+		return fmt.Errorf("safetensors: tensor %q shape %v does not match %d elements", name, shape, n)
+	}
+	return nil
+}
+
+// WriteTo encodes the queued tensors to out in name order
+func (w *Writer) WriteTo(out io.Writer) (int64, error) {
+	names := make([]string, 0, len(w.entries))
+	for name := range w.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	header := make(map[string]interface{}, len(names)+1)
+	if len(w.Metadata) > 0 {
+		header[metadataKey] = w.Metadata
+	}
+	size := int64(w.opts.DType.Size())
+	var offset int64
+	for _, name := range names {
+		e := w.entries[name]
+		n := int64(len(e.f32) + len(e.f16))
+		shape := e.shape
+		if shape == nil {
+			shape = []int{}
+		}
+		header[name] = tensorInfo{
+			DType:       w.opts.DType,
+			Shape:       shape,
+			DataOffsets: [2]int64{offset, offset + n*size},
+		}
+		offset += n * size
+	}
+
+	js, err := json.Marshal(header)
+	if err != nil {
+		return 0, err
+	}
+	// Pad with spaces so the data section starts 8-byte aligned
+	for len(js)%8 != 0 {
+		js = append(js, ' ')
+	}
+
+	bw := bufio.NewWriter(out)
+	var prefix [8]byte
+	binary.LittleEndian.PutUint64(prefix[:], uint64(len(js)))
+	bw.Write(prefix[:])
+	bw.Write(js)
+
+	buf := make([]byte, 0, 8)
+	for _, name := range names {
+		e := w.entries[name]
+		for i := 0; i < len(e.f32)+len(e.f16); i++ {
+			buf = w.encode(buf[:0], e, i)
+			if _, err := bw.Write(buf); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	return int64(8+len(js)) + offset, nil
+}
+
+// encode appends element i of e in the output dtype
+func (w *Writer) encode(dst []byte, e *entry, i int) []byte {
+	le := binary.LittleEndian
+	if e.f16 != nil {
+		v := e.f16[i]
+		switch w.opts.DType {
+		case F16:
+			return le.AppendUint16(dst, v.Bits())
+		case BF16:
+			return le.AppendUint16(dst, float32ToBF16(v.ToFloat32(), w.opts.Rounding))
+		default:
+			return le.AppendUint32(dst, math.Float32bits(v.ToFloat32()))
+		}
+	}
+
+	v := e.f32[i]
+	switch w.opts.DType {
+	case F16:
+		h, _ := float16.FromFloat32WithMode(v, float16.ModeIEEE, w.opts.Rounding)
+		return le.AppendUint16(dst, h.Bits())
+	case BF16:
+		return le.AppendUint16(dst, float32ToBF16(v, w.opts.Rounding))
+	default:
+		return le.AppendUint32(dst, math.Float32bits(v))
+	}
+}
+
+// WriteFile encodes the queued tensors to the named file
+func (w *Writer) WriteFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,254 @@
+package float16
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// The selection functions below order values numerically without
+// converting them: orderKey maps each non-NaN Float16 to a uint16 that
+// sorts the same way, with -0 and +0 equal.
+//
+// NaN policy: NaNs are ignored. They are never returned by ArgMax, ArgMin
+// or TopK and are left out of the sample that Quantile and Median see, as
+// with NumPy's nanargmax and nanquantile. When a slice holds no values
+// other than NaNs, the index functions return -1 and the quantiles NaN.
+
+// orderKey returns a key that orders non-NaN values numerically: the
+// totalKey of the value, with -0 taken as +0
+func orderKey(f Float16) uint16 {
+	if f == NegativeZero {
+		f = PositiveZero
+	}
+	return totalKey(f)
+}
+
+// fromOrderKey inverts totalKey, and so orderKey, for which a zero key
+// comes back as +0
+func fromOrderKey(k uint16) Float16 {
+	if k&SignMask != 0 {
+		return Float16(k &^ SignMask)
+	}
+	return Float16(^k)
+}
+
+// ArgMax returns the index of the largest non-NaN value in s, the first one
+// if it occurs more than once, or -1 if there is none
+func ArgMax(s []Float16) int {
+	best, bestKey := -1, uint16(0)
+	for i, v := range s {
+		if v.IsNaN() {
+			continue
+		}
+		if k := orderKey(v); best < 0 || k > bestKey {
+			best, bestKey = i, k
+		}
+	}
+	return best
+}
+
+// ArgMin returns the index of the smallest non-NaN value in s, the first one
+// if it occurs more than once, or -1 if there is none
+func ArgMin(s []Float16) int {
+	best, bestKey := -1, uint16(0)
+	for i, v := range s {
+		if v.IsNaN() {
+			continue
+		}
+		if k := orderKey(v); best < 0 || k < bestKey {
+			best, bestKey = i, k
+		}
+	}
+	return best
+}
+
+// TopK returns the indices and values of the k largest non-NaN values in s,
+// largest first; equal values keep their order in s. Fewer than k are
+// returned when s has fewer non-NaN values. It runs in O(n log k) time with
+// a k-element heap and leaves s unchanged.
+func TopK(s []Float16, k int) (indices []int, values []Float16) {
+	if k <= 0 {
+		return nil, nil
+	}
+	h := topKHeap{keys: make([]uint16, 0, min(k, len(s))), idx: make([]int, 0, min(k, len(s)))}
+	for i, v := range s {
+		if v.IsNaN() {
+			continue
+		}
+		key := orderKey(v)
+		if len(h.idx) < k {
+			h.push(key, i)
+		} else if key > h.keys[0] {
+			// A later equal value never displaces an earlier one
+			h.replaceTop(key, i)
+		}
+	}
+
+	// Popping the heap yields the smallest first
+	n := len(h.idx)
+	indices, values = make([]int, n), make([]Float16, n)
+	for j := n - 1; j >= 0; j-- {
+		indices[j], values[j] = h.idx[0], s[h.idx[0]]
+		h.pop()
+	}
+	return indices, values
+}
+
+// topKHeap is a min-heap of candidates ordered by key, then by index
+// descending, so that the root is the first candidate to drop
+type topKHeap struct {
+	keys []uint16
+	idx  []int
+}
+
+func (h *topKHeap) less(i, j int) bool {
+	if h.keys[i] != h.keys[j] {
+		return h.keys[i] < h.keys[j]
+	}
+	return h.idx[i] > h.idx[j]
+}
+
+func (h *topKHeap) swap(i, j int) {
+	h.keys[i], h.keys[j] = h.keys[j], h.keys[i]
+	h.idx[i], h.idx[j] = h.idx[j], h.idx[i]
+}
+
+func (h *topKHeap) push(key uint16, i int) {
+	h.keys, h.idx = append(h.keys, key), append(h.idx, i)
+	for j := len(h.idx) - 1; j > 0; {
+		parent := (j - 1) / 2
+		if !h.less(j, parent) {
+			break
+		}
+		h.swap(j, parent)
+		j = parent
+	}
+}
+
+func (h *topKHeap) replaceTop(key uint16, i int) {
+	h.keys[0], h.idx[0] = key, i
+	h.down(0)
+}
+
+func (h *topKHeap) pop() {
+	last := len(h.idx) - 1
+	h.swap(0, last)
+	h.keys, h.idx = h.keys[:last], h.idx[:last]
+	h.down(0)
+}
+
+func (h *topKHeap) down(j int) {
+	for {
+		c := 2*j + 1
+		if c >= len(h.idx) {
+			return
+		}
+		if c+1 < len(h.idx) && h.less(c+1, c) {
+			c++
+		}
+		if !h.less(c, j) {
+			return
+		}
+		h.swap(j, c)
+		j = c
+	}
+}
+
+// Quantile returns the q-quantile of the non-NaN values in s, interpolating
+// linearly between the two nearest order statistics (NumPy's default
+// "linear" method) and rounding once to Float16. q must be in [0, 1];
+// otherwise, or when s has no non-NaN values, the result is NaN. Quantile
+// copies the values and selects the order statistics with introselect in
+// O(n) expected time, leaving s unchanged.
+func Quantile(s []Float16, q float64) Float16 {
+	if !(q >= 0 && q <= 1) {
+		return QuietNaN
+	}
+	keys := make([]uint16, 0, len(s))
+	for _, v := range s {
+		if !v.IsNaN() {
+			keys = append(keys, orderKey(v))
+		}
+	}
+	if len(keys) == 0 {
+		return QuietNaN
+	}
+
+	h := q * float64(len(keys)-1)
+	lo := int(h)
+	introselect(keys, lo)
+	a := fromOrderKey(keys[lo])
+	frac := h - float64(lo)
+	if frac == 0 {
+		return a
+	}
+	// The next order statistic is the smallest key to the right of lo
+	b := fromOrderKey(slices.Min(keys[lo+1:]))
+	// Between an infinity and anything else the line stays at the infinity,
+	// and between -Inf and +Inf it is undefined
+	switch {
+	case a == b:
+		return a
+	case a.IsInf(-1) && b.IsInf(1):
+		return QuietNaN
+	case a.IsInf(-1):
+		return a
+	case b.IsInf(1):
+		return b
+	}
+	return FromFloat64(a.ToFloat64() + frac*(b.ToFloat64()-a.ToFloat64()))
+}
+
+// Median returns the median of the non-NaN values in s, the mean of the two
+// middle values when their number is even; see Quantile
+func Median(s []Float16) Float16 {
+	return Quantile(s, 0.5)
+}
+
+// introselect reorders keys so that keys[k] holds the value it would have
+// if keys were sorted, with no larger key before it and no smaller key
+// after it. It is quickselect with median-of-three pivots that falls back
+// to sorting the remaining range once partitioning stops making progress,
+// bounding the worst case at O(n log n).
+func introselect(keys []uint16, k int) {
+	lo, hi := 0, len(keys)
+	budget := 2 * bits.Len(uint(len(keys)))
+	for hi-lo > 16 {
+		if budget == 0 {
+			slices.Sort(keys[lo:hi])
+			return
+		}
+		budget--
+
+		p := medianOfThree(keys, lo, lo+(hi-lo)/2, hi-1)
+		// Three-way partition: [lo, lt) < p, [lt, gt) == p, [gt, hi) > p
+		lt, i, gt := lo, lo, hi
+		for i < gt {
+			switch {
+			case keys[i] < p:
+				keys[lt], keys[i] = keys[i], keys[lt]
+				lt++
+				i++
+			case keys[i] > p:
+				gt--
+				keys[gt], keys[i] = keys[i], keys[gt]
+			default:
+				i++
+			}
+		}
+		switch {
+		case k < lt:
+			hi = lt
+		case k >= gt:
+			lo = gt
+		default:
+			return
+		}
+	}
+	slices.Sort(keys[lo:hi])
+}
+
+func medianOfThree(keys []uint16, a, b, c int) uint16 {
+	x, y, z := keys[a], keys[b], keys[c]
+	return max(min(x, y), min(max(x, y), z))
+}
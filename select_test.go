@@ -0,0 +1,218 @@
+package float16
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// sortedNonNaN returns the non-NaN values of s in ascending numeric order
+func sortedNonNaN(s []Float16) []float64 {
+	var v []float64
+	for _, f := range s {
+		if !f.IsNaN() {
+			v = append(v, f.ToFloat64())
+		}
+	}
+	slices.Sort(v)
+	return v
+}
+
+// quantileRef is the linear-interpolation quantile of sorted values
+func quantileRef(sorted []float64, q float64) Float16 {
+	h := q * float64(len(sorted)-1)
+	lo := int(h)
+	if lo == len(sorted)-1 || h == float64(lo) {
+		return FromFloat64(sorted[lo])
+	}
+	if math.IsInf(sorted[lo], -1) && !math.IsInf(sorted[lo+1], 1) {
+		return NegativeInfinity // the line from -Inf stays there
+	}
+	return FromFloat64(sorted[lo] + (h-float64(lo))*(sorted[lo+1]-sorted[lo]))
+}
+
+func selectionInputs() map[string][]Float16 {
+	rng := rand.New(rand.NewSource(1))
+	random := make([]Float16, 1001)
+	for i := range random {
+		random[i] = FromFloat64(rng.NormFloat64() * 100)
+	}
+	// Few distinct values, NaNs and signed zeros
+	dups := make([]Float16, 500)
+	choices := []Float16{QuietNaN, NegativeZero, PositiveZero, FromFloat32(-1), FromFloat32(2), NegativeQNaN}
+	for i := range dups {
+		dups[i] = choices[rng.Intn(len(choices))]
+	}
+	ascending := make([]Float16, 300)
+	for i := range ascending {
+		ascending[i] = FromFloat32(float32(i))
+	}
+	descending := slices.Clone(ascending)
+	slices.Reverse(descending)
+	organ := append(slices.Clone(ascending), descending...)
+	equal := make([]Float16, 200)
+	for i := range equal {
+		equal[i] = FromFloat32(7)
+	}
+
+	return map[string][]Float16{
+		"random":     random,
+		"dups":       dups,
+		"ascending":  ascending,
+		"descending": descending,
+		"organ pipe": organ,
+		"equal":      equal,
+		"one":        {FromFloat32(-3)},
+		"infinities": {PositiveInfinity, FromFloat32(1), NegativeInfinity, QuietNaN, FromFloat32(-4)},
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	qs := []float64{0, 0.01, 0.25, 0.5, 0.6, 0.75, 0.99, 1}
+	for name, s := range selectionInputs() {
+		orig := slices.Clone(s)
+		sorted := sortedNonNaN(s)
+		for _, q := range qs {
+			want := quantileRef(sorted, q)
+			if got := Quantile(s, q); got != want && !(got.IsNaN() && want.IsNaN()) &&
+				!(got.IsZero() && want.IsZero()) {
+				t.Errorf("%s: Quantile(%v) = %v, want %v", name, q, got, want)
+			}
+		}
+		if !slices.Equal(s, orig) {
+			t.Errorf("%s: Quantile modified its input", name)
+		}
+	}
+}
+
+func TestQuantileSpecialCases(t *testing.T) {
+	one, two := FromFloat32(1), FromFloat32(2)
+	tests := []struct {
+		name string
+		s    []Float16
+		q    float64
+		want Float16
+	}{
+		{"empty", nil, 0.5, QuietNaN},
+		{"all NaN", []Float16{QuietNaN, NegativeQNaN}, 0.5, QuietNaN},
+		{"q < 0", []Float16{one}, -0.1, QuietNaN},
+		{"q > 1", []Float16{one}, 1.5, QuietNaN},
+		{"q NaN", []Float16{one}, QuietNaN.ToFloat64(), QuietNaN},
+		{"even median", []Float16{two, one, QuietNaN}, 0.5, FromFloat32(1.5)},
+		{"-inf and finite", []Float16{NegativeInfinity, one}, 0.5, NegativeInfinity},
+		{"finite and +inf", []Float16{one, PositiveInfinity}, 0.5, PositiveInfinity},
+		{"-inf and +inf", []Float16{PositiveInfinity, NegativeInfinity}, 0.5, QuietNaN},
+		{"+inf twice", []Float16{PositiveInfinity, PositiveInfinity}, 0.5, PositiveInfinity},
+		{"max overflow", []Float16{MaxValue, MaxValue.Neg()}, 1, MaxValue},
+	}
+
+	for _, tt := range tests {
+		got := Quantile(tt.s, tt.q)
+		if got != tt.want && !(got.IsNaN() && tt.want.IsNaN()) {
+			t.Errorf("%s: Quantile() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if got := Median([]Float16{FromFloat32(5), one, FromFloat32(3)}); got != FromFloat32(3) {
+		t.Errorf("Median() = %v, want 3", got)
+	}
+}
+
+func TestArgMaxArgMin(t *testing.T) {
+	for name, s := range selectionInputs() {
+		sorted := sortedNonNaN(s)
+		gotMax, gotMin := ArgMax(s), ArgMin(s)
+		firstMax := slices.IndexFunc(s, func(f Float16) bool { return !f.IsNaN() && f.ToFloat64() == sorted[len(sorted)-1] })
+		firstMin := slices.IndexFunc(s, func(f Float16) bool { return !f.IsNaN() && f.ToFloat64() == sorted[0] })
+		if gotMax != firstMax {
+			t.Errorf("%s: ArgMax() = %d, want %d", name, gotMax, firstMax)
+		}
+		if gotMin != firstMin {
+			t.Errorf("%s: ArgMin() = %d, want %d", name, gotMin, firstMin)
+		}
+	}
+
+	for _, s := range [][]Float16{nil, {QuietNaN, NegativeQNaN}} {
+		if ArgMax(s) != -1 || ArgMin(s) != -1 {
+			t.Errorf("ArgMax(%v), ArgMin(%v) = %d, %d, want -1, -1", s, s, ArgMax(s), ArgMin(s))
+		}
+	}
+	// Signed zeros compare equal, so the first one wins
+	if got := ArgMax([]Float16{NegativeZero, PositiveZero}); got != 0 {
+		t.Errorf("ArgMax(-0, +0) = %d, want 0", got)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	for name, s := range selectionInputs() {
+		// Reference: stable sort of non-NaN indices by value, descending
+		var ref []int
+		for i, f := range s {
+			if !f.IsNaN() {
+				ref = append(ref, i)
+			}
+		}
+		slices.SortStableFunc(ref, func(i, j int) int {
+			a, b := s[i].ToFloat64(), s[j].ToFloat64()
+			switch {
+			case a > b:
+				return -1
+			case a < b:
+				return 1
+			}
+			return 0
+		})
+
+		for _, k := range []int{1, 5, 50, len(s), len(s) + 3} {
+			indices, values := TopK(s, k)
+			want := ref[:min(k, len(ref))]
+			if !slices.Equal(indices, want) {
+				t.Fatalf("%s: TopK(%d) indices = %v, want %v", name, k, indices, want)
+			}
+			for j, i := range indices {
+				if values[j] != s[i] {
+					t.Errorf("%s: TopK(%d) values[%d] = %v, want %v", name, k, j, values[j], s[i])
+				}
+			}
+		}
+	}
+
+	if indices, values := TopK([]Float16{FromFloat32(1)}, 0); indices != nil || values != nil {
+		t.Errorf("TopK(k=0) = %v, %v, want nil, nil", indices, values)
+	}
+}
+
+func TestOrderKey(t *testing.T) {
+	// Every non-NaN pair orders like its float64 values
+	var vals []Float16
+	for b := 0; b < 1<<16; b += 37 {
+		if f := FromBits(uint16(b)); !f.IsNaN() {
+			vals = append(vals, f)
+		}
+	}
+	vals = append(vals, PositiveInfinity, NegativeInfinity, NegativeZero, PositiveZero)
+	for _, a := range vals {
+		for _, b := range vals[:200] {
+			x, y := a.ToFloat64(), b.ToFloat64()
+			ka, kb := orderKey(a), orderKey(b)
+			if (x < y) != (ka < kb) || (x == y) != (ka == kb) {
+				t.Fatalf("orderKey(%v) = %#x, orderKey(%v) = %#x", a, ka, b, kb)
+			}
+		}
+		if a != NegativeZero && fromOrderKey(orderKey(a)) != a {
+			t.Fatalf("fromOrderKey(orderKey(%v)) = %v", a, fromOrderKey(orderKey(a)))
+		}
+	}
+}
+
+func BenchmarkTopK(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	logits := make([]Float16, 50000)
+	for i := range logits {
+		logits[i] = FromFloat64(rng.NormFloat64() * 4)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		TopK(logits, 50)
+	}
+}
@@ -0,0 +1,56 @@
+package float16
+
+// ToSlice16 converts a slice of float32 values to Float16 with FromFloat32
+func ToSlice16(s []float32) []Float16 {
+	result := make([]Float16, len(s))
+	for i, v := range s {
+		result[i] = FromFloat32(v)
+	}
+	return result
+}
+
+// ToSlice32 converts a slice of Float16 values to float32 with ToFloat32
+func ToSlice32(s []Float16) []float32 {
+	result := make([]float32, len(s))
+	for i, v := range s {
+		result[i] = v.ToFloat32()
+	}
+	return result
+}
+
+// ToSlice64 converts a slice of Float16 values to float64 with ToFloat64
+func ToSlice64(s []Float16) []float64 {
+	result := make([]float64, len(s))
+	for i, v := range s {
+		result[i] = v.ToFloat64()
+	}
+	return result
+}
+
+// FromSlice64 converts a slice of float64 values to Float16 with FromFloat64
+func FromSlice64(s []float64) []Float16 {
+	result := make([]Float16, len(s))
+	for i, v := range s {
+		result[i] = FromFloat64(v)
+	}
+	return result
+}
+
+// ToSlice16WithMode converts a slice of float32 values to Float16 with
+// FromFloat32WithMode. The errors are nil if every element converted
+// cleanly; otherwise errs[i] is the error, if any, for s[i].
+func ToSlice16WithMode(s []float32, convMode ConversionMode, rounding RoundingMode) ([]Float16, []error) {
+	result := make([]Float16, len(s))
+	var errs []error
+	for i, v := range s {
+		var err error
+		result[i], err = FromFloat32WithMode(v, convMode, rounding)
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(s))
+			}
+			errs[i] = err
+		}
+	}
+	return result, errs
+}
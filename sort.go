@@ -0,0 +1,170 @@
+package float16
+
+import "slices"
+
+// Sorting and searching. Sort, IsSorted, BinarySearch and Unique follow
+// totalOrder (see Compare): -0 sorts before +0, and NaNs sit at the ends by
+// sign, negative NaNs first and positive NaNs last, ordered by payload.
+// Every bit pattern is its own key, so a sorted slice is fully determined by
+// its contents. SortStable instead orders numerically, keeping -0 and +0
+// and all NaNs in their original order.
+//
+// The sorts are least-significant-digit radix sorts over the 16-bit key,
+// two counting passes of one byte each, so they take O(n) time and an
+// O(n) buffer; short slices use insertion sort.
+
+// insertionSortMax is the length up to which the sorts use insertion sort
+const insertionSortMax = 48
+
+// Sort sorts s in ascending totalOrder
+func Sort(s []Float16) {
+	keys := make([]uint16, len(s))
+	for i, v := range s {
+		keys[i] = totalKey(v)
+	}
+	radixSort16(keys)
+	for i, k := range keys {
+		s[i] = fromOrderKey(k)
+	}
+}
+
+// SortStable sorts s in ascending numeric order, with NaNs last. Values that
+// compare equal, -0 and +0 as well as NaNs of any sign and payload, keep
+// their relative order.
+func SortStable(s []Float16) {
+	// Pack the numeric key above the value; the sort compares keys only
+	packed := make([]uint32, len(s))
+	for i, v := range s {
+		packed[i] = uint32(stableKey(v))<<16 | uint32(v)
+	}
+	radixSortPacked(packed)
+	for i, p := range packed {
+		s[i] = Float16(p)
+	}
+}
+
+// stableKey orders non-NaN values numerically, with ±0 equal, and places
+// every NaN after +Inf
+func stableKey(f Float16) uint16 {
+	if f.IsNaN() {
+		return 0xFFFF
+	}
+	return orderKey(f)
+}
+
+// IsSorted reports whether s is in ascending totalOrder
+func IsSorted(s []Float16) bool {
+	for i := 1; i < len(s); i++ {
+		if totalKey(s[i]) < totalKey(s[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// BinarySearch searches for target in s, which must be sorted in totalOrder
+// as by Sort. It returns the position of the first element that does not
+// precede target and whether that element is target. Matches are by bit
+// pattern: searching for +0 does not find -0, and a NaN is found only with
+// the same sign and payload.
+func BinarySearch(s []Float16, target Float16) (int, bool) {
+	k := totalKey(target)
+	i, j := 0, len(s)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		if totalKey(s[h]) < k {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i, i < len(s) && s[i] == target
+}
+
+// Unique returns the distinct values of s in ascending totalOrder, leaving s
+// unchanged. Values are distinct when their bits differ, so -0 and +0 are
+// both kept, as are NaNs with different signs or payloads.
+func Unique(s []Float16) []Float16 {
+	u := slices.Clone(s)
+	Sort(u)
+	return slices.Clip(slices.Compact(u))
+}
+
+// radixSort16 sorts keys in ascending order
+func radixSort16(keys []uint16) {
+	if len(keys) <= insertionSortMax {
+		for i := 1; i < len(keys); i++ {
+			for j := i; j > 0 && keys[j] < keys[j-1]; j-- {
+				keys[j], keys[j-1] = keys[j-1], keys[j]
+			}
+		}
+		return
+	}
+
+	var count [2][256]int
+	for _, k := range keys {
+		count[0][k&0xFF]++
+		count[1][k>>8]++
+	}
+	buf := make([]uint16, len(keys))
+	src, dst := keys, buf
+	for pass, shift := range [2]uint{0, 8} {
+		if count[pass][src[0]>>shift&0xFF] == len(src) {
+			continue // every key has the same digit
+		}
+		offsets(&count[pass])
+		for _, k := range src {
+			d := k >> shift & 0xFF
+			dst[count[pass][d]] = k
+			count[pass][d]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &keys[0] {
+		copy(keys, src)
+	}
+}
+
+// radixSortPacked stably sorts packed by its upper 16 bits
+func radixSortPacked(packed []uint32) {
+	if len(packed) <= insertionSortMax {
+		for i := 1; i < len(packed); i++ {
+			for j := i; j > 0 && packed[j]>>16 < packed[j-1]>>16; j-- {
+				packed[j], packed[j-1] = packed[j-1], packed[j]
+			}
+		}
+		return
+	}
+
+	var count [2][256]int
+	for _, p := range packed {
+		count[0][p>>16&0xFF]++
+		count[1][p>>24]++
+	}
+	buf := make([]uint32, len(packed))
+	src, dst := packed, buf
+	for pass, shift := range [2]uint{16, 24} {
+		if count[pass][src[0]>>shift&0xFF] == len(src) {
+			continue
+		}
+		offsets(&count[pass])
+		for _, p := range src {
+			d := p >> shift & 0xFF
+			dst[count[pass][d]] = p
+			count[pass][d]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &packed[0] {
+		copy(packed, src)
+	}
+}
+
+// offsets turns digit counts into the starting position of each digit
+func offsets(count *[256]int) {
+	sum := 0
+	for d, c := range count {
+		count[d] = sum
+		sum += c
+	}
+}
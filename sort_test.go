@@ -0,0 +1,150 @@
+package float16
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func sortInputs() map[string][]Float16 {
+	inputs := selectionInputs()
+	inputs["order sample"] = orderSample()
+	inputs["empty"] = nil
+	rng := rand.New(rand.NewSource(2))
+	bitsOnly := make([]Float16, 5000)
+	for i := range bitsOnly {
+		bitsOnly[i] = FromBits(uint16(rng.Intn(1 << 16)))
+	}
+	inputs["random bits"] = bitsOnly
+	return inputs
+}
+
+func TestSort(t *testing.T) {
+	for name, s := range sortInputs() {
+		want := slices.Clone(s)
+		slices.SortFunc(want, Compare)
+		got := slices.Clone(s)
+		Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: Sort() disagrees with slices.SortFunc(Compare)", name)
+		}
+		if !IsSorted(got) {
+			t.Errorf("%s: IsSorted(Sort()) = false", name)
+		}
+		if len(s) > 1 && !slices.IsSortedFunc(s, Compare) && IsSorted(s) {
+			t.Errorf("%s: IsSorted() = true for an unsorted slice", name)
+		}
+	}
+
+	if IsSorted([]Float16{PositiveZero, NegativeZero}) {
+		t.Error("IsSorted(+0, -0) = true, want false")
+	}
+}
+
+func TestSortStable(t *testing.T) {
+	// Reference: stable sort by numeric value, NaNs last
+	cmp := func(a, b Float16) int {
+		switch {
+		case a.IsNaN() || b.IsNaN():
+			if a.IsNaN() == b.IsNaN() {
+				return 0
+			}
+			if a.IsNaN() {
+				return 1
+			}
+			return -1
+		case a.ToFloat64() < b.ToFloat64():
+			return -1
+		case a.ToFloat64() > b.ToFloat64():
+			return 1
+		}
+		return 0
+	}
+	for name, s := range sortInputs() {
+		want := slices.Clone(s)
+		slices.SortStableFunc(want, cmp)
+		got := slices.Clone(s)
+		SortStable(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: SortStable() disagrees with slices.SortStableFunc", name)
+		}
+	}
+
+	s := []Float16{QuietNaN, PositiveZero, NegativeQNaN, NegativeZero, FromFloat32(-1), PositiveZero}
+	SortStable(s)
+	want := []Float16{FromFloat32(-1), PositiveZero, NegativeZero, PositiveZero, QuietNaN, NegativeQNaN}
+	if !slices.Equal(s, want) {
+		t.Errorf("SortStable() = %v, want %v", s, want)
+	}
+}
+
+func TestBinarySearch(t *testing.T) {
+	s := orderSample()
+	Sort(s)
+	for _, target := range append(orderSample(), FromFloat32(1.5), FromFloat32(-1000)) {
+		want, wantFound := slices.BinarySearchFunc(s, target, Compare)
+		got, found := BinarySearch(s, target)
+		if got != want || found != wantFound {
+			t.Fatalf("BinarySearch(%#04x) = %d, %v, want %d, %v", uint16(target), got, found, want, wantFound)
+		}
+	}
+
+	zeros := []Float16{FromFloat32(-1), NegativeZero, FromFloat32(1)}
+	if i, found := BinarySearch(zeros, PositiveZero); i != 2 || found {
+		t.Errorf("BinarySearch(+0) = %d, %v, want 2, false", i, found)
+	}
+	if i, found := BinarySearch(nil, PositiveZero); i != 0 || found {
+		t.Errorf("BinarySearch(nil) = %d, %v, want 0, false", i, found)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	for name, s := range sortInputs() {
+		orig := slices.Clone(s)
+		want := slices.Clone(s)
+		slices.SortFunc(want, Compare)
+		want = slices.Compact(want)
+		if got := Unique(s); !slices.Equal(got, want) {
+			t.Errorf("%s: Unique() = %d values, want %d", name, len(got), len(want))
+		}
+		if !slices.Equal(s, orig) {
+			t.Errorf("%s: Unique modified its input", name)
+		}
+	}
+
+	s := []Float16{PositiveZero, QuietNaN, NegativeZero, QuietNaN, PositiveZero, NegativeQNaN}
+	want := []Float16{NegativeQNaN, NegativeZero, PositiveZero, QuietNaN}
+	if got := Unique(s); !slices.Equal(got, want) {
+		t.Errorf("Unique() = %v, want %v", got, want)
+	}
+}
+
+func benchmarkScores(n int) []Float16 {
+	rng := rand.New(rand.NewSource(1))
+	s := make([]Float16, n)
+	for i := range s {
+		s[i] = FromFloat64(rng.NormFloat64())
+	}
+	return s
+}
+
+func BenchmarkSort(b *testing.B) {
+	scores := benchmarkScores(1 << 20)
+	s := make([]Float16, len(scores))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(s, scores)
+		Sort(s)
+	}
+}
+
+func BenchmarkSortLess(b *testing.B) {
+	scores := benchmarkScores(1 << 20)
+	s := make([]Float16, len(scores))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(s, scores)
+		sort.Slice(s, func(i, j int) bool { return Less(s[i], s[j]) })
+	}
+}
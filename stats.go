@@ -0,0 +1,169 @@
+package float16
+
+import "math"
+
+// StatsAccumulator computes summary statistics of a stream of Float16
+// values without keeping them. The mean and variance use Welford's update in
+// float64, and accumulators filled separately, for example one per
+// goroutine, combine exactly with Merge.
+//
+// Sum, Mean and the variances cover the finite values only; NaNs and
+// infinities are counted separately, and infinities do take part in Min and
+// Max. The zero value is an empty accumulator ready to use. A
+// StatsAccumulator must not be used by several goroutines at once.
+type StatsAccumulator struct {
+	count     int // all values, including NaNs and infinities
+	nans      int
+	infs      int
+	zeros     int
+	subnormal int
+
+	min, max Float16 // valid once count > nans
+	sum      float64
+	mean     float64
+	m2       float64 // sum of squared deviations from the mean
+}
+
+// Add adds f to the accumulator
+func (a *StatsAccumulator) Add(f Float16) {
+	a.count++
+	if f.IsNaN() {
+		a.nans++
+		return
+	}
+	if a.count-a.nans == 1 || Less(f, a.min) {
+		a.min = f
+	}
+	if a.count-a.nans == 1 || Greater(f, a.max) {
+		a.max = f
+	}
+	if f.IsInf(0) {
+		a.infs++
+		return
+	}
+	if f.IsZero() {
+		a.zeros++
+	} else if f.IsSubnormal() {
+		a.subnormal++
+	}
+
+	x := f.ToFloat64()
+	n := float64(a.finite())
+	a.sum += x
+	delta := x - a.mean
+	a.mean += delta / n
+	a.m2 += delta * (x - a.mean)
+}
+
+// AddSlice adds every value of s to the accumulator
+func (a *StatsAccumulator) AddSlice(s []Float16) {
+	for _, f := range s {
+		a.Add(f)
+	}
+}
+
+// Merge adds the values summarized by b to a, as if they had been added to a
+// directly; b is unchanged
+func (a *StatsAccumulator) Merge(b *StatsAccumulator) {
+	if b.count-b.nans > 0 {
+		if a.count-a.nans == 0 || Less(b.min, a.min) {
+			a.min = b.min
+		}
+		if a.count-a.nans == 0 || Greater(b.max, a.max) {
+			a.max = b.max
+		}
+	}
+
+	// Chan, Golub and LeVeque's pairwise update of the mean and M2
+	na, nb := float64(a.finite()), float64(b.finite())
+	if nb > 0 {
+		n := na + nb
+		delta := b.mean - a.mean
+		a.mean += delta * nb / n
+		a.m2 += b.m2 + delta*delta*na*nb/n
+	}
+	a.sum += b.sum
+
+	a.count += b.count
+	a.nans += b.nans
+	a.infs += b.infs
+	a.zeros += b.zeros
+	a.subnormal += b.subnormal
+}
+
+// Reset empties the accumulator
+func (a *StatsAccumulator) Reset() {
+	*a = StatsAccumulator{}
+}
+
+// finite returns the number of finite values added
+func (a *StatsAccumulator) finite() int {
+	return a.count - a.nans - a.infs
+}
+
+// Count returns the number of values added, including NaNs and infinities
+func (a *StatsAccumulator) Count() int { return a.count }
+
+// NaNCount returns the number of NaNs added
+func (a *StatsAccumulator) NaNCount() int { return a.nans }
+
+// InfCount returns the number of infinities of either sign added
+func (a *StatsAccumulator) InfCount() int { return a.infs }
+
+// ZeroCount returns the number of zeros of either sign added
+func (a *StatsAccumulator) ZeroCount() int { return a.zeros }
+
+// SubnormalCount returns the number of non-zero subnormal values added
+func (a *StatsAccumulator) SubnormalCount() int { return a.subnormal }
+
+// Min returns the smallest non-NaN value added, or NaN if there is none
+func (a *StatsAccumulator) Min() Float16 {
+	if a.count == a.nans {
+		return QuietNaN
+	}
+	return a.min
+}
+
+// Max returns the largest non-NaN value added, or NaN if there is none
+func (a *StatsAccumulator) Max() Float16 {
+	if a.count == a.nans {
+		return QuietNaN
+	}
+	return a.max
+}
+
+// Sum returns the float64 sum of the finite values added
+func (a *StatsAccumulator) Sum() float64 { return a.sum }
+
+// Mean returns the mean of the finite values added, or NaN if there are
+// none
+func (a *StatsAccumulator) Mean() float64 {
+	if a.finite() == 0 {
+		return math.NaN()
+	}
+	return a.mean
+}
+
+// Variance returns the population variance of the finite values added, or
+// NaN if there are none
+func (a *StatsAccumulator) Variance() float64 {
+	if a.finite() == 0 {
+		return math.NaN()
+	}
+	return a.m2 / float64(a.finite())
+}
+
+// SampleVariance returns the unbiased sample variance of the finite values
+// added, or NaN if there are fewer than two
+func (a *StatsAccumulator) SampleVariance() float64 {
+	if a.finite() < 2 {
+		return math.NaN()
+	}
+	return a.m2 / float64(a.finite()-1)
+}
+
+// StdDev returns the population standard deviation of the finite values
+// added, or NaN if there are none
+func (a *StatsAccumulator) StdDev() float64 {
+	return math.Sqrt(a.Variance())
+}
@@ -0,0 +1,172 @@
+package float16
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// exactMoments returns the exact mean and population variance of the finite
+// values in s, rounded to float64
+func exactMoments(s []Float16) (mean, variance float64) {
+	sum := new(big.Float).SetPrec(4096)
+	n := 0
+	for _, v := range s {
+		if v.IsFinite() {
+			sum.Add(sum, big.NewFloat(v.ToFloat64()))
+			n++
+		}
+	}
+	m := new(big.Float).SetPrec(4096).Quo(sum, big.NewFloat(float64(n)))
+	ssq := new(big.Float).SetPrec(4096)
+	for _, v := range s {
+		if v.IsFinite() {
+			d := new(big.Float).SetPrec(4096).Sub(big.NewFloat(v.ToFloat64()), m)
+			ssq.Add(ssq, d.Mul(d, d))
+		}
+	}
+	mean, _ = m.Float64()
+	variance, _ = ssq.Quo(ssq, big.NewFloat(float64(n))).Float64()
+	return mean, variance
+}
+
+func closeRel(got, want, tol float64) bool {
+	return math.Abs(got-want) <= tol*math.Abs(want)
+}
+
+func TestStatsAccumulator(t *testing.T) {
+	s := []Float16{
+		FromFloat32(1), FromFloat32(-2), QuietNaN, PositiveZero, NegativeZero,
+		SmallestSubnormal, PositiveInfinity, FromFloat32(4), FromBits(0x8200), NegativeQNaN,
+	}
+	var acc StatsAccumulator
+	acc.AddSlice(s)
+
+	counts := []struct {
+		name      string
+		got, want int
+	}{
+		{"Count", acc.Count(), 10},
+		{"NaNCount", acc.NaNCount(), 2},
+		{"InfCount", acc.InfCount(), 1},
+		{"ZeroCount", acc.ZeroCount(), 2},
+		{"SubnormalCount", acc.SubnormalCount(), 2},
+	}
+	for _, c := range counts {
+		if c.got != c.want {
+			t.Errorf("%s() = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+
+	if got := acc.Min(); got != FromFloat32(-2) {
+		t.Errorf("Min() = %v, want -2", got)
+	}
+	if got := acc.Max(); got != PositiveInfinity {
+		t.Errorf("Max() = %v, want +Inf", got)
+	}
+
+	mean, variance := exactMoments(s)
+	wantSum := 1 - 2 + 4 + SmallestSubnormal.ToFloat64() + FromBits(0x8200).ToFloat64()
+	if got := acc.Sum(); got != wantSum {
+		t.Errorf("Sum() = %v, want %v", got, wantSum)
+	}
+	if got := acc.Mean(); !closeRel(got, mean, 1e-15) {
+		t.Errorf("Mean() = %v, want %v", got, mean)
+	}
+	if got := acc.Variance(); !closeRel(got, variance, 1e-15) {
+		t.Errorf("Variance() = %v, want %v", got, variance)
+	}
+	if got, want := acc.SampleVariance(), variance*7/6; !closeRel(got, want, 1e-15) {
+		t.Errorf("SampleVariance() = %v, want %v", got, want)
+	}
+	if got := acc.StdDev(); !closeRel(got, math.Sqrt(variance), 1e-15) {
+		t.Errorf("StdDev() = %v, want %v", got, math.Sqrt(variance))
+	}
+}
+
+func TestStatsAccumulatorEmpty(t *testing.T) {
+	var acc StatsAccumulator
+	acc.Add(QuietNaN)
+	if !acc.Min().IsNaN() || !acc.Max().IsNaN() {
+		t.Errorf("Min(), Max() = %v, %v, want NaN", acc.Min(), acc.Max())
+	}
+	if !math.IsNaN(acc.Mean()) || !math.IsNaN(acc.Variance()) || !math.IsNaN(acc.StdDev()) {
+		t.Errorf("Mean(), Variance(), StdDev() = %v, %v, %v, want NaN", acc.Mean(), acc.Variance(), acc.StdDev())
+	}
+	if acc.Sum() != 0 || acc.Count() != 1 {
+		t.Errorf("Sum(), Count() = %v, %d, want 0, 1", acc.Sum(), acc.Count())
+	}
+
+	acc.Add(FromFloat32(3))
+	if !math.IsNaN(acc.SampleVariance()) {
+		t.Errorf("SampleVariance() of one value = %v, want NaN", acc.SampleVariance())
+	}
+	if acc.Variance() != 0 || acc.Mean() != 3 {
+		t.Errorf("Mean(), Variance() = %v, %v, want 3, 0", acc.Mean(), acc.Variance())
+	}
+
+	acc.Reset()
+	if acc != (StatsAccumulator{}) {
+		t.Errorf("Reset() left %+v", acc)
+	}
+}
+
+func TestStatsAccumulatorMerge(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	s := make([]Float16, 20000)
+	for i := range s {
+		s[i] = FromFloat64(1000 + 3*rng.NormFloat64())
+	}
+	s[17] = QuietNaN
+	s[4000] = NegativeInfinity
+
+	var whole StatsAccumulator
+	whole.AddSlice(s)
+
+	// One accumulator per goroutine, including an empty one
+	bounds := []int{0, 0, 5000, 5001, 12000, len(s)}
+	parts := make([]StatsAccumulator, len(bounds)-1)
+	var wg sync.WaitGroup
+	for i := range parts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parts[i].AddSlice(s[bounds[i]:bounds[i+1]])
+		}()
+	}
+	wg.Wait()
+	var merged StatsAccumulator
+	for i := range parts {
+		merged.Merge(&parts[i])
+	}
+
+	mean, variance := exactMoments(s)
+	for _, acc := range []*StatsAccumulator{&whole, &merged} {
+		if !closeRel(acc.Mean(), mean, 1e-14) || !closeRel(acc.Variance(), variance, 1e-10) {
+			t.Errorf("Mean(), Variance() = %v, %v, want %v, %v", acc.Mean(), acc.Variance(), mean, variance)
+		}
+	}
+	if merged.Count() != whole.Count() || merged.NaNCount() != 1 || merged.InfCount() != 1 {
+		t.Errorf("merged counts = %d, %d, %d, want %d, 1, 1", merged.Count(), merged.NaNCount(), merged.InfCount(), whole.Count())
+	}
+	if merged.Min() != NegativeInfinity || merged.Max() != whole.Max() {
+		t.Errorf("merged Min(), Max() = %v, %v, want -Inf, %v", merged.Min(), merged.Max(), whole.Max())
+	}
+	if !closeRel(merged.Sum(), whole.Sum(), 1e-15) {
+		t.Errorf("merged Sum() = %v, want %v", merged.Sum(), whole.Sum())
+	}
+}
+
+func TestComputeSliceStatsLargeMean(t *testing.T) {
+	s := make([]Float16, 10000)
+	for i := range s {
+		s[i] = FromFloat32(3)
+	}
+	// A Float16 running sum stalls at 8192
+	stats := ComputeSliceStats(s)
+	if stats.Mean != FromFloat32(3) || stats.Sum != FromFloat32(30000) {
+		t.Errorf("Mean, Sum = %v, %v, want 3, 30000", stats.Mean, stats.Sum)
+	}
+}
@@ -0,0 +1,146 @@
+package float16
+
+import "math"
+
+// SumStrategy selects how SumSliceWith, DotProductWith and Norm2With
+// accumulate their terms.
+//
+// Let n be the number of terms, S = Σ|xᵢ| (Σ|aᵢbᵢ| for dot products) and u
+// the unit roundoff of the accumulator: 2^-11 for Float16, 2^-24 for
+// float32 and 2^-53 for float64. With round-to-nearest arithmetic the
+// computed sum differs from the exact one by at most
+//
+//	SumNaive       (n-1)·u·S
+//	SumPairwise    (⌈log₂ n⌉+4)·u·S
+//	SumKahan       (2u + O(n·u²))·S
+//	SumFloat32Acc  (n-1)·2^-24·S, plus half an ulp of the Float16 result
+//	SumFloat64Acc  (n-1)·2^-53·S, plus half an ulp of the Float16 result
+//
+// (Higham, Accuracy and Stability of Numerical Algorithms, §4). The
+// strategies that accumulate in Float16 round each product of a dot product
+// to Float16 first, which adds u·S to their bounds; float32 and float64 hold
+// the products of Float16 values exactly. Float16 values span 2^-24 to 2^16,
+// so SumFloat64Acc adds up to 2^13 of them exactly and returns the correctly
+// rounded sum.
+type SumStrategy int
+
+const (
+	// SumNaive adds each term to a Float16 accumulator in order, the classic
+	// loop whose sum stops growing once it passes 2048
+	SumNaive SumStrategy = iota
+	// SumPairwise adds the two halves of the slice recursively in Float16,
+	// in blocks of eight
+	SumPairwise
+	// SumKahan carries a Float16 compensation term that recovers the low
+	// bits lost by each addition
+	SumKahan
+	// SumFloat32Acc accumulates in float32 and rounds to Float16 once
+	SumFloat32Acc
+	// SumFloat64Acc accumulates in float64 and rounds to Float16 once; it
+	// is what SumSlice, DotProduct and Norm2 use
+	SumFloat64Acc
+)
+
+// pairwiseBlock is the length below which SumPairwise adds naively
+const pairwiseBlock = 8
+
+// SumSliceWith returns the sum of s accumulated with strategy
+func SumSliceWith(s []Float16, strategy SumStrategy) Float16 {
+	return FromFloat64(accumulate(len(s), func(i int) float64 { return s[i].ToFloat64() }, strategy))
+}
+
+// DotProductWith returns the sum of a[i] × b[i] accumulated with strategy
+func DotProductWith(a, b []Float16, strategy SumStrategy) Float16 {
+	if len(a) != len(b) {
+		panic("float16: slice length mismatch")
+	}
+	return FromFloat64(accumulate(len(a), func(i int) float64 { return a[i].ToFloat64() * b[i].ToFloat64() }, strategy))
+}
+
+// Norm2With returns the Euclidean norm of s with its squares summed using
+// strategy. As in LAPACK's nrm2, the values are first scaled by a power of
+// two near max|sᵢ|, so the squares are below 1 and neither overflow nor
+// lose the largest terms to underflow even when summed in Float16. The
+// relative error is about half the relative error of the sum of squares.
+// As with Hypot, an infinity gives +Inf even when s also holds a NaN.
+func Norm2With(s []Float16, strategy SumStrategy) Float16 {
+	var amax float64
+	nan := false
+	for _, v := range s {
+		switch {
+		case v.IsInf(0):
+			return PositiveInfinity // even with NaNs present, as in Hypot
+		case v.IsNaN():
+			nan = true
+		default:
+			amax = max(amax, math.Abs(v.ToFloat64()))
+		}
+	}
+	if nan {
+		return QuietNaN
+	}
+	if amax == 0 {
+		return PositiveZero
+	}
+
+	// |x|·2^-e < 1 for every x, and the scaling is exact in float64
+	_, e := math.Frexp(amax)
+	ssq := accumulate(len(s), func(i int) float64 {
+		x := math.Ldexp(s[i].ToFloat64(), -e)
+		return x * x
+	}, strategy)
+	return FromFloat64(math.Ldexp(math.Sqrt(ssq), e))
+}
+
+// accumulate returns the sum of term(i) for i in [0, n), in the precision
+// of the strategy's accumulator. The Float16 strategies round each term to
+// Float16 before adding it.
+func accumulate(n int, term func(int) float64, strategy SumStrategy) float64 {
+	switch strategy {
+	case SumNaive:
+		return sumNaive(0, n, term).ToFloat64()
+	case SumPairwise:
+		return sumPairwise(0, n, term).ToFloat64()
+	case SumKahan:
+		var sum, c Float16
+		for i := range n {
+			y := Sub(FromFloat64(term(i)), c)
+			t := Add(sum, y)
+			if t.IsFinite() {
+				c = Sub(Sub(t, sum), y)
+			} else {
+				c = 0 // Inf - Inf would poison the sum with NaN
+			}
+			sum = t
+		}
+		return sum.ToFloat64()
+	case SumFloat32Acc:
+		var sum float32
+		for i := range n {
+			sum += float32(term(i))
+		}
+		return float64(sum)
+	default:
+		var sum float64
+		for i := range n {
+			sum += term(i)
+		}
+		return sum
+	}
+}
+
+func sumNaive(lo, hi int, term func(int) float64) Float16 {
+	var sum Float16
+	for i := lo; i < hi; i++ {
+		sum = Add(sum, FromFloat64(term(i)))
+	}
+	return sum
+}
+
+func sumPairwise(lo, hi int, term func(int) float64) Float16 {
+	if hi-lo <= pairwiseBlock {
+		return sumNaive(lo, hi, term)
+	}
+	mid := lo + (hi-lo)/2
+	return Add(sumPairwise(lo, mid, term), sumPairwise(mid, hi, term))
+}
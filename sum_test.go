@@ -0,0 +1,234 @@
+package float16
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+var sumStrategies = []struct {
+	name     string
+	strategy SumStrategy
+}{
+	{"Naive", SumNaive},
+	{"Pairwise", SumPairwise},
+	{"Kahan", SumKahan},
+	{"Float32Acc", SumFloat32Acc},
+	{"Float64Acc", SumFloat64Acc},
+}
+
+// sumBound is the documented error bound of strategy for n terms whose
+// absolute values sum to abs, including the final rounding of result
+func sumBound(strategy SumStrategy, n int, abs float64, result Float16) float64 {
+	const u16 = 0x1p-11
+	half := math.Abs(result.ToFloat64()) * u16 // half an ulp, or more
+	m := float64(max(n-1, 0))
+	switch strategy {
+	case SumNaive:
+		return m * u16 * abs
+	case SumPairwise:
+		return (math.Ceil(math.Log2(float64(max(n, 1)))) + 4) * u16 * abs
+	case SumKahan:
+		return (2*u16 + 4*float64(n)*u16*u16) * abs
+	case SumFloat32Acc:
+		return m*0x1p-24*abs + half
+	default:
+		return m*0x1p-53*abs + half
+	}
+}
+
+// exactSum returns the exact sum of the terms and of their absolute values
+func exactSum(terms []*big.Float) (sum, abs *big.Float) {
+	sum, abs = new(big.Float).SetPrec(2048), new(big.Float).SetPrec(2048)
+	for _, t := range terms {
+		sum.Add(sum, t)
+		abs.Add(abs, new(big.Float).Abs(t))
+	}
+	return sum, abs
+}
+
+func randomHalfSlice(rng *rand.Rand, n int, gen func() float64) []Float16 {
+	s := make([]Float16, n)
+	for i := range s {
+		s[i] = FromFloat64(gen())
+	}
+	return s
+}
+
+func sumInputs() map[string][]Float16 {
+	rng := rand.New(rand.NewSource(1))
+	ones := make([]Float16, 5000)
+	for i := range ones {
+		ones[i] = FromFloat32(1)
+	}
+	return map[string][]Float16{
+		"ones":      ones,
+		"uniform":   randomHalfSlice(rng, 3000, rng.Float64),
+		"signed":    randomHalfSlice(rng, 2000, func() float64 { return 2*rng.Float64() - 1 }),
+		"wide":      randomHalfSlice(rng, 1000, func() float64 { return math.Ldexp(rng.NormFloat64(), rng.Intn(30)-20) }),
+		"short":     randomHalfSlice(rng, 7, rng.NormFloat64),
+		"subnormal": randomHalfSlice(rng, 500, func() float64 { return math.Ldexp(rng.Float64(), -15) }),
+	}
+}
+
+func TestSumSliceWithBounds(t *testing.T) {
+	for name, s := range sumInputs() {
+		terms := make([]*big.Float, len(s))
+		for i, v := range s {
+			terms[i] = big.NewFloat(v.ToFloat64())
+		}
+		sum, abs := exactSum(terms)
+		want, _ := sum.Float64()
+		absF, _ := abs.Float64()
+		for _, st := range sumStrategies {
+			got := SumSliceWith(s, st.strategy)
+			diff, _ := new(big.Float).Sub(big.NewFloat(got.ToFloat64()), sum).Float64()
+			if bound := sumBound(st.strategy, len(s), absF, got); math.Abs(diff) > bound {
+				t.Errorf("%s: SumSliceWith(%s) = %v, want %v within %g", name, st.name, got, want, bound)
+			}
+		}
+	}
+}
+
+func TestDotProductWithBounds(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for _, n := range []int{1, 10, 300, 4000} {
+		a := randomHalfSlice(rng, n, rng.NormFloat64)
+		b := randomHalfSlice(rng, n, rng.NormFloat64)
+		terms := make([]*big.Float, n)
+		for i := range a {
+			terms[i] = new(big.Float).Mul(big.NewFloat(a[i].ToFloat64()), big.NewFloat(b[i].ToFloat64()))
+		}
+		sum, abs := exactSum(terms)
+		absF, _ := abs.Float64()
+		for _, st := range sumStrategies {
+			got := DotProductWith(a, b, st.strategy)
+			bound := sumBound(st.strategy, n, absF, got)
+			if st.strategy < SumFloat32Acc {
+				bound += 0x1p-11 * absF // each product is rounded to Float16
+			}
+			diff, _ := new(big.Float).Sub(big.NewFloat(got.ToFloat64()), sum).Float64()
+			if math.Abs(diff) > bound {
+				t.Errorf("n=%d: DotProductWith(%s) = %v, off by %g, want within %g", n, st.name, got, diff, bound)
+			}
+		}
+	}
+}
+
+func TestSumFloat64AccCorrectlyRounded(t *testing.T) {
+	for name, s := range sumInputs() {
+		var sum big.Float
+		for _, v := range s {
+			sum.Add(&sum, big.NewFloat(v.ToFloat64()))
+		}
+		exact, _ := sum.SetPrec(2048).Float64() // exact: the span fits in 53 bits here
+		if got, want := SumSlice(s), FromFloat64(exact); got != want {
+			t.Errorf("%s: SumSlice() = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSumSliceLargeCount(t *testing.T) {
+	ones := make([]Float16, 4096)
+	for i := range ones {
+		ones[i] = FromFloat32(1)
+	}
+	// The Float16 accumulator stops at 2048, where adding 1 is a tie to even
+	if got := SumSliceWith(ones, SumNaive); got != FromFloat32(2048) {
+		t.Errorf("SumSliceWith(SumNaive) = %v, want 2048", got)
+	}
+	for _, st := range sumStrategies[1:] {
+		if got := SumSliceWith(ones, st.strategy); got != FromFloat32(4096) {
+			t.Errorf("SumSliceWith(%s) = %v, want 4096", st.name, got)
+		}
+	}
+}
+
+func TestSumSliceWithSpecialValues(t *testing.T) {
+	one := FromFloat32(1)
+	tests := []struct {
+		name string
+		s    []Float16
+		want Float16
+	}{
+		{"empty", nil, PositiveZero},
+		{"inf", []Float16{one, PositiveInfinity, one}, PositiveInfinity},
+		{"inf - inf", []Float16{PositiveInfinity, NegativeInfinity}, QuietNaN},
+		{"nan", []Float16{one, QuietNaN}, QuietNaN},
+		{"cancel", []Float16{MaxValue, one, MaxValue.Neg()}, one},
+	}
+
+	for _, tt := range tests {
+		for _, st := range sumStrategies {
+			if tt.name == "cancel" && st.strategy <= SumKahan {
+				continue // 65504 + 1 rounds back to 65504 in Float16
+			}
+			got := SumSliceWith(tt.s, st.strategy)
+			if got != tt.want && !(got.IsNaN() && tt.want.IsNaN()) {
+				t.Errorf("%s: SumSliceWith(%s) = %v, want %v", tt.name, st.name, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestNorm2With(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	tests := []struct {
+		name string
+		s    []Float16
+	}{
+		// The squares overflow Float16, which broke the half-precision Norm2
+		{"large", randomHalfSlice(rng, 100, func() float64 { return 5000 * rng.NormFloat64() })},
+		{"near max", []Float16{FromFloat32(40000), FromFloat32(-40000), FromFloat32(20000)}},
+		// The squares underflow Float16
+		{"tiny", randomHalfSlice(rng, 50, func() float64 { return 1e-5 * rng.NormFloat64() })},
+		{"subnormal", []Float16{SmallestSubnormal, SmallestSubnormal}},
+		{"mixed", randomHalfSlice(rng, 1000, rng.NormFloat64)},
+	}
+
+	for _, tt := range tests {
+		var ssq big.Float
+		ssq.SetPrec(2048)
+		for _, v := range tt.s {
+			x := big.NewFloat(v.ToFloat64())
+			ssq.Add(&ssq, new(big.Float).Mul(x, x))
+		}
+		want, _ := new(big.Float).SetPrec(64).Sqrt(&ssq).Float64()
+		for _, st := range sumStrategies {
+			got := Norm2With(tt.s, st.strategy)
+			// Half the sum bound with S = Σx², plus the square rounding for
+			// Float16 strategies and the final rounding
+			rel := sumBound(st.strategy, len(tt.s), 1, 0) / 2
+			if st.strategy < SumFloat32Acc {
+				rel += 0x1p-12
+			}
+			bound := rel*want + math.Abs(want)*0x1p-11 + 0x1p-25
+			if math.Abs(got.ToFloat64()-want) > bound {
+				t.Errorf("%s: Norm2With(%s) = %v, want %v within %g", tt.name, st.name, got, want, bound)
+			}
+		}
+	}
+}
+
+func TestNorm2SpecialValues(t *testing.T) {
+	one := FromFloat32(1)
+	tests := []struct {
+		name string
+		s    []Float16
+		want Float16
+	}{
+		{"empty", nil, PositiveZero},
+		{"zeros", []Float16{NegativeZero, PositiveZero}, PositiveZero},
+		{"3-4-5", []Float16{FromFloat32(3), FromFloat32(-4)}, FromFloat32(5)},
+		{"max", []Float16{MaxValue, MaxValue}, PositiveInfinity},
+		{"nan", []Float16{one, QuietNaN}, QuietNaN},
+		{"inf and nan", []Float16{QuietNaN, NegativeInfinity}, PositiveInfinity},
+	}
+
+	for _, tt := range tests {
+		if got := Norm2(tt.s); got != tt.want && !(got.IsNaN() && tt.want.IsNaN()) {
+			t.Errorf("%s: Norm2() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}